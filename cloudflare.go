@@ -287,59 +287,7 @@ func (api *API) makeRequestWithAuthTypeAndHeadersComplete(ctx context.Context, m
 	}
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		if strings.HasSuffix(resp.Request.URL.Path, "/filters/validate-expr") {
-			return nil, fmt.Errorf("%s", respBody)
-		}
-
-		if resp.StatusCode >= http.StatusInternalServerError {
-			return nil, &ServiceError{cloudflareError: &Error{
-				StatusCode: resp.StatusCode,
-				RayID:      resp.Header.Get("cf-ray"),
-				Errors: []ResponseInfo{{
-					Message: errInternalServiceError,
-				}},
-			}}
-		}
-
-		errBody := &Response{}
-		err = json.Unmarshal(respBody, &errBody)
-		if err != nil {
-			return nil, fmt.Errorf(errUnmarshalErrorBody+": %w", err)
-		}
-
-		errCodes := make([]int, 0, len(errBody.Errors))
-		errMsgs := make([]string, 0, len(errBody.Errors))
-		for _, e := range errBody.Errors {
-			errCodes = append(errCodes, e.Code)
-			errMsgs = append(errMsgs, e.Message)
-		}
-
-		err := &Error{
-			StatusCode:    resp.StatusCode,
-			RayID:         resp.Header.Get("cf-ray"),
-			Errors:        errBody.Errors,
-			ErrorCodes:    errCodes,
-			ErrorMessages: errMsgs,
-			Messages:      errBody.Messages,
-		}
-
-		switch resp.StatusCode {
-		case http.StatusUnauthorized:
-			err.Type = ErrorTypeAuthorization
-			return nil, &AuthorizationError{cloudflareError: err}
-		case http.StatusForbidden:
-			err.Type = ErrorTypeAuthentication
-			return nil, &AuthenticationError{cloudflareError: err}
-		case http.StatusNotFound:
-			err.Type = ErrorTypeNotFound
-			return nil, &NotFoundError{cloudflareError: err}
-		case http.StatusTooManyRequests:
-			err.Type = ErrorTypeRateLimit
-			return nil, &RatelimitError{cloudflareError: err}
-		default:
-			err.Type = ErrorTypeRequest
-			return nil, &RequestError{cloudflareError: err}
-		}
+		return nil, errorFromResponse(resp, respBody)
 	}
 
 	return &APIResponse{
@@ -350,6 +298,88 @@ func (api *API) makeRequestWithAuthTypeAndHeadersComplete(ctx context.Context, m
 	}, nil
 }
 
+// makeRequestStream makes a single request without retries and returns the
+// body of a successful response unread, for responses too large to buffer.
+// The caller is responsible for closing it.
+func (api *API) makeRequestStream(ctx context.Context, method, uri string, body io.Reader, headers http.Header) (io.ReadCloser, error) {
+	if err := api.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("error caused by request rate limiting: %w", err)
+	}
+
+	resp, err := api.request(ctx, method, uri, body, api.authType, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not read response body: %w", err)
+		}
+		return nil, errorFromResponse(resp, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// errorFromResponse converts an unsuccessful API response and its body into
+// the matching error type.
+func errorFromResponse(resp *http.Response, respBody []byte) error {
+	if strings.HasSuffix(resp.Request.URL.Path, "/filters/validate-expr") {
+		return fmt.Errorf("%s", respBody)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &ServiceError{cloudflareError: &Error{
+			StatusCode: resp.StatusCode,
+			RayID:      resp.Header.Get("cf-ray"),
+			Errors: []ResponseInfo{{
+				Message: errInternalServiceError,
+			}},
+		}}
+	}
+
+	errBody := &Response{}
+	if err := json.Unmarshal(respBody, &errBody); err != nil {
+		return fmt.Errorf(errUnmarshalErrorBody+": %w", err)
+	}
+
+	errCodes := make([]int, 0, len(errBody.Errors))
+	errMsgs := make([]string, 0, len(errBody.Errors))
+	for _, e := range errBody.Errors {
+		errCodes = append(errCodes, e.Code)
+		errMsgs = append(errMsgs, e.Message)
+	}
+
+	err := &Error{
+		StatusCode:    resp.StatusCode,
+		RayID:         resp.Header.Get("cf-ray"),
+		Errors:        errBody.Errors,
+		ErrorCodes:    errCodes,
+		ErrorMessages: errMsgs,
+		Messages:      errBody.Messages,
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		err.Type = ErrorTypeAuthorization
+		return &AuthorizationError{cloudflareError: err}
+	case http.StatusForbidden:
+		err.Type = ErrorTypeAuthentication
+		return &AuthenticationError{cloudflareError: err}
+	case http.StatusNotFound:
+		err.Type = ErrorTypeNotFound
+		return &NotFoundError{cloudflareError: err}
+	case http.StatusTooManyRequests:
+		err.Type = ErrorTypeRateLimit
+		return &RatelimitError{cloudflareError: err}
+	default:
+		err.Type = ErrorTypeRequest
+		return &RequestError{cloudflareError: err}
+	}
+}
+
 // request makes a HTTP request to the given API endpoint, returning the raw
 // *http.Response, or an error if one occurred. The caller is responsible for
 // closing the response body.
@@ -364,16 +394,7 @@ func (api *API) request(ctx context.Context, method, uri string, reqBody io.Read
 	copyHeader(combinedHeaders, headers)
 	req.Header = combinedHeaders
 
-	if authType&AuthKeyEmail != 0 {
-		req.Header.Set("X-Auth-Key", api.APIKey)
-		req.Header.Set("X-Auth-Email", api.APIEmail)
-	}
-	if authType&AuthUserService != 0 {
-		req.Header.Set("X-Auth-User-Service-Key", api.APIUserServiceKey)
-	}
-	if authType&AuthToken != 0 {
-		req.Header.Set("Authorization", "Bearer "+api.APIToken)
-	}
+	api.setAuthHeaders(req.Header, authType)
 
 	if api.UserAgent != "" {
 		req.Header.Set("User-Agent", api.UserAgent)
@@ -416,6 +437,20 @@ func (api *API) request(ctx context.Context, method, uri string, reqBody io.Read
 	return resp, nil
 }
 
+// setAuthHeaders sets the credentials of authType on h.
+func (api *API) setAuthHeaders(h http.Header, authType int) {
+	if authType&AuthKeyEmail != 0 {
+		h.Set("X-Auth-Key", api.APIKey)
+		h.Set("X-Auth-Email", api.APIEmail)
+	}
+	if authType&AuthUserService != 0 {
+		h.Set("X-Auth-User-Service-Key", api.APIUserServiceKey)
+	}
+	if authType&AuthToken != 0 {
+		h.Set("Authorization", "Bearer "+api.APIToken)
+	}
+}
+
 // copyHeader copies all headers for `source` and sets them on `target`.
 // based on https://godoc.org/github.com/golang/gddo/httputil/header#Copy
 func copyHeader(target, source http.Header) {
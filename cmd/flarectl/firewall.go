@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/cwlowder/cloudflare-go"
 	"github.com/urfave/cli/v2"
@@ -310,6 +313,120 @@ func firewallAccessRuleDelete(c *cli.Context) error {
 	return nil
 }
 
+// accessRulesScope returns the resource container of the access rules of
+// the --zone or --account flags, or of the user.
+func accessRulesScope(c *cli.Context) (*cloudflare.ResourceContainer, error) {
+	accountID, zoneID, err := getScope(c)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case accountID != "":
+		return cloudflare.AccountIdentifier(accountID), nil
+	case zoneID != "":
+		return cloudflare.ZoneIdentifier(zoneID), nil
+	default:
+		return cloudflare.UserIdentifier(""), nil
+	}
+}
+
+// accessRulesFormat returns the --format flag, or the format matching the
+// extension of the --file flag.
+func accessRulesFormat(c *cli.Context) cloudflare.AccessRulesFormat {
+	if c.String("format") != "" {
+		return cloudflare.AccessRulesFormat(c.String("format"))
+	}
+	if strings.EqualFold(filepath.Ext(c.String("file")), ".json") {
+		return cloudflare.AccessRulesFormatJSON
+	}
+	return cloudflare.AccessRulesFormatCSV
+}
+
+func firewallAccessRulesImport(c *cli.Context) error {
+	if err := checkFlags(c, "file", "owner"); err != nil {
+		return err
+	}
+	rc, err := accessRulesScope(c)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if file := c.String("file"); file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("error opening access rules file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	rules, err := cloudflare.ReadAccessRules(r, accessRulesFormat(c))
+	if err != nil {
+		return fmt.Errorf("error reading access rules: %w", err)
+	}
+
+	result, err := api.SyncAccessRules(context.Background(), rc, cloudflare.SyncAccessRulesParams{
+		Owner:        c.String("owner"),
+		Rules:        rules,
+		KeepUnlisted: !c.Bool("prune"),
+		DryRun:       c.Bool("dry-run"),
+		Concurrency:  c.Int("concurrency"),
+	})
+	if err != nil {
+		return fmt.Errorf("error importing access rules: %w", err)
+	}
+
+	output := make([][]string, 0, len(result.Results))
+	for _, r := range result.Results {
+		status := "ok"
+		if c.Bool("dry-run") {
+			status = "planned"
+		}
+		if r.Err != nil {
+			status = r.Err.Error()
+		}
+		output = append(output, append([]string{string(r.Action)}, append(formatAccessRule(r.Rule), status)...))
+	}
+	writeTable(c, output, "Action", "ID", "Value", "Scope", "Mode", "Notes", "Status")
+	fmt.Fprintf(os.Stderr, "%d rules unchanged\n", result.Unchanged)
+
+	return result.Err()
+}
+
+func firewallAccessRulesExport(c *cli.Context) error {
+	rc, err := accessRulesScope(c)
+	if err != nil {
+		return err
+	}
+
+	filter := cloudflare.AccessRule{Notes: c.String("notes")}
+	listed, err := api.ListAllAccessRules(context.Background(), rc, filter)
+	if err != nil {
+		return fmt.Errorf("error listing access rules: %w", err)
+	}
+
+	// Leave out rules inherited from the account or user, which would be
+	// imported back as rules of the zone.
+	rules := make([]cloudflare.AccessRule, 0, len(listed))
+	for _, rule := range listed {
+		if cloudflare.AccessRuleInScope(rc, rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if file := c.String("file"); file != "" && file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("error creating access rules file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return cloudflare.WriteAccessRules(w, accessRulesFormat(c), rules)
+}
+
 func getScope(c *cli.Context) (string, string, error) {
 	var account, accountID string
 	if c.String("account") != "" {
@@ -674,6 +674,75 @@ func main() {
 								},
 							},
 						},
+						{
+							Name:    "import",
+							Aliases: []string{"i"},
+							Action:  firewallAccessRulesImport,
+							Usage:   "Create, update and delete the firewall access rules owned by --owner to match a CSV or JSON file",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								&cli.StringFlag{
+									Name:  "account",
+									Usage: "account name",
+								},
+								&cli.StringFlag{
+									Name:  "file",
+									Usage: "file to import, - for stdin",
+								},
+								&cli.StringFlag{
+									Name:  "format",
+									Usage: "file format ( csv | json ), inferred from the file extension by default",
+								},
+								&cli.StringFlag{
+									Name:  "owner",
+									Usage: "notes prefix of the rules managed by the import",
+								},
+								&cli.BoolFlag{
+									Name:  "prune",
+									Usage: "delete owned rules missing from the file",
+								},
+								&cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "print the changes without making them",
+								},
+								&cli.IntFlag{
+									Name:  "concurrency",
+									Usage: "number of rules changed at once",
+									Value: 4,
+								},
+							},
+						},
+						{
+							Name:    "export",
+							Aliases: []string{"e"},
+							Action:  firewallAccessRulesExport,
+							Usage:   "Export firewall access rules to a CSV or JSON file",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "zone",
+									Usage: "zone name",
+								},
+								&cli.StringFlag{
+									Name:  "account",
+									Usage: "account name",
+								},
+								&cli.StringFlag{
+									Name:  "file",
+									Usage: "file to write, stdout by default",
+								},
+								&cli.StringFlag{
+									Name:  "format",
+									Usage: "file format ( csv | json ), inferred from the file extension by default",
+								},
+								&cli.StringFlag{
+									Name:  "notes",
+									Usage: "rule notes",
+								},
+							},
+						},
 						{
 							Name:    "delete",
 							Aliases: []string{"d"},
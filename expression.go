@@ -0,0 +1,853 @@
+package cloudflare
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidExpression is wrapped by every ExpressionError so parse and
+// validation failures can be matched with errors.Is.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// ExpressionPosition is a location in the source of an expression. Offset is
+// in bytes, Line and Column start at 1.
+type ExpressionPosition struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func expressionPosition(src string, offset int) ExpressionPosition {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	line := 1 + strings.Count(src[:offset], "\n")
+	column := offset + 1
+	if i := strings.LastIndex(src[:offset], "\n"); i >= 0 {
+		column = offset - i
+	}
+	return ExpressionPosition{Offset: offset, Line: line, Column: column}
+}
+
+// ExpressionError is a syntax or type error in a rules language expression.
+type ExpressionError struct {
+	Position ExpressionPosition
+	Message  string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Position.Line, e.Position.Column, e.Message)
+}
+
+func (e *ExpressionError) Unwrap() error {
+	return ErrInvalidExpression
+}
+
+// ExpressionOperator is the canonical name of an operator of the rules
+// language. Symbolic forms such as "==" and "&&" are parsed into these.
+type ExpressionOperator string
+
+const (
+	ExpressionOperatorAnd            ExpressionOperator = "and"
+	ExpressionOperatorOr             ExpressionOperator = "or"
+	ExpressionOperatorXor            ExpressionOperator = "xor"
+	ExpressionOperatorNot            ExpressionOperator = "not"
+	ExpressionOperatorEqual          ExpressionOperator = "eq"
+	ExpressionOperatorNotEqual       ExpressionOperator = "ne"
+	ExpressionOperatorLess           ExpressionOperator = "lt"
+	ExpressionOperatorLessEqual      ExpressionOperator = "le"
+	ExpressionOperatorGreater        ExpressionOperator = "gt"
+	ExpressionOperatorGreaterEqual   ExpressionOperator = "ge"
+	ExpressionOperatorContains       ExpressionOperator = "contains"
+	ExpressionOperatorMatches        ExpressionOperator = "matches"
+	ExpressionOperatorIn             ExpressionOperator = "in"
+	ExpressionOperatorWildcard       ExpressionOperator = "wildcard"
+	ExpressionOperatorStrictWildcard ExpressionOperator = "strict wildcard"
+)
+
+var expressionOperatorAliases = map[string]ExpressionOperator{
+	"and": ExpressionOperatorAnd, "&&": ExpressionOperatorAnd,
+	"or": ExpressionOperatorOr, "||": ExpressionOperatorOr,
+	"xor": ExpressionOperatorXor, "^^": ExpressionOperatorXor,
+	"not": ExpressionOperatorNot, "!": ExpressionOperatorNot,
+	"eq": ExpressionOperatorEqual, "==": ExpressionOperatorEqual,
+	"ne": ExpressionOperatorNotEqual, "!=": ExpressionOperatorNotEqual,
+	"lt": ExpressionOperatorLess, "<": ExpressionOperatorLess,
+	"le": ExpressionOperatorLessEqual, "<=": ExpressionOperatorLessEqual,
+	"gt": ExpressionOperatorGreater, ">": ExpressionOperatorGreater,
+	"ge": ExpressionOperatorGreaterEqual, ">=": ExpressionOperatorGreaterEqual,
+	"contains": ExpressionOperatorContains,
+	"matches":  ExpressionOperatorMatches, "~": ExpressionOperatorMatches,
+	"in":       ExpressionOperatorIn,
+	"wildcard": ExpressionOperatorWildcard,
+}
+
+// ExpressionNode is a node of a parsed expression.
+type ExpressionNode interface {
+	// Pos is the offset of the node in the source.
+	Pos() int
+	// String formats the node on one line.
+	String() string
+}
+
+// ExpressionLogical combines two boolean expressions with and, or or xor.
+type ExpressionLogical struct {
+	Offset      int
+	Operator    ExpressionOperator
+	Left, Right ExpressionNode
+}
+
+// ExpressionNot negates a boolean expression.
+type ExpressionNot struct {
+	Offset int
+	X      ExpressionNode
+}
+
+// ExpressionGroup is an expression in parentheses.
+type ExpressionGroup struct {
+	Offset int
+	X      ExpressionNode
+}
+
+// ExpressionComparison compares a field or function call with a value.
+// Operator is empty for a bare boolean field or function call.
+type ExpressionComparison struct {
+	Offset   int
+	Left     ExpressionNode
+	Operator ExpressionOperator
+	Right    ExpressionNode
+}
+
+// ExpressionIndex accesses a map key, an array element or, with Wildcard,
+// every element of an array.
+type ExpressionIndex struct {
+	Key      *string
+	Index    *int
+	Wildcard bool
+}
+
+// ExpressionField is a reference to a request field, such as
+// http.request.headers["accept"][0].
+type ExpressionField struct {
+	Offset  int
+	Name    string
+	Indexes []ExpressionIndex
+}
+
+// ExpressionFunction is a function call such as lower(http.host).
+type ExpressionFunction struct {
+	Offset int
+	Name   string
+	Args   []ExpressionNode
+}
+
+// ExpressionLiteralKind is the kind of value of a literal.
+type ExpressionLiteralKind string
+
+const (
+	ExpressionLiteralString   ExpressionLiteralKind = "string"
+	ExpressionLiteralInt      ExpressionLiteralKind = "int"
+	ExpressionLiteralIntRange ExpressionLiteralKind = "int range"
+	ExpressionLiteralIP       ExpressionLiteralKind = "ip"
+	ExpressionLiteralBool     ExpressionLiteralKind = "bool"
+)
+
+// ExpressionLiteral is a literal value. Value holds a string, an int64, a
+// [2]int64 for ranges, a net.IP, *net.IPNet or ExpressionIPRange, or a bool
+// depending on Kind.
+type ExpressionLiteral struct {
+	Offset int
+	Kind   ExpressionLiteralKind
+	Raw    string
+	Value  interface{}
+}
+
+// ExpressionIPRange is an inclusive range of IP addresses of the same family,
+// such as 192.0.2.1..192.0.2.10. Ranges can only be used in lists.
+type ExpressionIPRange struct {
+	From net.IP
+	To   net.IP
+}
+
+// Contains reports whether ip is within the range.
+func (r ExpressionIPRange) Contains(ip net.IP) bool {
+	if (ip.To4() == nil) != (r.From.To4() == nil) {
+		return false
+	}
+	return bytes.Compare(ip.To16(), r.From.To16()) >= 0 && bytes.Compare(ip.To16(), r.To.To16()) <= 0
+}
+
+// ExpressionList is an inline list of literals, or a reference to a named
+// list when Name is set.
+type ExpressionList struct {
+	Offset int
+	Items  []*ExpressionLiteral
+	Name   string
+}
+
+func (n *ExpressionLogical) Pos() int    { return n.Offset }
+func (n *ExpressionNot) Pos() int        { return n.Offset }
+func (n *ExpressionGroup) Pos() int      { return n.Offset }
+func (n *ExpressionComparison) Pos() int { return n.Offset }
+func (n *ExpressionField) Pos() int      { return n.Offset }
+func (n *ExpressionFunction) Pos() int   { return n.Offset }
+func (n *ExpressionLiteral) Pos() int    { return n.Offset }
+func (n *ExpressionList) Pos() int       { return n.Offset }
+
+func (n *ExpressionLogical) String() string {
+	return n.Left.String() + " " + string(n.Operator) + " " + n.Right.String()
+}
+
+func (n *ExpressionNot) String() string { return "not " + n.X.String() }
+
+func (n *ExpressionGroup) String() string { return "(" + n.X.String() + ")" }
+
+func (n *ExpressionComparison) String() string {
+	if n.Operator == "" {
+		return n.Left.String()
+	}
+	return n.Left.String() + " " + string(n.Operator) + " " + n.Right.String()
+}
+
+func (n *ExpressionField) String() string {
+	var b strings.Builder
+	b.WriteString(n.Name)
+	for _, idx := range n.Indexes {
+		switch {
+		case idx.Wildcard:
+			b.WriteString("[*]")
+		case idx.Key != nil:
+			b.WriteString("[" + quoteExpressionString(*idx.Key) + "]")
+		case idx.Index != nil:
+			b.WriteString("[" + strconv.Itoa(*idx.Index) + "]")
+		}
+	}
+	return b.String()
+}
+
+func (n *ExpressionFunction) String() string {
+	args := make([]string, 0, len(n.Args))
+	for _, arg := range n.Args {
+		args = append(args, arg.String())
+	}
+	return n.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (n *ExpressionLiteral) String() string {
+	switch n.Kind {
+	case ExpressionLiteralString:
+		if strings.HasPrefix(n.Raw, "r") {
+			return n.Raw
+		}
+		return quoteExpressionString(n.Value.(string))
+	case ExpressionLiteralInt:
+		return strconv.FormatInt(n.Value.(int64), 10)
+	case ExpressionLiteralIntRange:
+		r := n.Value.([2]int64)
+		return strconv.FormatInt(r[0], 10) + ".." + strconv.FormatInt(r[1], 10)
+	case ExpressionLiteralBool:
+		return strconv.FormatBool(n.Value.(bool))
+	}
+	return n.Raw
+}
+
+func (n *ExpressionList) String() string {
+	if n.Name != "" {
+		return "$" + n.Name
+	}
+	items := make([]string, 0, len(n.Items))
+	for _, item := range n.Items {
+		items = append(items, item.String())
+	}
+	return "{" + strings.Join(items, " ") + "}"
+}
+
+func quoteExpressionString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Expression is a parsed rules language expression, as used by filters,
+// firewall rules, ruleset rules, Gateway rules and waiting room rules.
+type Expression struct {
+	Root   ExpressionNode
+	source string
+}
+
+// ParseExpression parses an expression of the Cloudflare rules language
+// (wirefilter). Only the syntax is checked; Validate also checks the fields,
+// functions and types used.
+//
+// Language reference: https://developers.cloudflare.com/ruleset-engine/rules-language/
+func ParseExpression(expression string) (*Expression, error) {
+	p := &expressionParser{src: expression}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != expressionTokenEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+	return &Expression{Root: root, source: expression}, nil
+}
+
+// String formats the expression on one line, with canonical operator names.
+func (e *Expression) String() string {
+	return e.Root.String()
+}
+
+// Format formats the expression with each operand of and, or and xor on its
+// own line and the contents of parentheses indented.
+func (e *Expression) Format() string {
+	return formatExpressionNode(e.Root, "")
+}
+
+// FormatExpression parses and formats an expression, see Expression.Format.
+func FormatExpression(expression string) (string, error) {
+	e, err := ParseExpression(expression)
+	if err != nil {
+		return "", err
+	}
+	return e.Format(), nil
+}
+
+func formatExpressionNode(n ExpressionNode, indent string) string {
+	switch n := n.(type) {
+	case *ExpressionLogical:
+		return formatExpressionNode(n.Left, indent) + "\n" + indent + string(n.Operator) + " " + formatExpressionNode(n.Right, indent)
+	case *ExpressionNot:
+		return "not " + formatExpressionNode(n.X, indent)
+	case *ExpressionGroup:
+		if _, ok := n.X.(*ExpressionLogical); !ok {
+			return "(" + formatExpressionNode(n.X, indent) + ")"
+		}
+		inner := indent + "  "
+		return "(\n" + inner + formatExpressionNode(n.X, inner) + "\n" + indent + ")"
+	}
+	return n.String()
+}
+
+// Walk calls fn for every node of the expression, parents before children.
+// Children of a node are skipped when fn returns false.
+func (e *Expression) Walk(fn func(ExpressionNode) bool) {
+	walkExpression(e.Root, fn)
+}
+
+func walkExpression(n ExpressionNode, fn func(ExpressionNode) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	switch n := n.(type) {
+	case *ExpressionLogical:
+		walkExpression(n.Left, fn)
+		walkExpression(n.Right, fn)
+	case *ExpressionNot:
+		walkExpression(n.X, fn)
+	case *ExpressionGroup:
+		walkExpression(n.X, fn)
+	case *ExpressionComparison:
+		walkExpression(n.Left, fn)
+		if n.Right != nil {
+			walkExpression(n.Right, fn)
+		}
+	case *ExpressionFunction:
+		for _, arg := range n.Args {
+			walkExpression(arg, fn)
+		}
+	case *ExpressionList:
+		for _, item := range n.Items {
+			walkExpression(item, fn)
+		}
+	}
+}
+
+type expressionTokenKind int
+
+const (
+	expressionTokenEOF expressionTokenKind = iota
+	expressionTokenIdent
+	expressionTokenString
+	expressionTokenInt
+	expressionTokenIntRange
+	expressionTokenIP
+	expressionTokenList
+	expressionTokenPunct
+)
+
+type expressionToken struct {
+	kind  expressionTokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+func (t expressionToken) String() string {
+	if t.kind == expressionTokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+var expressionPunctuation = []string{"==", "!=", "<=", ">=", "&&", "||", "^^", "(", ")", "{", "}", "[", "]", ",", "*", "<", ">", "~", "!"}
+
+type expressionParser struct {
+	src  string
+	toks []expressionToken
+	i    int
+}
+
+func (p *expressionParser) errorf(pos int, format string, args ...interface{}) error {
+	return &ExpressionError{Position: expressionPosition(p.src, pos), Message: fmt.Sprintf(format, args...)}
+}
+
+func isExpressionWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == ':' || c == '/' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *expressionParser) lex() error {
+	src := p.src
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '"':
+			s, end, err := p.lexString(i)
+			if err != nil {
+				return err
+			}
+			p.toks = append(p.toks, expressionToken{kind: expressionTokenString, text: src[i:end], value: s, pos: i})
+			i = end
+			continue
+		case c == 'r' && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '#'):
+			s, end, err := p.lexRawString(i)
+			if err != nil {
+				return err
+			}
+			p.toks = append(p.toks, expressionToken{kind: expressionTokenString, text: src[i:end], value: s, pos: i})
+			i = end
+			continue
+		case c == '$':
+			end := i + 1
+			for end < len(src) && isExpressionWordByte(src[end]) && src[end] != ':' && src[end] != '/' {
+				end++
+			}
+			if end == i+1 {
+				return p.errorf(i, "expected list name after $")
+			}
+			p.toks = append(p.toks, expressionToken{kind: expressionTokenList, text: src[i+1 : end], pos: i})
+			i = end
+			continue
+		case isExpressionWordByte(c) && c != '.' && c != '/' && (c != ':' || strings.HasPrefix(src[i:], "::")),
+			c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			end := i + 1
+			for end < len(src) && isExpressionWordByte(src[end]) {
+				end++
+			}
+			tok, err := p.word(src[i:end], i)
+			if err != nil {
+				return err
+			}
+			p.toks = append(p.toks, tok)
+			i = end
+			continue
+		}
+
+		matched := false
+		for _, punct := range expressionPunctuation {
+			if strings.HasPrefix(src[i:], punct) {
+				p.toks = append(p.toks, expressionToken{kind: expressionTokenPunct, text: punct, pos: i})
+				i += len(punct)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return p.errorf(i, "unexpected character %q", c)
+		}
+	}
+	p.toks = append(p.toks, expressionToken{kind: expressionTokenEOF, pos: len(src)})
+	return nil
+}
+
+// word classifies a run of identifier-like characters as a field or keyword,
+// an integer or integer range, or an IP address, CIDR or IP range.
+func (p *expressionParser) word(w string, pos int) (expressionToken, error) {
+	tok := expressionToken{text: w, pos: pos}
+
+	if lo, hi, ok := strings.Cut(w, ".."); ok {
+		if from, err := strconv.ParseInt(lo, 0, 64); err == nil {
+			to, err := strconv.ParseInt(hi, 0, 64)
+			if err != nil || from > to {
+				return tok, p.errorf(pos, "invalid integer range %q", w)
+			}
+			tok.kind, tok.value = expressionTokenIntRange, [2]int64{from, to}
+			return tok, nil
+		}
+
+		r := ExpressionIPRange{From: net.ParseIP(lo), To: net.ParseIP(hi)}
+		if r.From == nil || r.To == nil || (r.From.To4() == nil) != (r.To.To4() == nil) || bytes.Compare(r.From.To16(), r.To.To16()) > 0 {
+			return tok, p.errorf(pos, "invalid IP range %q", w)
+		}
+		tok.kind, tok.value = expressionTokenIP, r
+		return tok, nil
+	}
+
+	if strings.Contains(w, ":") || (w[0] >= '0' && w[0] <= '9' && strings.Count(w, ".") == 3) {
+		if strings.Contains(w, "/") {
+			_, network, err := net.ParseCIDR(w)
+			if err != nil {
+				return tok, p.errorf(pos, "invalid CIDR %q", w)
+			}
+			tok.kind, tok.value = expressionTokenIP, network
+			return tok, nil
+		}
+		ip := net.ParseIP(w)
+		if ip == nil {
+			return tok, p.errorf(pos, "invalid IP address %q", w)
+		}
+		tok.kind, tok.value = expressionTokenIP, ip
+		return tok, nil
+	}
+
+	if w[0] == '-' || (w[0] >= '0' && w[0] <= '9') {
+		n, err := strconv.ParseInt(w, 0, 64)
+		if err != nil {
+			return tok, p.errorf(pos, "invalid integer %q", w)
+		}
+		tok.kind, tok.value = expressionTokenInt, n
+		return tok, nil
+	}
+
+	if strings.Contains(w, "/") || strings.HasSuffix(w, ".") || strings.Contains(w, "..") {
+		return tok, p.errorf(pos, "invalid field name %q", w)
+	}
+	tok.kind = expressionTokenIdent
+	return tok, nil
+}
+
+func (p *expressionParser) lexString(start int) (string, int, error) {
+	var b strings.Builder
+	i := start + 1
+	for i < len(p.src) {
+		c := p.src[i]
+		switch c {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(p.src) {
+				return "", 0, p.errorf(i, "unterminated escape sequence")
+			}
+			switch p.src[i+1] {
+			case '"', '\\':
+				b.WriteByte(p.src[i+1])
+				i += 2
+			case 'x':
+				if i+4 > len(p.src) {
+					return "", 0, p.errorf(i, `invalid \x escape`)
+				}
+				v, err := strconv.ParseUint(p.src[i+2:i+4], 16, 8)
+				if err != nil {
+					return "", 0, p.errorf(i, `invalid \x escape`)
+				}
+				b.WriteByte(byte(v))
+				i += 4
+			default:
+				return "", 0, p.errorf(i, "invalid escape sequence \\%c", p.src[i+1])
+			}
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, p.errorf(start, "unterminated string")
+}
+
+func (p *expressionParser) lexRawString(start int) (string, int, error) {
+	i := start + 1
+	hashes := 0
+	for i < len(p.src) && p.src[i] == '#' {
+		hashes++
+		i++
+	}
+	if i >= len(p.src) || p.src[i] != '"' {
+		return "", 0, p.errorf(start, `expected " to start raw string`)
+	}
+	terminator := `"` + strings.Repeat("#", hashes)
+	end := strings.Index(p.src[i+1:], terminator)
+	if end < 0 {
+		return "", 0, p.errorf(start, "unterminated raw string")
+	}
+	return p.src[i+1 : i+1+end], i + 1 + end + len(terminator), nil
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.toks[p.i]
+}
+
+func (p *expressionParser) next() expressionToken {
+	tok := p.toks[p.i]
+	if tok.kind != expressionTokenEOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *expressionParser) is(kind expressionTokenKind, text string) bool {
+	tok := p.peek()
+	return tok.kind == kind && tok.text == text
+}
+
+func (p *expressionParser) expect(text string) error {
+	if tok := p.next(); tok.kind != expressionTokenPunct || tok.text != text {
+		return p.errorf(tok.pos, "expected %q, got %s", text, tok)
+	}
+	return nil
+}
+
+// logicalOperator returns the logical operator at the current token, if any.
+func (p *expressionParser) logicalOperator() ExpressionOperator {
+	tok := p.peek()
+	if tok.kind != expressionTokenIdent && tok.kind != expressionTokenPunct {
+		return ""
+	}
+	switch op := expressionOperatorAliases[tok.text]; op {
+	case ExpressionOperatorAnd, ExpressionOperatorOr, ExpressionOperatorXor:
+		return op
+	}
+	return ""
+}
+
+func (p *expressionParser) parseOr() (ExpressionNode, error) {
+	return p.parseLogical(ExpressionOperatorOr, p.parseXor)
+}
+
+func (p *expressionParser) parseXor() (ExpressionNode, error) {
+	return p.parseLogical(ExpressionOperatorXor, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (ExpressionNode, error) {
+	return p.parseLogical(ExpressionOperatorAnd, p.parseUnary)
+}
+
+func (p *expressionParser) parseLogical(op ExpressionOperator, operand func() (ExpressionNode, error)) (ExpressionNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.logicalOperator() == op {
+		pos := p.next().pos
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &ExpressionLogical{Offset: pos, Operator: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (ExpressionNode, error) {
+	tok := p.peek()
+	if (tok.kind == expressionTokenIdent && tok.text == "not") || (tok.kind == expressionTokenPunct && tok.text == "!") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ExpressionNot{Offset: tok.pos, X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *expressionParser) parsePrimary() (ExpressionNode, error) {
+	tok := p.peek()
+
+	if tok.kind == expressionTokenPunct && tok.text == "(" {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &ExpressionGroup{Offset: tok.pos, X: x}, nil
+	}
+
+	if tok.kind != expressionTokenIdent {
+		return nil, p.errorf(tok.pos, "expected field or function, got %s", tok)
+	}
+	if _, ok := expressionOperatorAliases[tok.text]; ok || tok.text == "strict" {
+		return nil, p.errorf(tok.pos, "expected field or function, got operator %s", tok)
+	}
+
+	if tok.text == "true" || tok.text == "false" {
+		p.next()
+		return &ExpressionComparison{Offset: tok.pos, Left: &ExpressionLiteral{
+			Offset: tok.pos, Kind: ExpressionLiteralBool, Raw: tok.text, Value: tok.text == "true",
+		}}, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := p.comparisonOperator()
+	if err != nil || op == "" {
+		return &ExpressionComparison{Offset: tok.pos, Left: left}, err
+	}
+
+	var right ExpressionNode
+	if op == ExpressionOperatorIn {
+		right, err = p.parseList()
+	} else {
+		right, err = p.parseLiteral()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ExpressionComparison{Offset: tok.pos, Left: left, Operator: op, Right: right}, nil
+}
+
+// parseOperand parses a field or function call.
+func (p *expressionParser) parseOperand() (ExpressionNode, error) {
+	tok := p.next()
+
+	if p.is(expressionTokenPunct, "(") {
+		p.next()
+		fn := &ExpressionFunction{Offset: tok.pos, Name: tok.text}
+		for !p.is(expressionTokenPunct, ")") {
+			if len(fn.Args) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.parseArgument()
+			if err != nil {
+				return nil, err
+			}
+			fn.Args = append(fn.Args, arg)
+		}
+		p.next()
+		return fn, nil
+	}
+
+	field := &ExpressionField{Offset: tok.pos, Name: tok.text}
+	for p.is(expressionTokenPunct, "[") {
+		p.next()
+		idx := p.next()
+		switch {
+		case idx.kind == expressionTokenString:
+			key := idx.value.(string)
+			field.Indexes = append(field.Indexes, ExpressionIndex{Key: &key})
+		case idx.kind == expressionTokenInt && idx.value.(int64) >= 0:
+			n := int(idx.value.(int64))
+			field.Indexes = append(field.Indexes, ExpressionIndex{Index: &n})
+		case idx.kind == expressionTokenPunct && idx.text == "*":
+			field.Indexes = append(field.Indexes, ExpressionIndex{Wildcard: true})
+		default:
+			return nil, p.errorf(idx.pos, "expected string key, index or * in brackets, got %s", idx)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *expressionParser) parseArgument() (ExpressionNode, error) {
+	switch p.peek().kind {
+	case expressionTokenString, expressionTokenInt, expressionTokenIP:
+		return p.parseLiteral()
+	}
+	return p.parseOr()
+}
+
+func (p *expressionParser) comparisonOperator() (ExpressionOperator, error) {
+	tok := p.peek()
+	if tok.kind != expressionTokenIdent && tok.kind != expressionTokenPunct {
+		return "", nil
+	}
+
+	if tok.text == "strict" {
+		p.next()
+		if next := p.next(); next.text != "wildcard" {
+			return "", p.errorf(next.pos, "expected wildcard after strict, got %s", next)
+		}
+		return ExpressionOperatorStrictWildcard, nil
+	}
+
+	switch op := expressionOperatorAliases[tok.text]; op {
+	case "", ExpressionOperatorAnd, ExpressionOperatorOr, ExpressionOperatorXor, ExpressionOperatorNot:
+		return "", nil
+	default:
+		p.next()
+		return op, nil
+	}
+}
+
+func (p *expressionParser) parseLiteral() (*ExpressionLiteral, error) {
+	tok := p.next()
+	lit := &ExpressionLiteral{Offset: tok.pos, Raw: tok.text, Value: tok.value}
+	switch tok.kind {
+	case expressionTokenString:
+		lit.Kind = ExpressionLiteralString
+	case expressionTokenInt:
+		lit.Kind = ExpressionLiteralInt
+	case expressionTokenIntRange:
+		lit.Kind = ExpressionLiteralIntRange
+	case expressionTokenIP:
+		lit.Kind = ExpressionLiteralIP
+	default:
+		return nil, p.errorf(tok.pos, "expected a value, got %s", tok)
+	}
+	return lit, nil
+}
+
+func (p *expressionParser) parseList() (*ExpressionList, error) {
+	tok := p.peek()
+	if tok.kind == expressionTokenList {
+		p.next()
+		return &ExpressionList{Offset: tok.pos, Name: tok.text}, nil
+	}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	list := &ExpressionList{Offset: tok.pos}
+	for !p.is(expressionTokenPunct, "}") {
+		item, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+	p.next()
+	return list, nil
+}
@@ -0,0 +1,674 @@
+package cloudflare
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// ErrExpressionFunctionUnsupported is returned when evaluating an expression
+// calling a function which can only be evaluated by Cloudflare.
+var ErrExpressionFunctionUnsupported = errors.New("function can't be evaluated locally")
+
+// ExpressionContext holds the request an expression is evaluated against.
+type ExpressionContext struct {
+	// Fields are the values of fields keyed by field name. Values are
+	// strings, ints, bools, net.IP (or a string holding one), []string,
+	// []int or map[string][]string depending on the type of the field.
+	// Fields which aren't set have the zero value of their type.
+	Fields map[string]interface{}
+
+	// Lists are the items of named lists referenced as $name. Items of IP
+	// lists can be addresses or CIDRs.
+	Lists map[string][]string
+}
+
+// NewExpressionContext returns a context holding the fields which can be
+// derived from r. Fields depending on Cloudflare, such as cf.threat_score or
+// ip.src.country, can be added to Fields afterwards.
+func NewExpressionContext(r *http.Request) ExpressionContext {
+	headers := make(map[string][]string, len(r.Header))
+	var names, values []string
+	for name, vs := range r.Header {
+		headers[strings.ToLower(name)] = vs
+	}
+	for _, name := range sortedExpressionKeys(headers) {
+		for _, v := range headers[name] {
+			names = append(names, name)
+			values = append(values, v)
+		}
+	}
+
+	query := r.URL.Query()
+	args := make(map[string][]string, len(query))
+	var argNames, argValues []string
+	for name, vs := range query {
+		args[name] = vs
+	}
+	for _, name := range sortedExpressionKeys(args) {
+		for _, v := range args[name] {
+			argNames = append(argNames, name)
+			argValues = append(argValues, v)
+		}
+	}
+
+	cookies := map[string][]string{}
+	for _, c := range r.Cookies() {
+		cookies[c.Name] = append(cookies[c.Name], c.Value)
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	scheme := "http"
+	if r.TLS != nil || r.URL.Scheme == "https" {
+		scheme = "https"
+	}
+
+	uri := r.URL.RequestURI()
+	extension := strings.TrimPrefix(path.Ext(r.URL.Path), ".")
+
+	ip := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(ip); err == nil {
+		ip = h
+	}
+
+	fields := map[string]interface{}{
+		"http.cookie":                     r.Header.Get("Cookie"),
+		"http.host":                       host,
+		"http.referer":                    r.Referer(),
+		"http.request.full_uri":           scheme + "://" + host + uri,
+		"http.request.method":             r.Method,
+		"http.request.uri":                uri,
+		"http.request.uri.path":           r.URL.Path,
+		"http.request.uri.path.extension": strings.ToLower(extension),
+		"http.request.uri.query":          r.URL.RawQuery,
+		"http.request.version":            r.Proto,
+		"http.user_agent":                 r.UserAgent(),
+		"http.x_forwarded_for":            r.Header.Get("X-Forwarded-For"),
+		"http.request.headers":            headers,
+		"http.request.headers.names":      names,
+		"http.request.headers.values":     values,
+		"http.request.cookies":            cookies,
+		"http.request.uri.args":           args,
+		"http.request.uri.args.names":     argNames,
+		"http.request.uri.args.values":    argValues,
+		"ssl":                             scheme == "https",
+	}
+	if parsed := net.ParseIP(ip); parsed != nil {
+		fields["ip.src"] = parsed
+	}
+
+	return ExpressionContext{Fields: fields}
+}
+
+func sortedExpressionKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// expressionElements are the values of an expression evaluated once per
+// element of an array accessed with [*].
+type expressionElements []interface{}
+
+// EvaluateExpression validates an expression and evaluates it against ctx,
+// see Expression.Evaluate.
+func EvaluateExpression(expression string, ctx ExpressionContext) (bool, error) {
+	e, err := ParseExpression(expression)
+	if err != nil {
+		return false, err
+	}
+	return e.Evaluate(ctx)
+}
+
+// Evaluate reports whether the expression matches the request in ctx. It is
+// meant for testing rules; results can differ from Cloudflare for fields
+// which depend on how Cloudflare normalises requests.
+func (e *Expression) Evaluate(ctx ExpressionContext) (bool, error) {
+	if err := e.Validate(); err != nil {
+		return false, err
+	}
+
+	ev := &expressionEvaluator{ctx: ctx, regexps: map[string]*regexp.Regexp{}}
+	v, err := ev.eval(e.Root)
+	if err != nil {
+		return false, err
+	}
+	b, _ := v.(bool)
+	return b, nil
+}
+
+type expressionEvaluator struct {
+	ctx     ExpressionContext
+	regexps map[string]*regexp.Regexp
+}
+
+func (ev *expressionEvaluator) regexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := ev.regexps[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	ev.regexps[pattern] = re
+	return re, nil
+}
+
+// eachExpressionValue applies fn to v, or to every element of v when it is
+// evaluated per element of an array.
+func eachExpressionValue(v interface{}, fn func(interface{}) (interface{}, error)) (interface{}, error) {
+	elems, ok := v.(expressionElements)
+	if !ok {
+		return fn(v)
+	}
+	out := make(expressionElements, len(elems))
+	for i, elem := range elems {
+		r, err := fn(elem)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = r
+	}
+	return out, nil
+}
+
+func (ev *expressionEvaluator) eval(n ExpressionNode) (interface{}, error) {
+	switch n := n.(type) {
+	case *ExpressionGroup:
+		return ev.eval(n.X)
+
+	case *ExpressionLiteral:
+		return n.Value, nil
+
+	case *ExpressionNot:
+		x, err := ev.eval(n.X)
+		if err != nil {
+			return nil, err
+		}
+		return eachExpressionValue(x, func(v interface{}) (interface{}, error) { return !v.(bool), nil })
+
+	case *ExpressionLogical:
+		return ev.evalLogical(n)
+
+	case *ExpressionField:
+		return ev.evalField(n)
+
+	case *ExpressionFunction:
+		return ev.evalFunction(n)
+
+	case *ExpressionComparison:
+		left, err := ev.eval(n.Left)
+		if err != nil || n.Operator == "" {
+			return left, err
+		}
+		return eachExpressionValue(left, func(v interface{}) (interface{}, error) {
+			return ev.compare(v, n.Operator, n.Right)
+		})
+	}
+	return nil, fmt.Errorf("unexpected expression node %T", n)
+}
+
+func (ev *expressionEvaluator) evalLogical(n *ExpressionLogical) (interface{}, error) {
+	combine := func(l, r bool) bool {
+		switch n.Operator {
+		case ExpressionOperatorAnd:
+			return l && r
+		case ExpressionOperatorOr:
+			return l || r
+		}
+		return l != r
+	}
+
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := left.(bool); ok {
+		if (n.Operator == ExpressionOperatorAnd && !l) || (n.Operator == ExpressionOperatorOr && l) {
+			return l, nil
+		}
+	}
+
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	le, lok := left.(expressionElements)
+	re, rok := right.(expressionElements)
+	if !lok || !rok {
+		return combine(left.(bool), right.(bool)), nil
+	}
+	if len(le) != len(re) {
+		return nil, fmt.Errorf("%s of [*] comparisons over arrays of different lengths", n.Operator)
+	}
+	out := make(expressionElements, len(le))
+	for i := range le {
+		out[i] = combine(le[i].(bool), re[i].(bool))
+	}
+	return out, nil
+}
+
+func (ev *expressionEvaluator) evalField(n *ExpressionField) (interface{}, error) {
+	t := expressionFields[n.Name]
+	v, err := normalizeExpressionValue(ev.ctx.Fields[n.Name], t)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", n.Name, err)
+	}
+
+	for _, idx := range n.Indexes {
+		idx := idx
+		v, err = eachExpressionValue(v, func(v interface{}) (interface{}, error) {
+			return indexExpressionValue(v, idx), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// normalizeExpressionValue converts a field value from an ExpressionContext
+// to the representation used during evaluation.
+func normalizeExpressionValue(v interface{}, t ExpressionType) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		switch t {
+		case ExpressionTypeBytes:
+			return "", nil
+		case ExpressionTypeInt:
+			return int64(0), nil
+		case ExpressionTypeBoolean:
+			return false, nil
+		}
+		return nil, nil
+	case int:
+		return int64(v), nil
+	case []int:
+		out := make([]int64, len(v))
+		for i, n := range v {
+			out[i] = int64(n)
+		}
+		return out, nil
+	case http.Header:
+		return map[string][]string(v), nil
+	case string:
+		if t == ExpressionTypeIP {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", v)
+			}
+			return ip, nil
+		}
+	}
+	return v, nil
+}
+
+func indexExpressionValue(v interface{}, idx ExpressionIndex) interface{} {
+	switch v := v.(type) {
+	case map[string][]string:
+		switch {
+		case idx.Wildcard:
+			var elems expressionElements
+			for _, k := range sortedExpressionKeys(v) {
+				elems = append(elems, v[k])
+			}
+			return elems
+		case idx.Key != nil:
+			if vs, ok := v[*idx.Key]; ok {
+				return vs
+			}
+		}
+	case []string:
+		switch {
+		case idx.Wildcard:
+			elems := make(expressionElements, len(v))
+			for i, s := range v {
+				elems[i] = s
+			}
+			return elems
+		case idx.Index != nil && *idx.Index < len(v):
+			return v[*idx.Index]
+		}
+	case []int64:
+		switch {
+		case idx.Wildcard:
+			elems := make(expressionElements, len(v))
+			for i, n := range v {
+				elems[i] = n
+			}
+			return elems
+		case idx.Index != nil && *idx.Index < len(v):
+			return v[*idx.Index]
+		}
+	}
+	// Missing keys and elements don't match any comparison.
+	return nil
+}
+
+func (ev *expressionEvaluator) evalFunction(n *ExpressionFunction) (interface{}, error) {
+	args := make([]interface{}, len(n.Args))
+	for i, arg := range n.Args {
+		v, err := ev.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	if n.Name == "any" || n.Name == "all" {
+		elems, _ := args[0].(expressionElements)
+		for _, elem := range elems {
+			if elem.(bool) == (n.Name == "any") {
+				return n.Name == "any", nil
+			}
+		}
+		return n.Name == "all", nil
+	}
+
+	// Functions of a value accessed with [*] are applied to each element.
+	for i, arg := range args {
+		elems, ok := arg.(expressionElements)
+		if !ok {
+			continue
+		}
+		out := make(expressionElements, len(elems))
+		for j, elem := range elems {
+			elemArgs := append([]interface{}{}, args...)
+			elemArgs[i] = elem
+			r, err := ev.call(n.Name, elemArgs)
+			if err != nil {
+				return nil, err
+			}
+			out[j] = r
+		}
+		return out, nil
+	}
+	return ev.call(n.Name, args)
+}
+
+func expressionString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func (ev *expressionEvaluator) call(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "lower":
+		return strings.ToLower(expressionString(args[0])), nil
+	case "upper":
+		return strings.ToUpper(expressionString(args[0])), nil
+	case "len":
+		switch v := args[0].(type) {
+		case string:
+			return int64(len(v)), nil
+		case []string:
+			return int64(len(v)), nil
+		case []int64:
+			return int64(len(v)), nil
+		case map[string][]string:
+			return int64(len(v)), nil
+		}
+		return int64(0), nil
+	case "starts_with":
+		return strings.HasPrefix(expressionString(args[0]), expressionString(args[1])), nil
+	case "ends_with":
+		return strings.HasSuffix(expressionString(args[0]), expressionString(args[1])), nil
+	case "concat":
+		var b strings.Builder
+		for _, arg := range args {
+			b.WriteString(expressionString(arg))
+		}
+		return b.String(), nil
+	case "to_string":
+		switch v := args[0].(type) {
+		case nil:
+			return "", nil
+		case net.IP:
+			return v.String(), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case "url_decode":
+		s := expressionString(args[0])
+		recursive := len(args) > 1 && strings.Contains(expressionString(args[1]), "r")
+		for {
+			decoded, err := url.PathUnescape(s)
+			if err != nil || decoded == s {
+				return s, nil
+			}
+			s = decoded
+			if !recursive {
+				return s, nil
+			}
+		}
+	case "remove_bytes":
+		remove := expressionString(args[1])
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(remove, r) {
+				return -1
+			}
+			return r
+		}, expressionString(args[0])), nil
+	case "regex_replace":
+		re, err := ev.regexp(expressionString(args[1]))
+		if err != nil {
+			return nil, err
+		}
+		s := expressionString(args[0])
+		loc := re.FindStringSubmatchIndex(s)
+		if loc == nil {
+			return s, nil
+		}
+		replaced := re.ExpandString(nil, expressionString(args[2]), s, loc)
+		return s[:loc[0]] + string(replaced) + s[loc[1]:], nil
+	case "decode_base64":
+		decoded, err := base64.StdEncoding.DecodeString(expressionString(args[0]))
+		if err != nil {
+			return "", nil
+		}
+		return string(decoded), nil
+	case "lookup_json_string", "lookup_json_integer":
+		v := lookupExpressionJSON(expressionString(args[0]), args[1:])
+		if name == "lookup_json_string" {
+			s, _ := v.(string)
+			return s, nil
+		}
+		if f, ok := v.(float64); ok && f == float64(int64(f)) {
+			return int64(f), nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrExpressionFunctionUnsupported, name)
+}
+
+func lookupExpressionJSON(doc string, path []interface{}) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return nil
+	}
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = obj[k]
+		case int64:
+			arr, ok := v.([]interface{})
+			if !ok || k < 0 || int(k) >= len(arr) {
+				return nil
+			}
+			v = arr[k]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func (ev *expressionEvaluator) compare(left interface{}, op ExpressionOperator, right ExpressionNode) (interface{}, error) {
+	if list, ok := right.(*ExpressionList); ok {
+		return ev.in(left, list)
+	}
+	value := right.(*ExpressionLiteral).Value
+
+	switch l := left.(type) {
+	case string:
+		r := value.(string)
+		switch op {
+		case ExpressionOperatorEqual:
+			return l == r, nil
+		case ExpressionOperatorNotEqual:
+			return l != r, nil
+		case ExpressionOperatorLess:
+			return l < r, nil
+		case ExpressionOperatorLessEqual:
+			return l <= r, nil
+		case ExpressionOperatorGreater:
+			return l > r, nil
+		case ExpressionOperatorGreaterEqual:
+			return l >= r, nil
+		case ExpressionOperatorContains:
+			return strings.Contains(l, r), nil
+		case ExpressionOperatorMatches:
+			re, err := ev.regexp(r)
+			if err != nil {
+				return nil, err
+			}
+			return re.MatchString(l), nil
+		case ExpressionOperatorWildcard:
+			return matchExpressionWildcard(r, l, false), nil
+		case ExpressionOperatorStrictWildcard:
+			return matchExpressionWildcard(r, l, true), nil
+		}
+
+	case int64:
+		r := value.(int64)
+		switch op {
+		case ExpressionOperatorEqual:
+			return l == r, nil
+		case ExpressionOperatorNotEqual:
+			return l != r, nil
+		case ExpressionOperatorLess:
+			return l < r, nil
+		case ExpressionOperatorLessEqual:
+			return l <= r, nil
+		case ExpressionOperatorGreater:
+			return l > r, nil
+		case ExpressionOperatorGreaterEqual:
+			return l >= r, nil
+		}
+
+	case net.IP:
+		matched := false
+		switch r := value.(type) {
+		case net.IP:
+			matched = l.Equal(r)
+		case *net.IPNet:
+			matched = r.Contains(l)
+		}
+		if op == ExpressionOperatorNotEqual {
+			return !matched, nil
+		}
+		return matched, nil
+	}
+
+	// Missing values don't match.
+	return false, nil
+}
+
+func (ev *expressionEvaluator) in(left interface{}, list *ExpressionList) (bool, error) {
+	if list.Name != "" {
+		for _, item := range ev.ctx.Lists[list.Name] {
+			switch l := left.(type) {
+			case string:
+				if l == item {
+					return true, nil
+				}
+			case int64:
+				if strconv.FormatInt(l, 10) == item {
+					return true, nil
+				}
+			case net.IP:
+				if _, network, err := net.ParseCIDR(item); err == nil && network.Contains(l) {
+					return true, nil
+				}
+				if ip := net.ParseIP(item); ip != nil && ip.Equal(l) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
+	for _, item := range list.Items {
+		switch r := item.Value.(type) {
+		case string:
+			if left == r {
+				return true, nil
+			}
+		case int64:
+			if left == r {
+				return true, nil
+			}
+		case [2]int64:
+			if l, ok := left.(int64); ok && l >= r[0] && l <= r[1] {
+				return true, nil
+			}
+		case net.IP:
+			if l, ok := left.(net.IP); ok && l.Equal(r) {
+				return true, nil
+			}
+		case *net.IPNet:
+			if l, ok := left.(net.IP); ok && r.Contains(l) {
+				return true, nil
+			}
+		case ExpressionIPRange:
+			if l, ok := left.(net.IP); ok && r.Contains(l) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// matchExpressionWildcard matches s against a pattern where * matches any
+// sequence of characters and \* and \\ match a literal * and \.
+func matchExpressionWildcard(pattern, s string, caseSensitive bool) bool {
+	var b strings.Builder
+	b.WriteString("^")
+	if !caseSensitive {
+		b.WriteString("(?i)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '*':
+			b.WriteString("(?s:.*)")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()).MatchString(s)
+}
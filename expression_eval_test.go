@@ -0,0 +1,79 @@
+package cloudflare
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "https://www.example.com/api/v1/users.json?q=a&q=b&page=2", nil)
+	r.RemoteAddr = "192.0.2.10:5555"
+	r.Header.Set("User-Agent", "curl/8.0")
+	r.Header.Set("X-Api-Key", "secret")
+	r.Header.Set("Cookie", "session=abc")
+
+	ctx := NewExpressionContext(r)
+	ctx.Fields["cf.threat_score"] = 15
+	ctx.Fields["ip.src.country"] = "GB"
+	ctx.Lists = map[string][]string{"office": {"198.51.100.0/24", "192.0.2.10"}, "cf.open_proxies": {"203.0.113.1"}}
+
+	testCases := map[string]bool{
+		`http.host eq "www.example.com"`:                                                      true,
+		`http.host wildcard "*.EXAMPLE.com"`:                                                  true,
+		`http.host strict wildcard "*.EXAMPLE.com"`:                                           false,
+		`http.request.method in {"GET" "HEAD"}`:                                               false,
+		`ssl and http.request.uri.path matches "^/api/v[0-9]+/"`:                              true,
+		`http.request.uri.path.extension eq "json"`:                                           true,
+		`http.request.full_uri eq "https://www.example.com/api/v1/users.json?q=a&q=b&page=2"`: true,
+		`ip.src in {192.0.2.0/24}`:                                                            true,
+		`ip.src eq 192.0.2.0/24 and ip.src ne 192.0.2.11`:                                     true,
+		`ip.src in $office`:                                                                   true,
+		`ip.src in $cf.open_proxies`:                                                          false,
+		`ip.src in {192.0.2.1..192.0.2.10}`:                                                   true,
+		`ip.src in {192.0.2.11..192.0.2.20 2001:db8::1..2001:db8::ff}`:                        false,
+		`cf.threat_score in {0..10}`:                                                          false,
+		`cf.threat_score ge 15 xor ip.src.country eq "GB"`:                                    false,
+		`http.request.headers["x-api-key"][0] eq "secret"`:                                    true,
+		`http.request.headers["x-missing"][0] ne "secret"`:                                    false,
+		`any(http.request.uri.args["q"][*] eq "b")`:                                           true,
+		`all(http.request.uri.args["q"][*] eq "b")`:                                           false,
+		`any(upper(http.request.headers.names[*]) eq "USER-AGENT")`:                           true,
+		`http.request.cookies["session"][0] eq "abc"`:                                         true,
+		`len(http.request.uri.args.names) eq 3`:                                               true,
+		`starts_with(http.user_agent, "curl/") and not ends_with(http.user_agent, "x")`:       true,
+		`concat(http.host, http.request.uri.path) contains "com/api"`:                         true,
+		`cf.bot_management.score lt 30`:                                                       true,
+		`lookup_json_string("{\"a\":{\"b\":\"c\"}}", "a", "b") eq "c"`:                        true,
+	}
+
+	for src, want := range testCases {
+		t.Run(src, func(t *testing.T) {
+			got, err := EvaluateExpression(src, ctx)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestEvaluateExpression_Errors(t *testing.T) {
+	_, err := EvaluateExpression(`http.hots eq "a"`, ExpressionContext{})
+	assert.ErrorIs(t, err, ErrInvalidExpression)
+
+	_, err = EvaluateExpression(`uuidv4(http.host) eq "a"`, ExpressionContext{})
+	assert.ErrorIs(t, err, ErrExpressionFunctionUnsupported)
+
+	_, err = EvaluateExpression(`ip.src eq 192.0.2.1`, ExpressionContext{Fields: map[string]interface{}{"ip.src": "nope"}})
+	assert.Error(t, err)
+}
+
+func TestMatchExpressionWildcard(t *testing.T) {
+	assert.True(t, matchExpressionWildcard("*.example.com/*", "www.example.com/a/b", true))
+	assert.True(t, matchExpressionWildcard(`a\*b`, "a*b", true))
+	assert.False(t, matchExpressionWildcard(`a\*b`, "axb", true))
+	assert.True(t, matchExpressionWildcard("A*", "abc", false))
+	assert.False(t, matchExpressionWildcard("A*", "abc", true))
+}
@@ -0,0 +1,577 @@
+package cloudflare
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExpressionType is the type of a field, function result or value in the
+// rules language.
+type ExpressionType string
+
+const (
+	ExpressionTypeBytes   ExpressionType = "Bytes"
+	ExpressionTypeInt     ExpressionType = "Int"
+	ExpressionTypeBoolean ExpressionType = "Boolean"
+	ExpressionTypeIP      ExpressionType = "IP address"
+
+	expressionTypeAny ExpressionType = ""
+)
+
+// ExpressionTypeArray returns the type of an array of t.
+func ExpressionTypeArray(t ExpressionType) ExpressionType {
+	return "Array<" + t + ">"
+}
+
+// ExpressionTypeMap returns the type of a map from strings to t.
+func ExpressionTypeMap(t ExpressionType) ExpressionType {
+	return "Map<" + t + ">"
+}
+
+func (t ExpressionType) element() (ExpressionType, bool, bool) {
+	s := string(t)
+	if strings.HasPrefix(s, "Array<") {
+		return ExpressionType(s[6 : len(s)-1]), true, false
+	}
+	if strings.HasPrefix(s, "Map<") {
+		return ExpressionType(s[4 : len(s)-1]), false, true
+	}
+	return "", false, false
+}
+
+var (
+	expressionTypeBytesArray    = ExpressionTypeArray(ExpressionTypeBytes)
+	expressionTypeBytesArrayMap = ExpressionTypeMap(ExpressionTypeArray(ExpressionTypeBytes))
+)
+
+// expressionFields are the fields of the rules language, see
+// https://developers.cloudflare.com/ruleset-engine/rules-language/fields/,
+// and of Gateway policies, see
+// https://developers.cloudflare.com/cloudflare-one/policies/gateway/
+var expressionFields = map[string]ExpressionType{
+	"http.cookie":                                          ExpressionTypeBytes,
+	"http.host":                                            ExpressionTypeBytes,
+	"http.referer":                                         ExpressionTypeBytes,
+	"http.request.full_uri":                                ExpressionTypeBytes,
+	"http.request.method":                                  ExpressionTypeBytes,
+	"http.request.uri":                                     ExpressionTypeBytes,
+	"http.request.uri.path":                                ExpressionTypeBytes,
+	"http.request.uri.path.extension":                      ExpressionTypeBytes,
+	"http.request.uri.query":                               ExpressionTypeBytes,
+	"http.request.version":                                 ExpressionTypeBytes,
+	"http.user_agent":                                      ExpressionTypeBytes,
+	"http.x_forwarded_for":                                 ExpressionTypeBytes,
+	"http.request.timestamp.sec":                           ExpressionTypeInt,
+	"http.request.timestamp.msec":                          ExpressionTypeInt,
+	"http.request.headers":                                 expressionTypeBytesArrayMap,
+	"http.request.headers.names":                           expressionTypeBytesArray,
+	"http.request.headers.values":                          expressionTypeBytesArray,
+	"http.request.headers.truncated":                       ExpressionTypeBoolean,
+	"http.request.cookies":                                 expressionTypeBytesArrayMap,
+	"http.request.uri.args":                                expressionTypeBytesArrayMap,
+	"http.request.uri.args.names":                          expressionTypeBytesArray,
+	"http.request.uri.args.values":                         expressionTypeBytesArray,
+	"http.request.jwt.claims":                              expressionTypeBytesArrayMap,
+	"http.request.accepted_languages":                      expressionTypeBytesArray,
+	"http.request.body.raw":                                ExpressionTypeBytes,
+	"http.request.body.size":                               ExpressionTypeInt,
+	"http.request.body.truncated":                          ExpressionTypeBoolean,
+	"http.request.body.mime":                               ExpressionTypeBytes,
+	"http.request.body.form":                               expressionTypeBytesArrayMap,
+	"http.request.body.form.names":                         expressionTypeBytesArray,
+	"http.request.body.form.values":                        expressionTypeBytesArray,
+	"http.response.code":                                   ExpressionTypeInt,
+	"http.response.content_type.media_type":                ExpressionTypeBytes,
+	"http.response.headers":                                expressionTypeBytesArrayMap,
+	"http.response.headers.names":                          expressionTypeBytesArray,
+	"http.response.headers.values":                         expressionTypeBytesArray,
+	"raw.http.request.full_uri":                            ExpressionTypeBytes,
+	"raw.http.request.uri":                                 ExpressionTypeBytes,
+	"raw.http.request.uri.path":                            ExpressionTypeBytes,
+	"raw.http.request.uri.query":                           ExpressionTypeBytes,
+	"ip.src":                                               ExpressionTypeIP,
+	"ip.src.asnum":                                         ExpressionTypeInt,
+	"ip.src.city":                                          ExpressionTypeBytes,
+	"ip.src.continent":                                     ExpressionTypeBytes,
+	"ip.src.country":                                       ExpressionTypeBytes,
+	"ip.src.is_in_european_union":                          ExpressionTypeBoolean,
+	"ip.src.lat":                                           ExpressionTypeBytes,
+	"ip.src.lon":                                           ExpressionTypeBytes,
+	"ip.src.metro_code":                                    ExpressionTypeBytes,
+	"ip.src.postal_code":                                   ExpressionTypeBytes,
+	"ip.src.region":                                        ExpressionTypeBytes,
+	"ip.src.region_code":                                   ExpressionTypeBytes,
+	"ip.src.subdivision_1_iso_code":                        ExpressionTypeBytes,
+	"ip.src.subdivision_2_iso_code":                        ExpressionTypeBytes,
+	"ip.src.timezone.name":                                 ExpressionTypeBytes,
+	"ip.geoip.asnum":                                       ExpressionTypeInt,
+	"ip.geoip.continent":                                   ExpressionTypeBytes,
+	"ip.geoip.country":                                     ExpressionTypeBytes,
+	"ip.geoip.is_in_european_union":                        ExpressionTypeBoolean,
+	"ip.geoip.subdivision_1_iso_code":                      ExpressionTypeBytes,
+	"ip.geoip.subdivision_2_iso_code":                      ExpressionTypeBytes,
+	"ssl":                                                  ExpressionTypeBoolean,
+	"cf.bot_management.corporate_proxy":                    ExpressionTypeBoolean,
+	"cf.bot_management.detection_ids":                      ExpressionTypeArray(ExpressionTypeInt),
+	"cf.bot_management.ja3_hash":                           ExpressionTypeBytes,
+	"cf.bot_management.ja4":                                ExpressionTypeBytes,
+	"cf.bot_management.js_detection.passed":                ExpressionTypeBoolean,
+	"cf.bot_management.score":                              ExpressionTypeInt,
+	"cf.bot_management.static_resource":                    ExpressionTypeBoolean,
+	"cf.bot_management.verified_bot":                       ExpressionTypeBoolean,
+	"cf.client.bot":                                        ExpressionTypeBoolean,
+	"cf.colo.id":                                           ExpressionTypeInt,
+	"cf.edge.server_ip":                                    ExpressionTypeIP,
+	"cf.edge.server_port":                                  ExpressionTypeInt,
+	"cf.hostname.metadata":                                 ExpressionTypeBytes,
+	"cf.ray_id":                                            ExpressionTypeBytes,
+	"cf.threat_score":                                      ExpressionTypeInt,
+	"cf.tls_client_auth.cert_fingerprint_sha256":           ExpressionTypeBytes,
+	"cf.tls_client_auth.cert_presented":                    ExpressionTypeBoolean,
+	"cf.tls_client_auth.cert_revoked":                      ExpressionTypeBoolean,
+	"cf.tls_client_auth.cert_verified":                     ExpressionTypeBoolean,
+	"cf.verified_bot_category":                             ExpressionTypeBytes,
+	"cf.waf.credential_check.password_leaked":              ExpressionTypeBoolean,
+	"cf.waf.credential_check.username_and_password_leaked": ExpressionTypeBoolean,
+	"cf.waf.score":                                         ExpressionTypeInt,
+	"cf.waf.score.class":                                   ExpressionTypeBytes,
+	"cf.waf.score.rce":                                     ExpressionTypeInt,
+	"cf.waf.score.sqli":                                    ExpressionTypeInt,
+	"cf.waf.score.xss":                                     ExpressionTypeInt,
+	"cf.worker.upstream_zone":                              ExpressionTypeBytes,
+	"cf.zone.name":                                         ExpressionTypeBytes,
+
+	// Gateway DNS, HTTP and network policies.
+	"app.ids":                            ExpressionTypeArray(ExpressionTypeInt),
+	"app.type.ids":                       ExpressionTypeArray(ExpressionTypeInt),
+	"dns.content_category":               ExpressionTypeArray(ExpressionTypeInt),
+	"dns.domains":                        expressionTypeBytesArray,
+	"dns.dst.ip":                         ExpressionTypeIP,
+	"dns.fqdn":                           ExpressionTypeBytes,
+	"dns.location":                       ExpressionTypeBytes,
+	"dns.query_rtype":                    ExpressionTypeBytes,
+	"dns.resolved_ips":                   ExpressionTypeArray(ExpressionTypeIP),
+	"dns.security_category":              ExpressionTypeArray(ExpressionTypeInt),
+	"dns.src.ip":                         ExpressionTypeIP,
+	"http.conn.dst_ip":                   ExpressionTypeIP,
+	"http.conn.dst_port":                 ExpressionTypeInt,
+	"http.conn.src_ip":                   ExpressionTypeIP,
+	"http.request.domains":               expressionTypeBytesArray,
+	"http.request.host":                  ExpressionTypeBytes,
+	"http.request.uri.category":          ExpressionTypeArray(ExpressionTypeInt),
+	"http.request.uri.content_category":  ExpressionTypeArray(ExpressionTypeInt),
+	"http.request.uri.security_category": ExpressionTypeArray(ExpressionTypeInt),
+	"http.upload.mime":                   ExpressionTypeBytes,
+	"identity.email":                     ExpressionTypeBytes,
+	"identity.groups.email":              expressionTypeBytesArray,
+	"identity.groups.id":                 expressionTypeBytesArray,
+	"identity.groups.name":               expressionTypeBytesArray,
+	"net.detected_protocol":              ExpressionTypeBytes,
+	"net.dst.geo.country":                ExpressionTypeBytes,
+	"net.dst.ip":                         ExpressionTypeIP,
+	"net.dst.port":                       ExpressionTypeInt,
+	"net.protocol":                       ExpressionTypeBytes,
+	"net.sni.domains":                    expressionTypeBytesArray,
+	"net.sni.host":                       ExpressionTypeBytes,
+	"net.src.geo.country":                ExpressionTypeBytes,
+	"net.src.ip":                         ExpressionTypeIP,
+	"net.src.port":                       ExpressionTypeInt,
+}
+
+// expressionDeprecatedFields maps deprecated fields to their replacement.
+var expressionDeprecatedFields = map[string]string{
+	"ip.geoip.asnum":                  "ip.src.asnum",
+	"ip.geoip.continent":              "ip.src.continent",
+	"ip.geoip.country":                "ip.src.country",
+	"ip.geoip.is_in_european_union":   "ip.src.is_in_european_union",
+	"ip.geoip.subdivision_1_iso_code": "ip.src.subdivision_1_iso_code",
+	"ip.geoip.subdivision_2_iso_code": "ip.src.subdivision_2_iso_code",
+	"cf.client.bot":                   "cf.bot_management.verified_bot",
+}
+
+// LookupExpressionField returns the type of a field of the rules language.
+func LookupExpressionField(name string) (ExpressionType, bool) {
+	t, ok := expressionFields[name]
+	return t, ok
+}
+
+type expressionFunctionSignature struct {
+	args []ExpressionType
+	// optional is the number of trailing arguments which can be left out.
+	optional int
+	// variadic repeats the last argument type.
+	variadic bool
+	result   ExpressionType
+}
+
+// expressionFunctions are the functions of the rules language, see
+// https://developers.cloudflare.com/ruleset-engine/rules-language/functions/
+var expressionFunctions = map[string]expressionFunctionSignature{
+	"any":                 {args: []ExpressionType{ExpressionTypeBoolean}, result: ExpressionTypeBoolean},
+	"all":                 {args: []ExpressionType{ExpressionTypeBoolean}, result: ExpressionTypeBoolean},
+	"concat":              {args: []ExpressionType{ExpressionTypeBytes}, variadic: true, result: ExpressionTypeBytes},
+	"decode_base64":       {args: []ExpressionType{ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"ends_with":           {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes}, result: ExpressionTypeBoolean},
+	"len":                 {args: []ExpressionType{expressionTypeAny}, result: ExpressionTypeInt},
+	"lookup_json_integer": {args: []ExpressionType{ExpressionTypeBytes, expressionTypeAny}, variadic: true, result: ExpressionTypeInt},
+	"lookup_json_string":  {args: []ExpressionType{ExpressionTypeBytes, expressionTypeAny}, variadic: true, result: ExpressionTypeBytes},
+	"lower":               {args: []ExpressionType{ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"regex_replace":       {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes, ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"remove_bytes":        {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"starts_with":         {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes}, result: ExpressionTypeBoolean},
+	"to_string":           {args: []ExpressionType{expressionTypeAny}, result: ExpressionTypeBytes},
+	"upper":               {args: []ExpressionType{ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"url_decode":          {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes}, optional: 1, result: ExpressionTypeBytes},
+	"uuidv4":              {args: []ExpressionType{ExpressionTypeBytes}, result: ExpressionTypeBytes},
+	"wildcard_replace":    {args: []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes, ExpressionTypeBytes, ExpressionTypeBytes}, optional: 1, result: ExpressionTypeBytes},
+	"is_timed_hmac_valid_v0": {
+		args:     []ExpressionType{ExpressionTypeBytes, ExpressionTypeBytes, ExpressionTypeInt, ExpressionTypeInt, ExpressionTypeInt, ExpressionTypeBytes},
+		optional: 2,
+		result:   ExpressionTypeBoolean,
+	},
+}
+
+// expressionOperatorTypes are the types each comparison operator applies to.
+var expressionOperatorTypes = map[ExpressionOperator][]ExpressionType{
+	ExpressionOperatorEqual:          {ExpressionTypeBytes, ExpressionTypeInt, ExpressionTypeIP},
+	ExpressionOperatorNotEqual:       {ExpressionTypeBytes, ExpressionTypeInt, ExpressionTypeIP},
+	ExpressionOperatorLess:           {ExpressionTypeBytes, ExpressionTypeInt},
+	ExpressionOperatorLessEqual:      {ExpressionTypeBytes, ExpressionTypeInt},
+	ExpressionOperatorGreater:        {ExpressionTypeBytes, ExpressionTypeInt},
+	ExpressionOperatorGreaterEqual:   {ExpressionTypeBytes, ExpressionTypeInt},
+	ExpressionOperatorContains:       {ExpressionTypeBytes},
+	ExpressionOperatorMatches:        {ExpressionTypeBytes},
+	ExpressionOperatorWildcard:       {ExpressionTypeBytes},
+	ExpressionOperatorStrictWildcard: {ExpressionTypeBytes},
+	ExpressionOperatorIn:             {ExpressionTypeBytes, ExpressionTypeInt, ExpressionTypeIP},
+}
+
+func expressionLiteralType(kind ExpressionLiteralKind) ExpressionType {
+	switch kind {
+	case ExpressionLiteralString:
+		return ExpressionTypeBytes
+	case ExpressionLiteralInt, ExpressionLiteralIntRange:
+		return ExpressionTypeInt
+	case ExpressionLiteralIP:
+		return ExpressionTypeIP
+	}
+	return ExpressionTypeBoolean
+}
+
+// ValidateExpression parses an expression and checks it against the fields,
+// functions and operators of the rules language without calling the API.
+// Errors are *ExpressionError values giving the line and column.
+func ValidateExpression(expression string) error {
+	e, err := ParseExpression(expression)
+	if err != nil {
+		return err
+	}
+	return e.Validate()
+}
+
+// Validate checks the fields, functions, operators and value types used in
+// the expression.
+func (e *Expression) Validate() error {
+	c := expressionChecker{src: e.source}
+	t, wildcard, err := c.check(e.Root)
+	if err != nil {
+		return err
+	}
+	if wildcard {
+		return c.errorf(e.Root.Pos(), "[*] can only be used inside any() or all()")
+	}
+	if t != ExpressionTypeBoolean {
+		return c.errorf(e.Root.Pos(), "expression must be %s, got %s", ExpressionTypeBoolean, t)
+	}
+	return nil
+}
+
+type expressionChecker struct {
+	src string
+}
+
+func (c *expressionChecker) errorf(pos int, format string, args ...interface{}) error {
+	return &ExpressionError{Position: expressionPosition(c.src, pos), Message: fmt.Sprintf(format, args...)}
+}
+
+// check returns the type of a node and whether it is evaluated once per
+// element of an array accessed with [*].
+func (c *expressionChecker) check(n ExpressionNode) (ExpressionType, bool, error) {
+	switch n := n.(type) {
+	case *ExpressionGroup:
+		return c.check(n.X)
+
+	case *ExpressionNot:
+		t, wildcard, err := c.check(n.X)
+		if err != nil {
+			return "", false, err
+		}
+		if t != ExpressionTypeBoolean {
+			return "", false, c.errorf(n.X.Pos(), "not requires %s, got %s", ExpressionTypeBoolean, t)
+		}
+		return ExpressionTypeBoolean, wildcard, nil
+
+	case *ExpressionLogical:
+		lt, lw, err := c.check(n.Left)
+		if err != nil {
+			return "", false, err
+		}
+		rt, rw, err := c.check(n.Right)
+		if err != nil {
+			return "", false, err
+		}
+		if lt != ExpressionTypeBoolean {
+			return "", false, c.errorf(n.Left.Pos(), "%s requires %s operands, got %s", n.Operator, ExpressionTypeBoolean, lt)
+		}
+		if rt != ExpressionTypeBoolean {
+			return "", false, c.errorf(n.Right.Pos(), "%s requires %s operands, got %s", n.Operator, ExpressionTypeBoolean, rt)
+		}
+		if lw != rw {
+			return "", false, c.errorf(n.Offset, "can't combine a [*] comparison with a single value using %s", n.Operator)
+		}
+		return ExpressionTypeBoolean, lw, nil
+
+	case *ExpressionLiteral:
+		return expressionLiteralType(n.Kind), false, nil
+
+	case *ExpressionField:
+		return c.checkField(n)
+
+	case *ExpressionFunction:
+		return c.checkFunction(n)
+
+	case *ExpressionComparison:
+		return c.checkComparison(n)
+	}
+	return "", false, c.errorf(n.Pos(), "unexpected %s", n)
+}
+
+func (c *expressionChecker) checkField(n *ExpressionField) (ExpressionType, bool, error) {
+	t, ok := expressionFields[n.Name]
+	if !ok {
+		return "", false, c.errorf(n.Offset, "unknown field %q", n.Name)
+	}
+
+	wildcard := false
+	for _, idx := range n.Indexes {
+		elem, isArray, isMap := t.element()
+		switch {
+		case idx.Wildcard && (isArray || isMap):
+			if wildcard {
+				return "", false, c.errorf(n.Offset, "[*] can only be used once in %s", n.Name)
+			}
+			wildcard = true
+		case idx.Key != nil && isMap, idx.Index != nil && isArray:
+		default:
+			return "", false, c.errorf(n.Offset, "%s of type %s can't be indexed with %s", n.Name, t, expressionIndexString(idx))
+		}
+		t = elem
+	}
+	return t, wildcard, nil
+}
+
+func expressionIndexString(idx ExpressionIndex) string {
+	return (&ExpressionField{Indexes: []ExpressionIndex{idx}}).String()
+}
+
+func (c *expressionChecker) checkFunction(n *ExpressionFunction) (ExpressionType, bool, error) {
+	sig, ok := expressionFunctions[n.Name]
+	if !ok {
+		return "", false, c.errorf(n.Offset, "unknown function %q", n.Name)
+	}
+
+	min, max := len(sig.args)-sig.optional, len(sig.args)
+	switch {
+	case sig.variadic && len(n.Args) < min:
+		return "", false, c.errorf(n.Offset, "%s takes at least %d arguments, got %d", n.Name, min, len(n.Args))
+	case !sig.variadic && (len(n.Args) < min || len(n.Args) > max):
+		return "", false, c.errorf(n.Offset, "%s takes %s arguments, got %d", n.Name, expressionArgCount(min, max), len(n.Args))
+	}
+
+	wildcard := false
+	for i, arg := range n.Args {
+		t, w, err := c.check(arg)
+		if err != nil {
+			return "", false, err
+		}
+		want := sig.args[len(sig.args)-1]
+		if i < len(sig.args) {
+			want = sig.args[i]
+		}
+		if want != expressionTypeAny && t != want {
+			return "", false, c.errorf(arg.Pos(), "argument %d of %s must be %s, got %s", i+1, n.Name, want, t)
+		}
+		wildcard = wildcard || w
+	}
+
+	if n.Name == "any" || n.Name == "all" {
+		if !wildcard {
+			return "", false, c.errorf(n.Offset, "%s requires a comparison using [*]", n.Name)
+		}
+		return sig.result, false, nil
+	}
+	return sig.result, wildcard, nil
+}
+
+func expressionArgCount(min, max int) string {
+	if min == max {
+		return fmt.Sprint(min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
+
+func (c *expressionChecker) checkComparison(n *ExpressionComparison) (ExpressionType, bool, error) {
+	t, wildcard, err := c.check(n.Left)
+	if err != nil || n.Operator == "" {
+		return t, wildcard, err
+	}
+
+	if !containsExpressionType(expressionOperatorTypes[n.Operator], t) {
+		return "", false, c.errorf(n.Offset, "%s can't be used with %s", n.Operator, t)
+	}
+
+	switch right := n.Right.(type) {
+	case *ExpressionList:
+		for _, item := range right.Items {
+			if expressionLiteralType(item.Kind) != t {
+				return "", false, c.errorf(item.Offset, "list item %s isn't %s", item, t)
+			}
+		}
+	case *ExpressionLiteral:
+		_, isIPRange := right.Value.(ExpressionIPRange)
+		if right.Kind == ExpressionLiteralIntRange || isIPRange || expressionLiteralType(right.Kind) != t {
+			return "", false, c.errorf(right.Offset, "can't compare %s with %s", t, right)
+		}
+		if _, isNetwork := right.Value.(*net.IPNet); isNetwork && n.Operator != ExpressionOperatorEqual && n.Operator != ExpressionOperatorNotEqual {
+			return "", false, c.errorf(right.Offset, "%s can't be used with a CIDR", n.Operator)
+		}
+		if n.Operator == ExpressionOperatorMatches {
+			if _, err := regexp.Compile(right.Value.(string)); err != nil {
+				return "", false, c.errorf(right.Offset, "invalid regular expression: %s", err)
+			}
+		}
+	}
+	return ExpressionTypeBoolean, wildcard, nil
+}
+
+func containsExpressionType(types []ExpressionType, t ExpressionType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpressionWarning is a likely mistake in an expression which is valid but
+// probably doesn't do what was intended.
+type ExpressionWarning struct {
+	Position ExpressionPosition
+	Message  string
+}
+
+func (w ExpressionWarning) String() string {
+	return fmt.Sprintf("%d:%d: %s", w.Position.Line, w.Position.Column, w.Message)
+}
+
+// LintExpression validates an expression and returns warnings for likely
+// mistakes, such as matching header names with upper case letters which
+// never match, or comparing the result of lower() with upper case text.
+//
+// The parser doesn't cover the whole rules language yet, so an expression
+// it can't parse is reported as a warning rather than an error.
+func LintExpression(expression string) ([]ExpressionWarning, error) {
+	e, err := ParseExpression(expression)
+	if err != nil {
+		var exprErr *ExpressionError
+		if !errors.As(err, &exprErr) {
+			return nil, err
+		}
+		return []ExpressionWarning{{Position: exprErr.Position, Message: "expression can't be parsed locally: " + exprErr.Message}}, nil
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e.Lint(), nil
+}
+
+// Lint returns warnings for likely mistakes in a valid expression, ordered
+// by position.
+func (e *Expression) Lint() []ExpressionWarning {
+	var warnings []ExpressionWarning
+	warn := func(pos int, format string, args ...interface{}) {
+		warnings = append(warnings, ExpressionWarning{
+			Position: expressionPosition(e.source, pos),
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	e.Walk(func(n ExpressionNode) bool {
+		switch n := n.(type) {
+		case *ExpressionField:
+			if replacement, ok := expressionDeprecatedFields[n.Name]; ok {
+				warn(n.Offset, "%s is deprecated, use %s", n.Name, replacement)
+			}
+			if n.Name == "http.request.headers" || n.Name == "http.response.headers" {
+				for _, idx := range n.Indexes {
+					if idx.Key != nil && *idx.Key != strings.ToLower(*idx.Key) {
+						warn(n.Offset, "header names are lower case, %q never matches", *idx.Key)
+					}
+				}
+			}
+		case *ExpressionComparison:
+			lintExpressionComparison(n, warn)
+		case *ExpressionList:
+			seen := map[string]bool{}
+			for _, item := range n.Items {
+				if seen[item.String()] {
+					warn(item.Offset, "duplicate list item %s", item)
+				}
+				seen[item.String()] = true
+			}
+		}
+		return true
+	})
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Position.Offset < warnings[j].Position.Offset
+	})
+	return warnings
+}
+
+func lintExpressionComparison(n *ExpressionComparison, warn func(int, string, ...interface{})) {
+	right, ok := n.Right.(*ExpressionLiteral)
+	if !ok || right.Kind != ExpressionLiteralString {
+		return
+	}
+	value := right.Value.(string)
+
+	switch n.Operator {
+	case ExpressionOperatorWildcard, ExpressionOperatorStrictWildcard:
+		if !strings.Contains(value, "*") {
+			warn(right.Offset, "%s pattern %q has no *, use eq", n.Operator, value)
+		}
+	case ExpressionOperatorContains:
+		if value == "" {
+			warn(right.Offset, "contains \"\" always matches")
+		}
+	}
+
+	if fn, ok := n.Left.(*ExpressionFunction); ok && n.Operator != ExpressionOperatorMatches {
+		switch {
+		case fn.Name == "lower" && value != strings.ToLower(value):
+			warn(right.Offset, "lower() is compared with %q which has upper case letters and never matches", value)
+		case fn.Name == "upper" && value != strings.ToUpper(value):
+			warn(right.Offset, "upper() is compared with %q which has lower case letters and never matches", value)
+		}
+	}
+
+	if field, ok := n.Left.(*ExpressionField); ok && field.Name == "http.request.uri.path" && len(field.Indexes) == 0 &&
+		(n.Operator == ExpressionOperatorEqual || n.Operator == ExpressionOperatorStrictWildcard) && !strings.HasPrefix(value, "/") {
+		warn(right.Offset, "paths start with /, %q never matches", value)
+	}
+}
@@ -0,0 +1,156 @@
+package cloudflare
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	testCases := map[string]string{
+		`http.host eq "example.com"`:                                                 `http.host eq "example.com"`,
+		`http.host == "example.com" && ssl`:                                          `http.host eq "example.com" and ssl`,
+		`not ssl or (ip.src in {192.0.2.0/24 2001:db8::1} and cf.threat_score > 10)`: `not ssl or (ip.src in {192.0.2.0/24 2001:db8::1} and cf.threat_score gt 10)`,
+		`http.request.uri.path ~ r"^/api/v[0-9]+/"`:                                  `http.request.uri.path matches r"^/api/v[0-9]+/"`,
+		`http.request.headers["x-foo"][0] eq "a\"b"`:                                 `http.request.headers["x-foo"][0] eq "a\"b"`,
+		`any(lower(http.request.headers.names[*])[*] eq "x")`:                        ``,
+		`any(http.request.headers.names[*] == "x-foo")`:                              `any(http.request.headers.names[*] eq "x-foo")`,
+		`cf.edge.server_port in {80 8000..8080} xor ip.src in $office`:               `cf.edge.server_port in {80 8000..8080} xor ip.src in $office`,
+		`http.host strict wildcard "*.example.com"`:                                  `http.host strict wildcard "*.example.com"`,
+		`starts_with(http.request.uri.path, "/a")`:                                   `starts_with(http.request.uri.path, "/a")`,
+		`ip.src in $cf.open_proxies`:                                                 `ip.src in $cf.open_proxies`,
+		`ip.src in {192.0.2.1..192.0.2.10 2001:db8::1..2001:db8::ff}`:                `ip.src in {192.0.2.1..192.0.2.10 2001:db8::1..2001:db8::ff}`,
+		`true`: `true`,
+	}
+
+	for src, want := range testCases {
+		t.Run(src, func(t *testing.T) {
+			e, err := ParseExpression(src)
+			if want == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, e.String())
+
+			// Formatting is stable.
+			again, err := ParseExpression(e.String())
+			require.NoError(t, err)
+			assert.Equal(t, want, again.String())
+		})
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	testCases := map[string]string{
+		`http.host eq`:                       `1:13: expected a value, got end of expression`,
+		`http.host eq "a`:                    `1:14: unterminated string`,
+		"ssl and\n(http.host eq \"a\"":       `2:18: expected ")", got end of expression`,
+		`ip.src eq 300.1.1.1`:                `1:11: invalid IP address "300.1.1.1"`,
+		`http.host eq "a" foo`:               `1:18: unexpected "foo"`,
+		`http.host in "a"`:                   `1:14: expected "{", got "\"a\""`,
+		`and ssl`:                            `1:1: expected field or function, got operator "and"`,
+		`ip.src in {192.0.2.9..192.0.2.1}`:   `1:12: invalid IP range "192.0.2.9..192.0.2.1"`,
+		`ip.src in {192.0.2.1..2001:db8::1}`: `1:12: invalid IP range "192.0.2.1..2001:db8::1"`,
+	}
+
+	for src, want := range testCases {
+		t.Run(src, func(t *testing.T) {
+			_, err := ParseExpression(src)
+			require.Error(t, err)
+			assert.Equal(t, want, err.Error())
+			assert.True(t, errors.Is(err, ErrInvalidExpression))
+		})
+	}
+}
+
+func TestValidateExpression(t *testing.T) {
+	testCases := map[string]string{
+		`http.host eq "example.com" and cf.threat_score gt 10`: ``,
+		`any(lower(http.request.headers.names[*]) eq "x-foo")`: ``,
+		`len(http.request.uri.args["q"]) gt 1`:                 ``,
+		`http.hots eq "a"`:                                     `1:1: unknown field "http.hots"`,
+		`cf.threat_score eq "10"`:                              `1:20: can't compare Int with "10"`,
+		`http.host contains 10`:                                `1:20: can't compare Bytes with 10`,
+		`ip.src contains "1"`:                                  `1:1: contains can't be used with IP address`,
+		`http.host`:                                            `1:1: expression must be Boolean, got Bytes`,
+		`http.request.headers.names[*] eq "a"`:                 `1:1: [*] can only be used inside any() or all()`,
+		`any(http.host eq "a")`:                                `1:1: any requires a comparison using [*]`,
+		`lower(http.host, "a") eq "a"`:                         `1:1: lower takes 1 arguments, got 2`,
+		`http.host matches "("`:                                "1:19: invalid regular expression: error parsing regexp: missing closing ): `(`",
+		`ip.src eq 192.0.2.1..192.0.2.10`:                      `1:11: can't compare IP address with 192.0.2.1..192.0.2.10`,
+		`ip.src in {192.0.2.1..192.0.2.10}`:                    ``,
+		`cf.edge.server_port in {80 "443"}`:                    `1:28: list item "443" isn't Int`,
+		`ssl and http.host["a"] eq "b"`:                        `1:9: http.host of type Bytes can't be indexed with ["a"]`,
+		`ip.src gt 192.0.2.1`:                                  `1:1: gt can't be used with IP address`,
+		`any(dns.domains[*] == "example.com")`:                 ``,
+		`net.dst.ip in {10.0.0.0/8} and net.dst.port == 443`:   ``,
+		`any(http.request.uri.content_category[*] in {1 4})`:   ``,
+		`http.request.jwt.claims["iss"][0] eq "example.com"`:   ``,
+	}
+
+	for src, want := range testCases {
+		t.Run(src, func(t *testing.T) {
+			err := ValidateExpression(src)
+			if want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, want, err.Error())
+		})
+	}
+}
+
+func TestLintExpression(t *testing.T) {
+	warnings, err := LintExpression(`http.request.headers["X-Foo"][0] eq "a" or lower(http.host) eq "Example.com" or ip.geoip.country in {"GB" "GB"} or http.request.uri.path eq "api" or http.host wildcard "example.com"`)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.String())
+	}
+	assert.Equal(t, []string{
+		`1:1: header names are lower case, "X-Foo" never matches`,
+		`1:64: lower() is compared with "Example.com" which has upper case letters and never matches`,
+		`1:81: ip.geoip.country is deprecated, use ip.src.country`,
+		`1:107: duplicate list item "GB"`,
+		`1:141: paths start with /, "api" never matches`,
+		`1:169: wildcard pattern "example.com" has no *, use eq`,
+	}, messages)
+
+	_, err = LintExpression(`http.hots eq "a"`)
+	assert.Error(t, err)
+
+	warnings, err = LintExpression(`http.host eq "a" foo`)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, `1:18: expression can't be parsed locally: unexpected "foo"`, warnings[0].String())
+}
+
+func TestFormatExpression(t *testing.T) {
+	got, err := FormatExpression(`http.host eq "a" and (ssl or ip.src in {192.0.2.1}) and not (cf.threat_score gt 10)`)
+	require.NoError(t, err)
+	assert.Equal(t, `http.host eq "a"
+and (
+  ssl
+  or ip.src in {192.0.2.1}
+)
+and not (cf.threat_score gt 10)`, got)
+}
+
+func TestExpression_Walk(t *testing.T) {
+	e, err := ParseExpression(`http.host eq "a" and any(http.request.headers.names[*] eq "b")`)
+	require.NoError(t, err)
+
+	var fields []string
+	e.Walk(func(n ExpressionNode) bool {
+		if f, ok := n.(*ExpressionField); ok {
+			fields = append(fields, f.Name)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"http.host", "http.request.headers.names"}, fields)
+}
@@ -264,7 +264,8 @@ func (api *API) DeleteFilters(ctx context.Context, rc *ResourceContainer, filter
 	return nil
 }
 
-// ValidateFilterExpression checks correctness of a filter expression.
+// ValidateFilterExpression checks correctness of a filter expression. See
+// ValidateExpression to check an expression without calling the API.
 //
 // API reference: https://developers.cloudflare.com/firewall/api/cf-filters/validation/
 func (api *API) ValidateFilterExpression(ctx context.Context, expression string) error {
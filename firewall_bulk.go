@@ -0,0 +1,447 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrUnknownAccessRulesFormat = errors.New("unknown access rules format")
+	ErrInvalidAccessRule        = errors.New("invalid access rule")
+	ErrMissingAccessRulesOwner  = errors.New("access rules owner must be set")
+	ErrAccessRuleNotOwned       = errors.New("access rule exists but isn't owned")
+)
+
+// defaultAccessRulesConcurrency is the number of access rules changed at
+// once unless set otherwise.
+const defaultAccessRulesConcurrency = 4
+
+// AccessRulesFormat is the format of an access rules file.
+type AccessRulesFormat string
+
+const (
+	// AccessRulesFormatCSV is a CSV file with a header naming the mode,
+	// target, value and notes columns. Other columns are ignored and the
+	// target is inferred from the value when missing.
+	AccessRulesFormatCSV AccessRulesFormat = "csv"
+	// AccessRulesFormatJSON is a JSON array of access rules.
+	AccessRulesFormatJSON AccessRulesFormat = "json"
+)
+
+var accessRuleModes = []string{"block", "challenge", "js_challenge", "managed_challenge", "whitelist"}
+
+var accessRuleTargets = []string{"ip", "ip6", "ip_range", "asn", "country"}
+
+var accessRuleASNPattern = regexp.MustCompile(`^(?i:AS)?([0-9]+)$`)
+
+var accessRuleCountryPattern = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+// AccessRuleConfigurationFromValue returns the configuration matching an
+// IP address, CIDR range, AS number or country code.
+func AccessRuleConfigurationFromValue(value string) (AccessRuleConfiguration, error) {
+	value = strings.TrimSpace(value)
+
+	if addr, err := netip.ParseAddr(value); err == nil {
+		if addr.Is4() || addr.Is4In6() {
+			return AccessRuleConfiguration{Target: "ip", Value: addr.Unmap().String()}, nil
+		}
+		return AccessRuleConfiguration{Target: "ip6", Value: addr.String()}, nil
+	}
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return AccessRuleConfiguration{Target: "ip_range", Value: prefix.Masked().String()}, nil
+	}
+	if m := accessRuleASNPattern.FindStringSubmatch(value); m != nil {
+		if _, err := strconv.ParseUint(m[1], 10, 32); err == nil {
+			return AccessRuleConfiguration{Target: "asn", Value: "AS" + m[1]}, nil
+		}
+	}
+	if accessRuleCountryPattern.MatchString(value) {
+		return AccessRuleConfiguration{Target: "country", Value: strings.ToUpper(value)}, nil
+	}
+
+	return AccessRuleConfiguration{}, fmt.Errorf("%w: unknown value %q", ErrInvalidAccessRule, value)
+}
+
+func validateAccessRule(rule AccessRule) (AccessRule, error) {
+	if !contains(accessRuleModes, rule.Mode) {
+		return AccessRule{}, fmt.Errorf("%w: unknown mode %q", ErrInvalidAccessRule, rule.Mode)
+	}
+
+	if rule.Configuration.Target == "" {
+		configuration, err := AccessRuleConfigurationFromValue(rule.Configuration.Value)
+		if err != nil {
+			return AccessRule{}, err
+		}
+		rule.Configuration = configuration
+	} else if !contains(accessRuleTargets, rule.Configuration.Target) {
+		return AccessRule{}, fmt.Errorf("%w: unknown target %q", ErrInvalidAccessRule, rule.Configuration.Target)
+	} else if rule.Configuration.Value == "" {
+		return AccessRule{}, fmt.Errorf("%w: missing value", ErrInvalidAccessRule)
+	}
+
+	return rule, nil
+}
+
+// ReadAccessRules reads access rules from a file, checking their mode and
+// target.
+func ReadAccessRules(r io.Reader, format AccessRulesFormat) ([]AccessRule, error) {
+	var rules []AccessRule
+
+	switch format {
+	case AccessRulesFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+
+		columns := map[string]int{}
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		for _, name := range []string{"mode", "value"} {
+			if _, ok := columns[name]; !ok {
+				return nil, fmt.Errorf("%w: missing %s column", ErrInvalidAccessRule, name)
+			}
+		}
+		field := func(record []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		for _, record := range records[1:] {
+			rules = append(rules, AccessRule{
+				Mode:  field(record, "mode"),
+				Notes: field(record, "notes"),
+				Configuration: AccessRuleConfiguration{
+					Target: field(record, "target"),
+					Value:  field(record, "value"),
+				},
+			})
+		}
+
+	case AccessRulesFormatJSON:
+		if err := json.NewDecoder(r).Decode(&rules); err != nil {
+			return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAccessRulesFormat, format)
+	}
+
+	for i, rule := range rules {
+		validated, err := validateAccessRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rules[i] = validated
+	}
+
+	return rules, nil
+}
+
+// WriteAccessRules writes access rules to a file which ReadAccessRules
+// can read back.
+func WriteAccessRules(w io.Writer, format AccessRulesFormat, rules []AccessRule) error {
+	switch format {
+	case AccessRulesFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"id", "mode", "target", "value", "notes", "scope"}); err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			record := []string{rule.ID, rule.Mode, rule.Configuration.Target, rule.Configuration.Value, rule.Notes, rule.Scope.Type}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case AccessRulesFormatJSON:
+		if rules == nil {
+			rules = []AccessRule{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rules)
+
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownAccessRulesFormat, format)
+	}
+}
+
+// accessRulePrefix returns the path prefix of the access rules of a
+// resource container.
+func accessRulePrefix(rc *ResourceContainer) (string, error) {
+	switch rc.Level {
+	case UserRouteLevel:
+		return "/user", nil
+	case ZoneRouteLevel:
+		if rc.Identifier == "" {
+			return "", ErrMissingZoneID
+		}
+		return fmt.Sprintf("/zones/%s", rc.Identifier), nil
+	case AccountRouteLevel:
+		if rc.Identifier == "" {
+			return "", ErrMissingAccountID
+		}
+		return fmt.Sprintf("/accounts/%s", rc.Identifier), nil
+	default:
+		return "", fmt.Errorf(errInvalidResourceContainerAccess, rc.Level)
+	}
+}
+
+// AccessRuleInScope reports whether a rule listed for a resource container
+// belongs to it rather than being inherited from a user or account.
+func AccessRuleInScope(rc *ResourceContainer, rule AccessRule) bool {
+	switch rule.Scope.Type {
+	case "":
+		return true
+	case "organization", account:
+		return rc.Level == AccountRouteLevel
+	case zone:
+		return rc.Level == ZoneRouteLevel
+	case user:
+		return rc.Level == UserRouteLevel
+	default:
+		return false
+	}
+}
+
+// ListAllAccessRules returns every access rule of a user, zone or account
+// matching filter, fetching all pages. Rules of zones include the rules
+// inherited from their account and user.
+func (api *API) ListAllAccessRules(ctx context.Context, rc *ResourceContainer, filter AccessRule) ([]AccessRule, error) {
+	prefix, err := accessRulePrefix(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []AccessRule
+	for page := 1; ; page++ {
+		response, err := api.listAccessRules(ctx, prefix, filter, page)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, response.Result...)
+		if page >= response.ResultInfo.TotalPages {
+			break
+		}
+	}
+
+	return rules, nil
+}
+
+// AccessRuleChangeAction is the change SyncAccessRules makes to a rule.
+type AccessRuleChangeAction string
+
+const (
+	AccessRuleCreate AccessRuleChangeAction = "create"
+	AccessRuleUpdate AccessRuleChangeAction = "update"
+	AccessRuleDelete AccessRuleChangeAction = "delete"
+)
+
+// AccessRuleResult is the outcome of a change to a rule.
+type AccessRuleResult struct {
+	Action AccessRuleChangeAction
+	// Rule is the rule as changed, or as it would be on dry runs.
+	Rule AccessRule
+	// Err is set when the change failed or wasn't made because a rule
+	// with the same configuration isn't owned.
+	Err error
+}
+
+// AccessRulesSyncResult describes the outcome of SyncAccessRules.
+type AccessRulesSyncResult struct {
+	Results []AccessRuleResult
+	// Unchanged is the number of rules which already matched.
+	Unchanged int
+}
+
+// Err returns an error when any change failed.
+func (r AccessRulesSyncResult) Err() error {
+	var failed []AccessRuleResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d access rule changes failed: %s %s: %w",
+		len(failed), len(r.Results), failed[0].Action, failed[0].Rule.Configuration.Value, failed[0].Err)
+}
+
+type SyncAccessRulesParams struct {
+	// Owner identifies the rules managed by the sync, which are the rules
+	// whose notes are Owner or start with Owner followed by a colon. The
+	// notes of the rules are prefixed with it.
+	Owner string
+	Rules []AccessRule
+	// KeepUnlisted leaves owned rules missing from Rules in place instead
+	// of deleting them.
+	KeepUnlisted bool
+	// DryRun only computes the changes.
+	DryRun bool
+	// Concurrency is the number of rules changed at once, 4 by default.
+	Concurrency int
+}
+
+// AccessRuleOwnedNotes returns the notes of a rule owned by owner.
+func AccessRuleOwnedNotes(owner, notes string) string {
+	switch {
+	case notes == "":
+		return owner
+	case accessRuleOwned(owner, AccessRule{Notes: notes}):
+		return notes
+	default:
+		return owner + ": " + notes
+	}
+}
+
+func accessRuleOwned(owner string, rule AccessRule) bool {
+	return rule.Notes == owner || strings.HasPrefix(rule.Notes, owner+":")
+}
+
+func accessRuleKey(c AccessRuleConfiguration) string {
+	return c.Target + "/" + strings.ToLower(c.Value)
+}
+
+// SyncAccessRules makes the access rules of a user, zone or account owned
+// by params.Owner match params.Rules, matching rules by configuration.
+// Rules which aren't owned are never changed, and rules inherited from
+// other scopes are ignored.
+//
+// Changes are made concurrently and don't stop at the first failure: the
+// outcome of each one is in the results, whose Err method reports whether
+// any failed. The returned error is only set when the rules couldn't be
+// listed or params are invalid.
+func (api *API) SyncAccessRules(ctx context.Context, rc *ResourceContainer, params SyncAccessRulesParams) (AccessRulesSyncResult, error) {
+	if params.Owner == "" {
+		return AccessRulesSyncResult{}, ErrMissingAccessRulesOwner
+	}
+	prefix, err := accessRulePrefix(rc)
+	if err != nil {
+		return AccessRulesSyncResult{}, err
+	}
+
+	desired := make([]AccessRule, 0, len(params.Rules))
+	desiredIndex := map[string]int{}
+	for i, rule := range params.Rules {
+		rule, err := validateAccessRule(rule)
+		if err != nil {
+			return AccessRulesSyncResult{}, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rule = AccessRule{
+			Mode:          rule.Mode,
+			Notes:         AccessRuleOwnedNotes(params.Owner, rule.Notes),
+			Configuration: rule.Configuration,
+		}
+
+		key := accessRuleKey(rule.Configuration)
+		if j, ok := desiredIndex[key]; ok {
+			desired[j] = rule
+			continue
+		}
+		desiredIndex[key] = len(desired)
+		desired = append(desired, rule)
+	}
+
+	existing, err := api.ListAllAccessRules(ctx, rc, AccessRule{})
+	if err != nil {
+		return AccessRulesSyncResult{}, err
+	}
+	existingByKey := map[string]AccessRule{}
+	for _, rule := range existing {
+		if AccessRuleInScope(rc, rule) {
+			existingByKey[accessRuleKey(rule.Configuration)] = rule
+		}
+	}
+
+	var result AccessRulesSyncResult
+	for _, rule := range desired {
+		current, ok := existingByKey[accessRuleKey(rule.Configuration)]
+		switch {
+		case !ok:
+			result.Results = append(result.Results, AccessRuleResult{Action: AccessRuleCreate, Rule: rule})
+		case !accessRuleOwned(params.Owner, current):
+			result.Results = append(result.Results, AccessRuleResult{Action: AccessRuleUpdate, Rule: current, Err: ErrAccessRuleNotOwned})
+		case current.Mode != rule.Mode || current.Notes != rule.Notes:
+			rule.ID = current.ID
+			rule.Scope = current.Scope
+			result.Results = append(result.Results, AccessRuleResult{Action: AccessRuleUpdate, Rule: rule})
+		default:
+			result.Unchanged++
+		}
+	}
+	if !params.KeepUnlisted {
+		for _, rule := range existing {
+			_, wanted := desiredIndex[accessRuleKey(rule.Configuration)]
+			if !wanted && AccessRuleInScope(rc, rule) && accessRuleOwned(params.Owner, rule) {
+				result.Results = append(result.Results, AccessRuleResult{Action: AccessRuleDelete, Rule: rule})
+			}
+		}
+	}
+
+	if params.DryRun {
+		return result, nil
+	}
+
+	concurrency := params.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultAccessRulesConcurrency
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for i := range result.Results {
+		if result.Results[i].Err != nil {
+			continue
+		}
+		r := &result.Results[i]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			var response *AccessRuleResponse
+			switch r.Action {
+			case AccessRuleCreate:
+				response, r.Err = api.createAccessRule(ctx, prefix, r.Rule)
+			case AccessRuleUpdate:
+				response, r.Err = api.updateAccessRule(ctx, prefix, r.Rule.ID, AccessRule{Mode: r.Rule.Mode, Notes: r.Rule.Notes})
+			case AccessRuleDelete:
+				_, r.Err = api.deleteAccessRule(ctx, prefix, r.Rule.ID)
+			}
+			if r.Err == nil && response != nil {
+				r.Rule = response.Result
+			}
+		}()
+	}
+	wg.Wait()
+
+	return result, nil
+}
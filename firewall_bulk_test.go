@@ -0,0 +1,183 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessRuleConfigurationFromValue(t *testing.T) {
+	testCases := map[string]AccessRuleConfiguration{
+		"192.0.2.1":        {Target: "ip", Value: "192.0.2.1"},
+		"2001:db8::1":      {Target: "ip6", Value: "2001:db8::1"},
+		"192.0.2.77/24":    {Target: "ip_range", Value: "192.0.2.0/24"},
+		"13335":            {Target: "asn", Value: "AS13335"},
+		"as13335":          {Target: "asn", Value: "AS13335"},
+		"gb":               {Target: "country", Value: "GB"},
+		"not a real value": {},
+	}
+
+	for in, want := range testCases {
+		got, err := AccessRuleConfigurationFromValue(in)
+		if want.Target == "" {
+			assert.ErrorIs(t, err, ErrInvalidAccessRule, in)
+			continue
+		}
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+}
+
+func TestAccessRuleInScope(t *testing.T) {
+	zoneRule := AccessRule{Scope: AccessRuleScope{Type: "zone"}}
+	accountRule := AccessRule{Scope: AccessRuleScope{Type: "account"}}
+	userRule := AccessRule{Scope: AccessRuleScope{Type: "user"}}
+
+	assert.True(t, AccessRuleInScope(ZoneIdentifier(testZoneID), zoneRule))
+	assert.False(t, AccessRuleInScope(ZoneIdentifier(testZoneID), accountRule))
+	assert.False(t, AccessRuleInScope(ZoneIdentifier(testZoneID), userRule))
+	assert.True(t, AccessRuleInScope(AccountIdentifier(testAccountID), accountRule))
+	assert.True(t, AccessRuleInScope(UserIdentifier("user"), userRule))
+}
+
+func TestReadWriteAccessRules(t *testing.T) {
+	rules, err := ReadAccessRules(strings.NewReader(`mode,value,notes,target
+block,192.0.2.1,scanner,
+challenge,AS64496,,
+whitelist,198.51.100.0/24,office,ip_range
+`), AccessRulesFormatCSV)
+	require.NoError(t, err)
+	want := []AccessRule{
+		{Mode: "block", Notes: "scanner", Configuration: AccessRuleConfiguration{Target: "ip", Value: "192.0.2.1"}},
+		{Mode: "challenge", Configuration: AccessRuleConfiguration{Target: "asn", Value: "AS64496"}},
+		{Mode: "whitelist", Notes: "office", Configuration: AccessRuleConfiguration{Target: "ip_range", Value: "198.51.100.0/24"}},
+	}
+	assert.Equal(t, want, rules)
+
+	for _, format := range []AccessRulesFormat{AccessRulesFormatCSV, AccessRulesFormatJSON} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteAccessRules(&buf, format, rules))
+		read, err := ReadAccessRules(&buf, format)
+		require.NoError(t, err, format)
+		assert.Equal(t, want, read, format)
+	}
+
+	_, err = ReadAccessRules(strings.NewReader(`[{"mode": "tarpit", "configuration": {"target": "ip", "value": "192.0.2.1"}}]`), AccessRulesFormatJSON)
+	assert.ErrorIs(t, err, ErrInvalidAccessRule)
+
+	_, err = ReadAccessRules(strings.NewReader("value\n192.0.2.1\n"), AccessRulesFormatCSV)
+	assert.ErrorIs(t, err, ErrInvalidAccessRule)
+
+	_, err = ReadAccessRules(strings.NewReader(""), "xml")
+	assert.ErrorIs(t, err, ErrUnknownAccessRulesFormat)
+}
+
+func TestSyncAccessRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	mux.HandleFunc("/zones/"+testZoneID+"/firewall/access_rules/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "1", r.URL.Query().Get("page"))
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": "r1", "mode": "block", "notes": "sync", "configuration": {"target": "ip", "value": "192.0.2.1"}, "scope": {"type": "zone"}},
+					{"id": "r2", "mode": "block", "notes": "sync: old", "configuration": {"target": "ip", "value": "192.0.2.2"}, "scope": {"type": "zone"}},
+					{"id": "r3", "mode": "block", "notes": "sync", "configuration": {"target": "ip", "value": "192.0.2.3"}, "scope": {"type": "zone"}},
+					{"id": "r4", "mode": "block", "notes": "manual", "configuration": {"target": "ip", "value": "192.0.2.4"}, "scope": {"type": "zone"}},
+					{"id": "r5", "mode": "block", "notes": "sync", "configuration": {"target": "ip", "value": "192.0.2.5"}, "scope": {"type": "organization"}}
+				],
+				"result_info": {"page": 1, "per_page": 100, "total_pages": 1, "count": 5, "total_count": 5}
+			}`)
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			requests = append(requests, "POST "+string(body))
+			mu.Unlock()
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "new", "mode": "challenge", "notes": "sync", "configuration": {"target": "country", "value": "XX"}}}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	mux.HandleFunc("/zones/"+testZoneID+"/firewall/access_rules/rules/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/zones/"+testZoneID+"/firewall/access_rules/rules/")+" "+string(body))
+		mu.Unlock()
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodDelete {
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "r3"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "r2", "mode": "challenge", "notes": "sync: updated", "configuration": {"target": "ip", "value": "192.0.2.2"}}}`)
+	})
+
+	params := SyncAccessRulesParams{
+		Owner: "sync",
+		Rules: []AccessRule{
+			{Mode: "block", Configuration: AccessRuleConfiguration{Value: "192.0.2.1"}},
+			{Mode: "challenge", Notes: "updated", Configuration: AccessRuleConfiguration{Value: "192.0.2.2"}},
+			{Mode: "block", Configuration: AccessRuleConfiguration{Value: "192.0.2.4"}},
+			{Mode: "challenge", Configuration: AccessRuleConfiguration{Value: "xx"}},
+		},
+		DryRun: true,
+	}
+
+	result, err := client.SyncAccessRules(context.Background(), ZoneIdentifier(testZoneID), params)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, []AccessRuleResult{
+		{
+			Action: AccessRuleUpdate,
+			Rule:   AccessRule{ID: "r2", Mode: "challenge", Notes: "sync: updated", Configuration: AccessRuleConfiguration{Target: "ip", Value: "192.0.2.2"}, Scope: AccessRuleScope{Type: "zone"}},
+		},
+		{
+			Action: AccessRuleUpdate,
+			Rule:   AccessRule{ID: "r4", Mode: "block", Notes: "manual", Configuration: AccessRuleConfiguration{Target: "ip", Value: "192.0.2.4"}, Scope: AccessRuleScope{Type: "zone"}},
+			Err:    ErrAccessRuleNotOwned,
+		},
+		{
+			Action: AccessRuleCreate,
+			Rule:   AccessRule{Mode: "challenge", Notes: "sync", Configuration: AccessRuleConfiguration{Target: "country", Value: "XX"}},
+		},
+		{
+			Action: AccessRuleDelete,
+			Rule:   AccessRule{ID: "r3", Mode: "block", Notes: "sync", Configuration: AccessRuleConfiguration{Target: "ip", Value: "192.0.2.3"}, Scope: AccessRuleScope{Type: "zone"}},
+		},
+	}, result.Results)
+	assert.Empty(t, requests)
+	assert.ErrorIs(t, result.Err(), ErrAccessRuleNotOwned)
+
+	params.DryRun = false
+	params.Concurrency = 2
+	result, err = client.SyncAccessRules(context.Background(), ZoneIdentifier(testZoneID), params)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		`PATCH r2 {"notes":"sync: updated","mode":"challenge","configuration":{},"scope":{},"created_on":"0001-01-01T00:00:00Z","modified_on":"0001-01-01T00:00:00Z"}`,
+		`POST {"notes":"sync","mode":"challenge","configuration":{"target":"country","value":"XX"},"scope":{},"created_on":"0001-01-01T00:00:00Z","modified_on":"0001-01-01T00:00:00Z"}`,
+		"DELETE r3 ",
+	}, requests)
+	assert.Equal(t, "new", result.Results[2].Rule.ID)
+	assert.Equal(t, "r3", result.Results[3].Rule.ID)
+
+	_, err = client.SyncAccessRules(context.Background(), ZoneIdentifier(testZoneID), SyncAccessRulesParams{})
+	assert.ErrorIs(t, err, ErrMissingAccessRulesOwner)
+}
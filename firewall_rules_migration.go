@@ -0,0 +1,224 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// FirewallRuleMigrationIssue is a firewall rule which couldn't be converted
+// to a custom rule.
+type FirewallRuleMigrationIssue struct {
+	FirewallRuleID string
+	Description    string
+	Reason         string
+}
+
+// FirewallRulesMigrationReport describes the outcome of converting firewall
+// rules to custom rules.
+type FirewallRulesMigrationReport struct {
+	// Converted maps the ID of each converted firewall rule to the ref of
+	// the custom rule replacing it.
+	Converted map[string]string
+	// Unconvertible lists the firewall rules left out of the custom rules.
+	Unconvertible []FirewallRuleMigrationIssue
+	// Existing lists the refs of converted rules which were already in the
+	// entrypoint ruleset and were left as is.
+	Existing []string
+}
+
+// firewallRuleProducts maps the products firewall rules can bypass to the
+// products skip rules take.
+var firewallRuleProducts = map[string]RulesetActionParameterProduct{
+	"bic":           RulesetActionParameterProductBIC,
+	"hot":           RulesetActionParameterProductHOT,
+	"rateLimit":     RulesetActionParameterProductRateLimit,
+	"securityLevel": RulesetActionParameterProductSecurityLevel,
+	"uaBlock":       RulesetActionParameterProductUABlock,
+	"waf":           RulesetActionParameterProductWAF,
+	"zoneLockdown":  RulesetActionParameterProductZoneLockdown,
+}
+
+// firewallRuleActionOrder is the order in which firewall rules without a
+// priority are evaluated.
+var firewallRuleActionOrder = map[string]int{
+	"log":               0,
+	"bypass":            1,
+	"allow":             2,
+	"managed_challenge": 3,
+	"challenge":         4,
+	"js_challenge":      5,
+	"block":             6,
+}
+
+// ConvertFirewallRules converts firewall rules and their filters to rules of
+// the http_request_firewall_custom phase, in the order the firewall rules
+// are evaluated: by priority, then rules without a priority by action.
+//
+// Actions map to the custom rule action of the same name except allow,
+// which skips the remaining custom rules, and bypass, which skips the
+// products of the firewall rule. Paused rules or filters give disabled
+// rules. The ref of each rule is the firewall rule ref or, when it has
+// none, its ID so that the conversion can be run again.
+func ConvertFirewallRules(rules []FirewallRule) ([]RulesetRule, FirewallRulesMigrationReport) {
+	report := FirewallRulesMigrationReport{Converted: map[string]string{}}
+
+	ordered := make([]FirewallRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, iok := firewallRulePriority(ordered[i])
+		pj, jok := firewallRulePriority(ordered[j])
+		if iok != jok {
+			return iok
+		}
+		if iok {
+			return pi < pj
+		}
+		return firewallRuleActionOrder[ordered[i].Action] < firewallRuleActionOrder[ordered[j].Action]
+	})
+
+	var converted []RulesetRule
+	for _, fr := range ordered {
+		rule, err := convertFirewallRule(fr)
+		if err != nil {
+			report.Unconvertible = append(report.Unconvertible, FirewallRuleMigrationIssue{
+				FirewallRuleID: fr.ID,
+				Description:    fr.Description,
+				Reason:         err.Error(),
+			})
+			continue
+		}
+		report.Converted[fr.ID] = rule.Ref
+		converted = append(converted, rule)
+	}
+
+	return converted, report
+}
+
+// firewallRulePriority returns the priority of a firewall rule and whether
+// it has one.
+func firewallRulePriority(fr FirewallRule) (float64, bool) {
+	switch p := fr.Priority.(type) {
+	case float64:
+		return p, true
+	case int:
+		return float64(p), true
+	default:
+		return 0, false
+	}
+}
+
+func convertFirewallRule(fr FirewallRule) (RulesetRule, error) {
+	if fr.Filter.Expression == "" {
+		return RulesetRule{}, fmt.Errorf("filter %q has no expression", fr.Filter.ID)
+	}
+
+	rule := RulesetRule{
+		Expression:  fr.Filter.Expression,
+		Description: fr.Description,
+		Ref:         fr.Ref,
+	}
+	if rule.Ref == "" {
+		rule.Ref = fr.ID
+	}
+	if rule.Description == "" {
+		rule.Description = fr.Filter.Description
+	}
+	if fr.Paused || fr.Filter.Paused {
+		rule.Enabled = BoolPtr(false)
+	}
+
+	switch fr.Action {
+	case "block", "challenge", "js_challenge", "managed_challenge", "log":
+		rule.Action = fr.Action
+	case "allow":
+		rule.Action = string(RulesetRuleActionSkip)
+		rule.ActionParameters = &RulesetRuleActionParameters{Ruleset: "current"}
+	case "bypass":
+		if len(fr.Products) == 0 {
+			return RulesetRule{}, fmt.Errorf("bypass rule has no products")
+		}
+		params := &RulesetRuleActionParameters{}
+		for _, product := range fr.Products {
+			p, ok := firewallRuleProducts[product]
+			if !ok {
+				return RulesetRule{}, fmt.Errorf("bypass of product %q has no custom rule equivalent", product)
+			}
+			params.Products = append(params.Products, string(p))
+		}
+		rule.Action = string(RulesetRuleActionSkip)
+		rule.ActionParameters = params
+	default:
+		return RulesetRule{}, fmt.Errorf("action %q has no custom rule equivalent", fr.Action)
+	}
+
+	return rule, nil
+}
+
+type MigrateFirewallRulesParams struct {
+	// DryRun converts the firewall rules without updating the entrypoint
+	// ruleset.
+	DryRun bool
+}
+
+// MigrateFirewallRules converts the firewall rules of a zone with
+// ConvertFirewallRules and appends them to the http_request_firewall_custom
+// entrypoint ruleset of the zone. Rules already in the entrypoint, matched
+// by ref, aren't added again. Firewall rules are left in place and should
+// be deleted once the custom rules are checked.
+//
+// It returns the rules of the entrypoint ruleset, which are the rules it
+// would have when DryRun is set.
+func (api *API) MigrateFirewallRules(ctx context.Context, rc *ResourceContainer, params MigrateFirewallRulesParams) ([]RulesetRule, FirewallRulesMigrationReport, error) {
+	if rc.Level != ZoneRouteLevel {
+		return nil, FirewallRulesMigrationReport{}, fmt.Errorf(errInvalidResourceContainerAccess, rc.Level)
+	}
+
+	firewallRules, _, err := api.FirewallRules(ctx, rc, FirewallRuleListParams{})
+	if err != nil {
+		return nil, FirewallRulesMigrationReport{}, err
+	}
+
+	converted, report := ConvertFirewallRules(firewallRules)
+
+	phase := string(RulesetPhaseHTTPRequestFirewallCustom)
+	entrypoint, err := api.GetEntrypointRuleset(ctx, rc, phase)
+	if err != nil {
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, report, err
+		}
+	}
+
+	refs := map[string]bool{}
+	for _, r := range entrypoint.Rules {
+		if r.Ref != "" {
+			refs[r.Ref] = true
+		}
+	}
+
+	rules := entrypoint.Rules
+	for _, r := range converted {
+		if refs[r.Ref] {
+			report.Existing = append(report.Existing, r.Ref)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	if params.DryRun || len(rules) == len(entrypoint.Rules) {
+		return rules, report, nil
+	}
+
+	updated, err := api.UpdateEntrypointRuleset(ctx, rc, UpdateEntrypointRulesetParams{
+		Phase:       phase,
+		Description: entrypoint.Description,
+		Rules:       rules,
+	})
+	if err != nil {
+		return nil, report, err
+	}
+
+	return updated.Rules, report, nil
+}
@@ -0,0 +1,119 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertFirewallRules(t *testing.T) {
+	rules, report := ConvertFirewallRules([]FirewallRule{
+		{ID: "b1", Action: "block", Filter: Filter{Expression: "ip.src eq 192.0.2.1"}},
+		{ID: "l1", Action: "log", Filter: Filter{Expression: "true"}},
+		{ID: "a1", Action: "allow", Priority: float64(2), Filter: Filter{Expression: "ip.src eq 192.0.2.2", Description: "office"}},
+		{ID: "p1", Action: "bypass", Priority: 1, Products: []string{"waf", "uaBlock"}, Paused: true, Filter: Filter{Expression: "http.host eq \"a\""}},
+		{ID: "x1", Action: "bypass", Products: []string{"unknown"}, Filter: Filter{Expression: "true"}},
+		{ID: "e1", Ref: "empty", Action: "block"},
+	})
+
+	assert.Equal(t, []RulesetRule{
+		{
+			Action:           "skip",
+			ActionParameters: &RulesetRuleActionParameters{Products: []string{"waf", "uablock"}},
+			Expression:       "http.host eq \"a\"",
+			Ref:              "p1",
+			Enabled:          BoolPtr(false),
+		},
+		{
+			Action:           "skip",
+			ActionParameters: &RulesetRuleActionParameters{Ruleset: "current"},
+			Expression:       "ip.src eq 192.0.2.2",
+			Description:      "office",
+			Ref:              "a1",
+		},
+		{Action: "log", Expression: "true", Ref: "l1"},
+		{Action: "block", Expression: "ip.src eq 192.0.2.1", Ref: "b1"},
+	}, rules)
+
+	assert.Equal(t, map[string]string{"p1": "p1", "a1": "a1", "l1": "l1", "b1": "b1"}, report.Converted)
+	require.Len(t, report.Unconvertible, 2)
+	assert.Equal(t, "x1", report.Unconvertible[0].FirewallRuleID)
+	assert.Equal(t, `bypass of product "unknown" has no custom rule equivalent`, report.Unconvertible[0].Reason)
+	assert.Equal(t, "e1", report.Unconvertible[1].FirewallRuleID)
+}
+
+func TestMigrateFirewallRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/firewall/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"result": [
+				{"id": "fr1", "action": "block", "priority": 1, "paused": false, "filter": {"id": "f1", "expression": "ip.src eq 192.0.2.1", "paused": false}},
+				{"id": "fr2", "action": "challenge", "priority": 2, "paused": false, "filter": {"id": "f2", "expression": "cf.threat_score gt 10", "paused": false}}
+			],
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result_info": {"page": 1, "per_page": 50, "count": 2, "total_count": 2, "total_pages": 1}
+		}`)
+	})
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/phases/http_request_firewall_custom/entrypoint", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{
+				"result": {
+					"id": "2c0fc9fa937b11eaa1b71c4d701ab86e",
+					"description": "custom rules",
+					"phase": "http_request_firewall_custom",
+					"rules": [{"id": "1", "ref": "fr1", "action": "block", "expression": "ip.src eq 192.0.2.1"}]
+				},
+				"success": true,
+				"errors": [],
+				"messages": []
+			}`)
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{
+				"description": "custom rules",
+				"rules": [
+					{"id": "1", "ref": "fr1", "action": "block", "expression": "ip.src eq 192.0.2.1"},
+					{"ref": "fr2", "action": "challenge", "expression": "cf.threat_score gt 10"}
+				]
+			}`, string(body))
+			fmt.Fprint(w, `{
+				"result": {
+					"id": "2c0fc9fa937b11eaa1b71c4d701ab86e",
+					"rules": [
+						{"id": "1", "ref": "fr1", "action": "block", "expression": "ip.src eq 192.0.2.1"},
+						{"id": "2", "ref": "fr2", "action": "challenge", "expression": "cf.threat_score gt 10"}
+					]
+				},
+				"success": true,
+				"errors": [],
+				"messages": []
+			}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	rules, report, err := client.MigrateFirewallRules(context.Background(), ZoneIdentifier(testZoneID), MigrateFirewallRulesParams{})
+	require.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "2", rules[1].ID)
+	assert.Equal(t, []string{"fr1"}, report.Existing)
+	assert.Empty(t, report.Unconvertible)
+
+	_, _, err = client.MigrateFirewallRules(context.Background(), AccountIdentifier(testAccountID), MigrateFirewallRulesParams{})
+	assert.Error(t, err)
+}
@@ -16,6 +16,8 @@ import (
 var (
 	ErrInvalidImagesAPIVersion = errors.New("invalid images API version")
 	ErrMissingImageID          = errors.New("required image ID missing")
+
+	errMissingImageUpload = errors.New("a file or url to upload must be specified")
 )
 
 type ImagesAPIVersion string
@@ -48,7 +50,7 @@ type UploadImageParams struct {
 // it can be used in an HTTP request.
 func (b UploadImageParams) write(mpw *multipart.Writer) error {
 	if b.File == nil && b.URL == "" {
-		return errors.New("a file or url to upload must be specified")
+		return errMissingImageUpload
 	}
 
 	if b.File != nil {
@@ -155,7 +157,8 @@ type ListImagesParams struct {
 	ResultInfo
 }
 
-// UploadImage uploads a single image.
+// UploadImage uploads a single image. The file is streamed, so a failed
+// upload isn't retried.
 //
 // API Reference: https://api.cloudflare.com/#cloudflare-images-upload-an-image-using-a-single-http-request
 func (api *API) UploadImage(ctx context.Context, rc *ResourceContainer, params UploadImageParams) (Image, error) {
@@ -167,21 +170,34 @@ func (api *API) UploadImage(ctx context.Context, rc *ResourceContainer, params U
 		return Image{}, errors.New("file and url uploads are mutually exclusive and can only be performed individually")
 	}
 
+	if params.File == nil && params.URL == "" {
+		return Image{}, fmt.Errorf("error writing multipart body: %w", errMissingImageUpload)
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/images/v1", rc.Identifier)
 
-	body := &bytes.Buffer{}
-	w := multipart.NewWriter(body)
-	if err := params.write(w); err != nil {
-		_ = w.Close()
-		return Image{}, fmt.Errorf("error writing multipart body: %w", err)
-	}
-	_ = w.Close()
+	// The multipart body is streamed to the request as it is written so
+	// large files are never held in memory.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	w := multipart.NewWriter(pw)
+	go func() {
+		err := params.write(w)
+		if err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			err = fmt.Errorf("error writing multipart body: %w", err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
 
-	res, err := api.makeRequestContextWithHeaders(
+	// The pipe can only be read once, so the request must not be retried.
+	body, err := api.makeRequestStream(
 		ctx,
 		http.MethodPost,
 		uri,
-		body,
+		pr,
 		http.Header{
 			"Accept":       []string{"application/json"},
 			"Content-Type": []string{w.FormDataContentType()},
@@ -190,9 +206,10 @@ func (api *API) UploadImage(ctx context.Context, rc *ResourceContainer, params U
 	if err != nil {
 		return Image{}, err
 	}
+	defer body.Close()
 
 	var imageDetailsResponse ImageDetailsResponse
-	err = json.Unmarshal(res, &imageDetailsResponse)
+	err = json.NewDecoder(body).Decode(&imageDetailsResponse)
 	if err != nil {
 		return Image{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
@@ -360,6 +377,24 @@ func (api *API) GetBaseImage(ctx context.Context, rc *ResourceContainer, id stri
 	return res, nil
 }
 
+// GetBaseImageStream gets the base image used to derive variants without
+// buffering it. The caller is responsible for closing the returned reader.
+//
+// API Reference: https://api.cloudflare.com/#cloudflare-images-base-image
+func (api *API) GetBaseImageStream(ctx context.Context, rc *ResourceContainer, id string) (io.ReadCloser, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if id == "" {
+		return nil, ErrMissingImageID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/%s/blob", rc.Identifier, id)
+
+	return api.makeRequestStream(ctx, http.MethodGet, uri, nil, nil)
+}
+
 // DeleteImage deletes an image.
 //
 // API Reference: https://api.cloudflare.com/#cloudflare-images-delete-image
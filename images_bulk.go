@@ -0,0 +1,139 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const imagesBulkDefaultConcurrency = 5
+
+// imagesUploadExtensions are the file types accepted by Images.
+var imagesUploadExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg"}
+
+var ErrMissingBulkImages = errors.New("a directory or urls to upload must be specified")
+
+// BulkUploadImagesParams configures BulkUploadImages. Every image file in
+// Directory and its subdirectories and every URL in URLs is uploaded.
+type BulkUploadImagesParams struct {
+	Directory         string
+	URLs              []string
+	RequireSignedURLs bool
+	Metadata          map[string]interface{}
+
+	// Concurrency is the number of uploads in flight. Defaults to 5.
+	Concurrency int
+
+	// OnResult is called as each upload finishes.
+	OnResult func(ImageUploadResult)
+}
+
+// ImageUploadResult is the outcome of uploading one file or URL.
+type ImageUploadResult struct {
+	// Source is the path or URL the image was uploaded from.
+	Source string
+	Image  Image
+	Err    error
+}
+
+// BulkUploadImages uploads many images using UploadImage. A failed upload
+// doesn't stop the others; the returned results, in the order files were
+// found followed by URLs, hold the error of each upload. An error is only
+// returned when the images to upload can't be determined.
+func (api *API) BulkUploadImages(ctx context.Context, rc *ResourceContainer, params BulkUploadImagesParams) ([]ImageUploadResult, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if params.Directory == "" && len(params.URLs) == 0 {
+		return nil, ErrMissingBulkImages
+	}
+
+	var files []string
+	if params.Directory != "" {
+		err := filepath.WalkDir(params.Directory, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+			if contains(imagesUploadExtensions, strings.ToLower(filepath.Ext(path))) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	concurrency := params.Concurrency
+	if concurrency < 1 {
+		concurrency = imagesBulkDefaultConcurrency
+	}
+
+	results := make([]ImageUploadResult, len(files)+len(params.URLs))
+	sources := append(append([]string{}, files...), params.URLs...)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, concurrency)
+	)
+	for i, source := range sources {
+		i, source, isFile := i, source, i < len(files)
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = ImageUploadResult{Source: source, Err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			result := ImageUploadResult{Source: source}
+			result.Image, result.Err = api.uploadBulkImage(ctx, rc, params, source, isFile)
+			results[i] = result
+
+			if params.OnResult != nil {
+				mu.Lock()
+				params.OnResult(result)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func (api *API) uploadBulkImage(ctx context.Context, rc *ResourceContainer, params BulkUploadImagesParams, source string, isFile bool) (Image, error) {
+	upload := UploadImageParams{
+		RequireSignedURLs: params.RequireSignedURLs,
+		Metadata:          params.Metadata,
+	}
+
+	if isFile {
+		f, err := os.Open(source)
+		if err != nil {
+			return Image{}, err
+		}
+		upload.File = f
+		upload.Name = filepath.Base(source)
+	} else {
+		upload.URL = source
+	}
+
+	return api.UploadImage(ctx, rc, upload)
+}
@@ -0,0 +1,81 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkUploadImages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	for _, name := range []string{"a.png", "nested/b.JPG", "notes.txt", ".hidden.png"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(name), 0o600))
+	}
+
+	var mu sync.Mutex
+	uploaded := map[string]bool{}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "true", r.FormValue("requireSignedURLs"))
+
+		source := r.FormValue("url")
+		if f, header, err := r.FormFile("file"); err == nil {
+			f.Close()
+			source = header.Filename
+		}
+
+		mu.Lock()
+		uploaded[source] = true
+		mu.Unlock()
+
+		w.Header().Set("content-type", "application/json")
+		if source == "https://example.com/broken.png" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 5400, "message": "Bad request: image could not be fetched"}], "messages": [], "result": null}`)
+			return
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": %q, "filename": %q, "requireSignedURLs": true, "variants": []}}`, source, source)
+	})
+
+	var callbacks int
+	results, err := client.BulkUploadImages(context.Background(), AccountIdentifier(testAccountID), BulkUploadImagesParams{
+		Directory:         dir,
+		URLs:              []string{"https://example.com/c.png", "https://example.com/broken.png"},
+		RequireSignedURLs: true,
+		Concurrency:       2,
+		OnResult:          func(ImageUploadResult) { callbacks++ },
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, 4, callbacks)
+
+	assert.Equal(t, filepath.Join(dir, "a.png"), results[0].Source)
+	assert.Equal(t, "a.png", results[0].Image.ID)
+	assert.Equal(t, filepath.Join(dir, "nested", "b.JPG"), results[1].Source)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "https://example.com/c.png", results[2].Image.ID)
+	assert.Error(t, results[3].Err)
+
+	assert.Equal(t, map[string]bool{"a.png": true, "b.JPG": true, "https://example.com/c.png": true, "https://example.com/broken.png": true}, uploaded)
+}
+
+func TestBulkUploadImages_NothingToUpload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.BulkUploadImages(context.Background(), AccountIdentifier(testAccountID), BulkUploadImagesParams{})
+	assert.ErrorIs(t, err, ErrMissingBulkImages)
+}
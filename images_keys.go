@@ -0,0 +1,148 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var ErrMissingImagesSigningKeyName = errors.New("required images signing key name missing")
+
+// ImagesSigningKey is a key used to sign URLs of images requiring signed URLs,
+// see ImageURLSigner.
+type ImagesSigningKey struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ImagesBatchToken authenticates uploads against the Images batch API,
+// which allows a higher request rate than the regular API.
+type ImagesBatchToken struct {
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type RotateImagesSigningKeyParams struct {
+	// NewKeyName is the name of the key to create.
+	NewKeyName string
+	// OldKeyName is the key removed once the new key exists. Removing the
+	// "default" key generates a new default value rather than deleting it.
+	OldKeyName string
+}
+
+type ImagesSigningKeysResponse struct {
+	Result struct {
+		Keys []ImagesSigningKey `json:"keys"`
+	} `json:"result"`
+	Response
+}
+
+type ImagesBatchTokenResponse struct {
+	Result ImagesBatchToken `json:"result"`
+	Response
+}
+
+// ListImagesSigningKeys lists the URL signing keys of an account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-keys-list-signing-keys
+func (api *API) ListImagesSigningKeys(ctx context.Context, rc *ResourceContainer) ([]ImagesSigningKey, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/keys", rc.Identifier)
+
+	return api.imagesSigningKeysRequest(ctx, http.MethodGet, uri)
+}
+
+// CreateImagesSigningKey creates a URL signing key and returns all keys of
+// the account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-keys-add-signing-key
+func (api *API) CreateImagesSigningKey(ctx context.Context, rc *ResourceContainer, name string) ([]ImagesSigningKey, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if name == "" {
+		return nil, ErrMissingImagesSigningKeyName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/keys/%s", rc.Identifier, name)
+
+	return api.imagesSigningKeysRequest(ctx, http.MethodPut, uri)
+}
+
+// DeleteImagesSigningKey deletes a URL signing key and returns the remaining
+// keys of the account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-keys-delete-signing-key
+func (api *API) DeleteImagesSigningKey(ctx context.Context, rc *ResourceContainer, name string) ([]ImagesSigningKey, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if name == "" {
+		return nil, ErrMissingImagesSigningKeyName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/keys/%s", rc.Identifier, name)
+
+	return api.imagesSigningKeysRequest(ctx, http.MethodDelete, uri)
+}
+
+// RotateImagesSigningKey creates a new signing key and then removes the old
+// one, so there is no moment without a valid key. URLs signed with the old
+// key stop working once it is removed.
+func (api *API) RotateImagesSigningKey(ctx context.Context, rc *ResourceContainer, params RotateImagesSigningKeyParams) ([]ImagesSigningKey, error) {
+	if params.NewKeyName == "" || params.OldKeyName == "" {
+		return nil, ErrMissingImagesSigningKeyName
+	}
+
+	if _, err := api.CreateImagesSigningKey(ctx, rc, params.NewKeyName); err != nil {
+		return nil, err
+	}
+
+	return api.DeleteImagesSigningKey(ctx, rc, params.OldKeyName)
+}
+
+func (api *API) imagesSigningKeysRequest(ctx context.Context, method, uri string) ([]ImagesSigningKey, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var r ImagesSigningKeysResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result.Keys, nil
+}
+
+// CreateImagesBatchToken creates a token for the Images batch API.
+//
+// API Reference: https://developers.cloudflare.com/images/upload-images/images-batch/
+func (api *API) CreateImagesBatchToken(ctx context.Context, rc *ResourceContainer) (ImagesBatchToken, error) {
+	if rc.Level != AccountRouteLevel {
+		return ImagesBatchToken{}, ErrRequiredAccountLevelResourceContainer
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/batch_token", rc.Identifier)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return ImagesBatchToken{}, err
+	}
+
+	var r ImagesBatchTokenResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return ImagesBatchToken{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,82 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagesSigningKeys(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keys := map[string]string{"default": "Oix0bbNaT8Rge9PuyxUBrjI6zrgnsyJ5="}
+	respond := func(w http.ResponseWriter) {
+		w.Header().Set("content-type", "application/json")
+		var list string
+		for _, name := range []string{"default", "rotated"} {
+			if v, ok := keys[name]; ok {
+				if list != "" {
+					list += ","
+				}
+				list += fmt.Sprintf(`{"name": %q, "value": %q}`, name, v)
+			}
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"keys": [%s]}}`, list)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/keys", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		respond(w)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/keys/rotated", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		keys["rotated"] = "6Hq1NwPTk5VDsUTSNwprF5x2AqSXXYNe="
+		respond(w)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/keys/default", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		_, created := keys["rotated"]
+		assert.True(t, created, "old key deleted before the new key was created")
+		delete(keys, "default")
+		respond(w)
+	})
+
+	got, err := client.ListImagesSigningKeys(context.Background(), AccountIdentifier(testAccountID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []ImagesSigningKey{{Name: "default", Value: "Oix0bbNaT8Rge9PuyxUBrjI6zrgnsyJ5="}}, got)
+	}
+
+	got, err = client.RotateImagesSigningKey(context.Background(), AccountIdentifier(testAccountID), RotateImagesSigningKeyParams{
+		NewKeyName: "rotated",
+		OldKeyName: "default",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []ImagesSigningKey{{Name: "rotated", Value: "6Hq1NwPTk5VDsUTSNwprF5x2AqSXXYNe="}}, got)
+	}
+
+	_, err = client.CreateImagesSigningKey(context.Background(), AccountIdentifier(testAccountID), "")
+	assert.ErrorIs(t, err, ErrMissingImagesSigningKeyName)
+}
+
+func TestCreateImagesBatchToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/batch_token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"token": "batch-token", "expiresAt": "2023-08-09T15:33:56.273411222Z"}}`)
+	})
+
+	expiresAt, _ := time.Parse(time.RFC3339Nano, "2023-08-09T15:33:56.273411222Z")
+
+	got, err := client.CreateImagesBatchToken(context.Background(), AccountIdentifier(testAccountID))
+	if assert.NoError(t, err) {
+		assert.Equal(t, ImagesBatchToken{Token: "batch-token", ExpiresAt: &expiresAt}, got)
+	}
+}
@@ -0,0 +1,29 @@
+package cloudflare
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignImageURL(t *testing.T) {
+	deliveryURL := ImageDeliveryURL("ZWd9g1K7eljCn_KDTu_MWA", "ZxR0pLaXRldlBtaFhhOWxteVNyUg", "public")
+	assert.Equal(t, "https://imagedelivery.net/ZWd9g1K7eljCn_KDTu_MWA/ZxR0pLaXRldlBtaFhhOWxteVNyUg/public", deliveryURL)
+
+	signed, err := SignImageURL(deliveryURL, "signing-key", time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("signing-key"))
+	mac.Write([]byte("/ZWd9g1K7eljCn_KDTu_MWA/ZxR0pLaXRldlBtaFhhOWxteVNyUg/public?exp=1700000000"))
+	assert.Equal(t, deliveryURL+"?exp=1700000000&sig="+hex.EncodeToString(mac.Sum(nil)), signed)
+}
+
+func TestSignImageURL_MissingKey(t *testing.T) {
+	_, err := SignImageURL("https://imagedelivery.net/ZWd9g1K7eljCn_KDTu_MWA/ZxR0pLaXRldlBtaFhhOWxteVNyUg/public", "", time.Time{})
+	assert.ErrorIs(t, err, ErrMissingImagesSigningKey)
+}
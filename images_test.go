@@ -97,6 +97,31 @@ func TestUploadImage(t *testing.T) {
 	}
 }
 
+func TestUploadImage_NotRetried(t *testing.T) {
+	setup(UsingRetryPolicy(2, 0, 1))
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, err := parseImageMultipartUpload(r)
+		assert.NoError(t, err)
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"success": false, "errors": [], "messages": [], "result": null}`)
+	})
+
+	_, err := client.UploadImage(context.Background(), AccountIdentifier(testAccountID), UploadImageParams{
+		File: fakeFile{Buffer: bytes.NewBufferString("this is definitely an image")},
+		Name: "avatar.png",
+	})
+
+	var serviceErr *ServiceError
+	assert.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, 1, requests)
+}
+
 func TestUploadImageByUrl(t *testing.T) {
 	setup()
 	defer teardown()
@@ -423,6 +448,98 @@ func TestBaseImage(t *testing.T) {
 	}
 }
 
+func TestBaseImageStream(t *testing.T) {
+	setup()
+	defer teardown()
+
+	image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 1024)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "image/png")
+		_, _ = w.Write(image)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/ZxR0pLaXRldlBtaFhhO2FiZGVnaA/blob", handler)
+
+	body, err := client.GetBaseImageStream(context.Background(), AccountIdentifier(testAccountID), "ZxR0pLaXRldlBtaFhhO2FiZGVnaA")
+	require.NoError(t, err)
+	defer body.Close()
+
+	actual, err := io.ReadAll(body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, image, actual)
+	}
+}
+
+func TestBaseImageStream_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 5404, "message": "Image not found"}], "messages": [], "result": null}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/missing/blob", handler)
+
+	_, err := client.GetBaseImageStream(context.Background(), AccountIdentifier(testAccountID), "missing")
+	var notFound *NotFoundError
+	if assert.ErrorAs(t, err, &notFound) {
+		assert.Equal(t, []int{5404}, notFound.ErrorCodes())
+	}
+}
+
+func TestUploadImage_Streamed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const size = 8 << 20
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+
+		mr, err := r.MultipartReader()
+		require.NoError(t, err)
+		part, err := mr.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "file", part.FormName())
+		n, err := io.Copy(io.Discard, part)
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), n)
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "ZxR0pLaXRldlBtaFhhO2FiZGVnaA", "filename": "large.png", "variants": []}}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1", handler)
+
+	actual, err := client.UploadImage(context.Background(), AccountIdentifier(testAccountID), UploadImageParams{
+		File: io.NopCloser(io.LimitReader(zeroReader{}, size)),
+		Name: "large.png",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "large.png", actual.Filename)
+	}
+}
+
+func TestUploadImage_MissingFileAndURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.UploadImage(context.Background(), AccountIdentifier(testAccountID), UploadImageParams{Name: "avatar.png"})
+	assert.ErrorIs(t, err, errMissingImageUpload)
+}
+
+// zeroReader produces an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
 func TestDeleteImage(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,220 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingImagesVariantID   = errors.New("required image variant ID missing")
+	ErrInvalidImagesVariantFit  = errors.New(`variant fit must be one of "scale-down", "contain", "cover", "crop" or "pad"`)
+	ErrInvalidImagesVariantMeta = errors.New(`variant metadata must be one of "keep", "copyright" or "none"`)
+)
+
+// ImagesVariantFit determines how an image is resized to the width and height
+// of a variant.
+type ImagesVariantFit string
+
+const (
+	ImagesVariantFitScaleDown ImagesVariantFit = "scale-down"
+	ImagesVariantFitContain   ImagesVariantFit = "contain"
+	ImagesVariantFitCover     ImagesVariantFit = "cover"
+	ImagesVariantFitCrop      ImagesVariantFit = "crop"
+	ImagesVariantFitPad       ImagesVariantFit = "pad"
+)
+
+// ImagesVariantMetadata determines which EXIF metadata is kept in images
+// served by a variant.
+type ImagesVariantMetadata string
+
+const (
+	ImagesVariantMetadataKeep      ImagesVariantMetadata = "keep"
+	ImagesVariantMetadataCopyright ImagesVariantMetadata = "copyright"
+	ImagesVariantMetadataNone      ImagesVariantMetadata = "none"
+)
+
+// ImagesVariant is a named set of resizing options images can be served with.
+type ImagesVariant struct {
+	ID                     string               `json:"id,omitempty"`
+	NeverRequireSignedURLs *bool                `json:"neverRequireSignedURLs,omitempty"`
+	Options                ImagesVariantOptions `json:"options"`
+}
+
+// ImagesVariantOptions are the resizing options of a variant.
+type ImagesVariantOptions struct {
+	Fit      ImagesVariantFit      `json:"fit,omitempty"`
+	Height   int                   `json:"height,omitempty"`
+	Width    int                   `json:"width,omitempty"`
+	Metadata ImagesVariantMetadata `json:"metadata,omitempty"`
+}
+
+type ListImagesVariantsParams struct{}
+
+type CreateImagesVariantParams struct {
+	ID                     string               `json:"id"`
+	NeverRequireSignedURLs *bool                `json:"neverRequireSignedURLs,omitempty"`
+	Options                ImagesVariantOptions `json:"options"`
+}
+
+type UpdateImagesVariantParams struct {
+	ID                     string               `json:"-"`
+	NeverRequireSignedURLs *bool                `json:"neverRequireSignedURLs,omitempty"`
+	Options                ImagesVariantOptions `json:"options"`
+}
+
+type ImagesVariantResponse struct {
+	Result struct {
+		Variant ImagesVariant `json:"variant"`
+	} `json:"result"`
+	Response
+}
+
+type ImagesVariantListResponse struct {
+	Result struct {
+		Variants map[string]ImagesVariant `json:"variants"`
+	} `json:"result"`
+	Response
+}
+
+// Validate checks the fit and metadata options against the values accepted
+// by the API.
+func (o ImagesVariantOptions) Validate() error {
+	switch o.Fit {
+	case "", ImagesVariantFitScaleDown, ImagesVariantFitContain, ImagesVariantFitCover, ImagesVariantFitCrop, ImagesVariantFitPad:
+	default:
+		return ErrInvalidImagesVariantFit
+	}
+
+	switch o.Metadata {
+	case "", ImagesVariantMetadataKeep, ImagesVariantMetadataCopyright, ImagesVariantMetadataNone:
+	default:
+		return ErrInvalidImagesVariantMeta
+	}
+
+	return nil
+}
+
+// ListImagesVariants lists the variants of an account, keyed by their ID.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-variants-list-variants
+func (api *API) ListImagesVariants(ctx context.Context, rc *ResourceContainer, params ListImagesVariantsParams) (map[string]ImagesVariant, error) {
+	if rc.Level != AccountRouteLevel {
+		return nil, ErrRequiredAccountLevelResourceContainer
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/variants", rc.Identifier)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var r ImagesVariantListResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	for id, v := range r.Result.Variants {
+		if v.ID == "" {
+			v.ID = id
+			r.Result.Variants[id] = v
+		}
+	}
+	return r.Result.Variants, nil
+}
+
+// GetImagesVariant gets the details of a variant.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-variants-variant-details
+func (api *API) GetImagesVariant(ctx context.Context, rc *ResourceContainer, variantID string) (ImagesVariant, error) {
+	if rc.Level != AccountRouteLevel {
+		return ImagesVariant{}, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if variantID == "" {
+		return ImagesVariant{}, ErrMissingImagesVariantID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/variants/%s", rc.Identifier, variantID)
+
+	return api.imagesVariantRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// CreateImagesVariant creates a variant.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-variants-create-a-variant
+func (api *API) CreateImagesVariant(ctx context.Context, rc *ResourceContainer, params CreateImagesVariantParams) (ImagesVariant, error) {
+	if rc.Level != AccountRouteLevel {
+		return ImagesVariant{}, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if params.ID == "" {
+		return ImagesVariant{}, ErrMissingImagesVariantID
+	}
+
+	if err := params.Options.Validate(); err != nil {
+		return ImagesVariant{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/variants", rc.Identifier)
+
+	return api.imagesVariantRequest(ctx, http.MethodPost, uri, params)
+}
+
+// UpdateImagesVariant updates the options of a variant.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-variants-update-a-variant
+func (api *API) UpdateImagesVariant(ctx context.Context, rc *ResourceContainer, params UpdateImagesVariantParams) (ImagesVariant, error) {
+	if rc.Level != AccountRouteLevel {
+		return ImagesVariant{}, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if params.ID == "" {
+		return ImagesVariant{}, ErrMissingImagesVariantID
+	}
+
+	if err := params.Options.Validate(); err != nil {
+		return ImagesVariant{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/variants/%s", rc.Identifier, params.ID)
+
+	return api.imagesVariantRequest(ctx, http.MethodPatch, uri, params)
+}
+
+// DeleteImagesVariant deletes a variant.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/cloudflare-images-variants-delete-a-variant
+func (api *API) DeleteImagesVariant(ctx context.Context, rc *ResourceContainer, variantID string) error {
+	if rc.Level != AccountRouteLevel {
+		return ErrRequiredAccountLevelResourceContainer
+	}
+
+	if variantID == "" {
+		return ErrMissingImagesVariantID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/images/v1/variants/%s", rc.Identifier, variantID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	return err
+}
+
+func (api *API) imagesVariantRequest(ctx context.Context, method, uri string, params interface{}) (ImagesVariant, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return ImagesVariant{}, err
+	}
+
+	var r ImagesVariantResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return ImagesVariant{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result.Variant, nil
+}
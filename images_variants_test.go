@@ -0,0 +1,128 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testImagesVariantResponse = `{
+	"success": true,
+	"errors": [],
+	"messages": [],
+	"result": {
+		"variant": {
+			"id": "hero",
+			"neverRequireSignedURLs": true,
+			"options": {"fit": "scale-down", "height": 768, "width": 1366, "metadata": "none"}
+		}
+	}
+}`
+
+var expectedImagesVariant = ImagesVariant{
+	ID:                     "hero",
+	NeverRequireSignedURLs: BoolPtr(true),
+	Options: ImagesVariantOptions{
+		Fit:      ImagesVariantFitScaleDown,
+		Height:   768,
+		Width:    1366,
+		Metadata: ImagesVariantMetadataNone,
+	},
+}
+
+func TestListImagesVariants(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/variants", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"variants": {
+					"hero": {
+						"neverRequireSignedURLs": true,
+						"options": {"fit": "scale-down", "height": 768, "width": 1366, "metadata": "none"}
+					}
+				}
+			}
+		}`)
+	})
+
+	got, err := client.ListImagesVariants(context.Background(), AccountIdentifier(testAccountID), ListImagesVariantsParams{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]ImagesVariant{"hero": expectedImagesVariant}, got)
+	}
+}
+
+func TestCreateImagesVariant(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/variants", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "hero", body["id"])
+		assert.Equal(t, "scale-down", body["options"].(map[string]interface{})["fit"])
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testImagesVariantResponse)
+	})
+
+	got, err := client.CreateImagesVariant(context.Background(), AccountIdentifier(testAccountID), CreateImagesVariantParams{
+		ID:                     "hero",
+		NeverRequireSignedURLs: BoolPtr(true),
+		Options:                expectedImagesVariant.Options,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedImagesVariant, got)
+	}
+
+	_, err = client.CreateImagesVariant(context.Background(), AccountIdentifier(testAccountID), CreateImagesVariantParams{
+		ID:      "hero",
+		Options: ImagesVariantOptions{Fit: "stretch"},
+	})
+	assert.ErrorIs(t, err, ErrInvalidImagesVariantFit)
+}
+
+func TestGetUpdateDeleteImagesVariant(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/images/v1/variants/hero", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet, http.MethodPatch:
+			fmt.Fprint(w, testImagesVariantResponse)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, err := client.GetImagesVariant(context.Background(), AccountIdentifier(testAccountID), "hero")
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedImagesVariant, got)
+	}
+
+	got, err = client.UpdateImagesVariant(context.Background(), AccountIdentifier(testAccountID), UpdateImagesVariantParams{
+		ID:      "hero",
+		Options: expectedImagesVariant.Options,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedImagesVariant, got)
+	}
+
+	assert.NoError(t, client.DeleteImagesVariant(context.Background(), AccountIdentifier(testAccountID), "hero"))
+	assert.ErrorIs(t, client.DeleteImagesVariant(context.Background(), AccountIdentifier(testAccountID), ""), ErrMissingImagesVariantID)
+}
@@ -2,7 +2,6 @@ package cloudflare
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -460,48 +459,12 @@ func (api *API) GetIPListItem(ctx context.Context, accountID, listID, id string)
 //
 // Deprecated: Use `GetListBulkOperation` instead.
 func (api *API) GetIPListBulkOperation(ctx context.Context, accountID, ID string) (IPListBulkOperation, error) {
-	uri := fmt.Sprintf("/accounts/%s/rules/lists/bulk_operations/%s", accountID, ID)
-	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
-	if err != nil {
-		return IPListBulkOperation{}, err
-	}
-
-	result := IPListBulkOperationResponse{}
-	if err := json.Unmarshal(res, &result); err != nil {
-		return IPListBulkOperation{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
-	}
-
-	return result.Result, nil
+	op, err := api.GetListBulkOperation(ctx, AccountIdentifier(accountID), ID)
+	return IPListBulkOperation(op), err
 }
 
 // pollIPListBulkOperation implements synchronous behaviour for some
-// asynchronous endpoints. bulk-operation status can be either pending, running,
-// failed or completed.
+// asynchronous endpoints.
 func (api *API) pollIPListBulkOperation(ctx context.Context, accountID, ID string) error {
-	for i := uint8(0); i < 16; i++ {
-		sleepDuration := 1 << (i / 2) * time.Second
-		select {
-		case <-time.After(sleepDuration):
-		case <-ctx.Done():
-			return fmt.Errorf("operation aborted during backoff: %w", ctx.Err())
-		}
-
-		bulkResult, err := api.GetIPListBulkOperation(ctx, accountID, ID)
-		if err != nil {
-			return err
-		}
-
-		switch bulkResult.Status {
-		case "failed":
-			return errors.New(bulkResult.Error)
-		case "pending", "running":
-			continue
-		case "completed":
-			return nil
-		default:
-			return fmt.Errorf("%s: %s", errOperationUnexpectedStatus, bulkResult.Status)
-		}
-	}
-
-	return errors.New(errOperationStillRunning)
+	return api.pollListBulkOperation(ctx, AccountIdentifier(accountID), ID)
 }
@@ -595,34 +595,75 @@ func (api *API) GetListBulkOperation(ctx context.Context, rc *ResourceContainer,
 	return result.Result, nil
 }
 
-// pollListBulkOperation implements synchronous behaviour for some asynchronous
-// endpoints. bulk-operation status can be either pending, running, failed or
-// completed.
-func (api *API) pollListBulkOperation(ctx context.Context, rc *ResourceContainer, ID string) error {
-	for i := uint8(0); i < 16; i++ {
-		sleepDuration := 1 << (i / 2) * time.Second
+// ErrListBulkOperationFailed is returned when a bulk operation fails.
+var ErrListBulkOperationFailed = errors.New("list bulk operation failed")
+
+// PollListBulkOperationParams configures PollListBulkOperation. The zero
+// value of each field uses its default.
+type PollListBulkOperationParams struct {
+	ID string
+	// Interval is the wait before the first two status checks, doubled
+	// after every second check up to MaxInterval. Defaults to 1 second.
+	Interval time.Duration
+	// MaxInterval defaults to 128 seconds, which with the other defaults
+	// waits up to about 8.5 minutes.
+	MaxInterval time.Duration
+	// MaxAttempts is the number of status checks after which the
+	// operation is considered stuck. Defaults to 16.
+	MaxAttempts int
+}
+
+// PollListBulkOperation waits for a bulk operation to finish and returns it.
+// Bulk operation status can be either pending, running, failed or
+// completed; failed operations return an error wrapping
+// ErrListBulkOperationFailed.
+func (api *API) PollListBulkOperation(ctx context.Context, rc *ResourceContainer, params PollListBulkOperationParams) (ListBulkOperation, error) {
+	if params.Interval <= 0 {
+		params.Interval = time.Second
+	}
+	if params.MaxInterval <= 0 {
+		params.MaxInterval = 128 * time.Second
+	}
+	if params.MaxAttempts <= 0 {
+		params.MaxAttempts = 16
+	}
+
+	interval := params.Interval
+	for i := 0; i < params.MaxAttempts; i++ {
 		select {
-		case <-time.After(sleepDuration):
+		case <-time.After(interval):
 		case <-ctx.Done():
-			return fmt.Errorf("operation aborted during backoff: %w", ctx.Err())
+			return ListBulkOperation{}, fmt.Errorf("operation aborted during backoff: %w", ctx.Err())
+		}
+		if i%2 == 1 {
+			if interval *= 2; interval > params.MaxInterval {
+				interval = params.MaxInterval
+			}
 		}
 
-		bulkResult, err := api.GetListBulkOperation(ctx, rc, ID)
+		bulkResult, err := api.GetListBulkOperation(ctx, rc, params.ID)
 		if err != nil {
-			return err
+			return ListBulkOperation{}, err
 		}
 
 		switch bulkResult.Status {
 		case "failed":
-			return errors.New(bulkResult.Error)
+			return bulkResult, fmt.Errorf("%w: %s", ErrListBulkOperationFailed, bulkResult.Error)
 		case "pending", "running":
 			continue
 		case "completed":
-			return nil
+			return bulkResult, nil
 		default:
-			return fmt.Errorf("%s: %s", errOperationUnexpectedStatus, bulkResult.Status)
+			return bulkResult, fmt.Errorf("%s: %s", errOperationUnexpectedStatus, bulkResult.Status)
 		}
 	}
 
-	return errors.New(errOperationStillRunning)
+	return ListBulkOperation{}, errors.New(errOperationStillRunning)
+}
+
+// pollListBulkOperation implements synchronous behaviour for some asynchronous
+// endpoints.
+func (api *API) pollListBulkOperation(ctx context.Context, rc *ResourceContainer, ID string) error {
+	_, err := api.PollListBulkOperation(ctx, rc, PollListBulkOperationParams{ID: ID})
+	return err
 }
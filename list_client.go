@@ -0,0 +1,457 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidListItem      = errors.New("invalid list item")
+	ErrListItemKindMismatch = errors.New("list item doesn't match the kind of the list")
+)
+
+// ListItemValue is the value of an item of a list kind. Values are
+// validated and normalized locally by ListClient before being sent.
+type ListItemValue[V any] interface {
+	// ListKind returns the kind of the lists holding the value.
+	ListKind() string
+	// Normalize validates the value and returns its canonical form.
+	Normalize() (V, error)
+
+	// key identifies the item in its list.
+	key() string
+	// withDefaults returns the value as the API stores it, with optional
+	// fields set to their default.
+	withDefaults() V
+	createRequest(comment string) ListItemCreateRequest
+	fromListItem(item ListItem) (V, bool)
+}
+
+// ListIPValue is an IP address or CIDR range of an ip list. IPv4 ranges go
+// from /8 to /32 and IPv6 ranges from /12 to /64; IPv6 addresses are
+// stored as their /64 range.
+type ListIPValue string
+
+func (v ListIPValue) ListKind() string { return ListTypeIP }
+
+func (v ListIPValue) Normalize() (ListIPValue, error) {
+	s := strings.TrimSpace(string(v))
+
+	var prefix netip.Prefix
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q isn't an IP address or CIDR range", ErrInvalidListItem, v)
+		}
+		prefix = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()).Masked()
+	} else {
+		addr, err := netip.ParseAddr(s)
+		if err != nil || addr.Zone() != "" {
+			return "", fmt.Errorf("%w: %q isn't an IP address or CIDR range", ErrInvalidListItem, v)
+		}
+		addr = addr.Unmap()
+		bits := 32
+		if addr.Is6() {
+			bits = 64
+		}
+		prefix, _ = addr.Prefix(bits)
+	}
+
+	if prefix.Addr().Is4() {
+		if prefix.Bits() < 8 {
+			return "", fmt.Errorf("%w: IPv4 range %s is larger than /8", ErrInvalidListItem, prefix)
+		}
+		if prefix.Bits() == 32 {
+			return ListIPValue(prefix.Addr().String()), nil
+		}
+	} else if prefix.Bits() < 12 || prefix.Bits() > 64 {
+		return "", fmt.Errorf("%w: IPv6 range %s must be between /12 and /64", ErrInvalidListItem, prefix)
+	}
+
+	return ListIPValue(prefix.String()), nil
+}
+
+func (v ListIPValue) key() string { return string(v) }
+
+func (v ListIPValue) withDefaults() ListIPValue { return v }
+
+func (v ListIPValue) createRequest(comment string) ListItemCreateRequest {
+	return ListItemCreateRequest{IP: StringPtr(string(v)), Comment: comment}
+}
+
+func (ListIPValue) fromListItem(item ListItem) (ListIPValue, bool) {
+	if item.IP == nil {
+		return "", false
+	}
+	return ListIPValue(*item.IP), true
+}
+
+// ListHostnameValue is a hostname of a hostname list, optionally starting
+// with a "*." wildcard.
+type ListHostnameValue string
+
+func (v ListHostnameValue) ListKind() string { return ListTypeHostname }
+
+func (v ListHostnameValue) Normalize() (ListHostnameValue, error) {
+	s := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(string(v))), ".")
+	host := strings.TrimPrefix(s, "*.")
+
+	if host == "" || len(host) > 253 {
+		return "", fmt.Errorf("%w: %q isn't a hostname", ErrInvalidListItem, v)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !validHostnameLabel(label) {
+			return "", fmt.Errorf("%w: %q isn't a hostname", ErrInvalidListItem, v)
+		}
+	}
+
+	return ListHostnameValue(s), nil
+}
+
+func validHostnameLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func (v ListHostnameValue) key() string { return string(v) }
+
+func (v ListHostnameValue) withDefaults() ListHostnameValue { return v }
+
+func (v ListHostnameValue) createRequest(comment string) ListItemCreateRequest {
+	return ListItemCreateRequest{Hostname: &Hostname{UrlHostname: string(v)}, Comment: comment}
+}
+
+func (ListHostnameValue) fromListItem(item ListItem) (ListHostnameValue, bool) {
+	if item.Hostname == nil {
+		return "", false
+	}
+	return ListHostnameValue(item.Hostname.UrlHostname), true
+}
+
+// ListASNValue is an autonomous system number of an asn list.
+type ListASNValue uint32
+
+// reservedASNs are never seen in traffic.
+var reservedASNs = map[ListASNValue]bool{0: true, 23456: true, 65535: true, 4294967295: true}
+
+func (v ListASNValue) ListKind() string { return ListTypeASN }
+
+func (v ListASNValue) Normalize() (ListASNValue, error) {
+	if reservedASNs[v] {
+		return 0, fmt.Errorf("%w: AS%d is reserved", ErrInvalidListItem, v)
+	}
+	return v, nil
+}
+
+func (v ListASNValue) key() string { return fmt.Sprint(uint32(v)) }
+
+func (v ListASNValue) withDefaults() ListASNValue { return v }
+
+func (v ListASNValue) createRequest(comment string) ListItemCreateRequest {
+	asn := uint32(v)
+	return ListItemCreateRequest{ASN: &asn, Comment: comment}
+}
+
+func (ListASNValue) fromListItem(item ListItem) (ListASNValue, bool) {
+	if item.ASN == nil {
+		return 0, false
+	}
+	return ListASNValue(*item.ASN), true
+}
+
+// ListRedirectValue is a redirect of a redirect list. The source URL has
+// no query string and its scheme is optional; the target URL is a full
+// http or https URL.
+type ListRedirectValue Redirect
+
+func (v ListRedirectValue) ListKind() string { return ListTypeRedirect }
+
+func (v ListRedirectValue) Normalize() (ListRedirectValue, error) {
+	source := strings.TrimSpace(v.SourceUrl)
+	scheme := ""
+	if i := strings.Index(source, "://"); i >= 0 {
+		scheme = strings.ToLower(source[:i])
+		if scheme != "http" && scheme != "https" {
+			return ListRedirectValue{}, fmt.Errorf("%w: source URL %q must be http or https", ErrInvalidListItem, v.SourceUrl)
+		}
+		source = source[i+3:]
+	}
+	u, err := url.Parse("//" + source)
+	if err != nil || u.Host == "" {
+		return ListRedirectValue{}, fmt.Errorf("%w: invalid source URL %q", ErrInvalidListItem, v.SourceUrl)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || strings.ContainsAny(source, "?#") {
+		return ListRedirectValue{}, fmt.Errorf("%w: source URL %q can't have a query string or fragment", ErrInvalidListItem, v.SourceUrl)
+	}
+	v.SourceUrl = strings.ToLower(u.Host) + u.EscapedPath()
+	if scheme != "" {
+		v.SourceUrl = scheme + "://" + v.SourceUrl
+	}
+
+	target, err := url.Parse(strings.TrimSpace(v.TargetUrl))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return ListRedirectValue{}, fmt.Errorf("%w: target URL %q must be a full http or https URL", ErrInvalidListItem, v.TargetUrl)
+	}
+	v.TargetUrl = target.String()
+
+	if v.StatusCode != nil {
+		switch *v.StatusCode {
+		case 301, 302, 307, 308:
+		default:
+			return ListRedirectValue{}, fmt.Errorf("%w: invalid redirect status code %d", ErrInvalidListItem, *v.StatusCode)
+		}
+	}
+
+	if v.PreservePathSuffix != nil && *v.PreservePathSuffix && (v.SubpathMatching == nil || !*v.SubpathMatching) {
+		return ListRedirectValue{}, fmt.Errorf("%w: preserving the path suffix requires subpath matching", ErrInvalidListItem)
+	}
+
+	return v, nil
+}
+
+func (v ListRedirectValue) key() string { return v.SourceUrl }
+
+func (v ListRedirectValue) withDefaults() ListRedirectValue {
+	if v.IncludeSubdomains == nil {
+		v.IncludeSubdomains = BoolPtr(false)
+	}
+	if v.StatusCode == nil {
+		v.StatusCode = IntPtr(301)
+	}
+	if v.PreserveQueryString == nil {
+		v.PreserveQueryString = BoolPtr(false)
+	}
+	if v.SubpathMatching == nil {
+		v.SubpathMatching = BoolPtr(false)
+	}
+	if v.PreservePathSuffix == nil {
+		v.PreservePathSuffix = BoolPtr(false)
+	}
+	return v
+}
+
+func (v ListRedirectValue) createRequest(comment string) ListItemCreateRequest {
+	r := Redirect(v)
+	return ListItemCreateRequest{Redirect: &r, Comment: comment}
+}
+
+func (ListRedirectValue) fromListItem(item ListItem) (ListRedirectValue, bool) {
+	if item.Redirect == nil {
+		return ListRedirectValue{}, false
+	}
+	return ListRedirectValue(*item.Redirect), true
+}
+
+// ListEntry is an item of a list holding values of type V.
+type ListEntry[V ListItemValue[V]] struct {
+	// ID is set on entries read from the list.
+	ID      string
+	Value   V
+	Comment string
+}
+
+// ListClient manages the items of a list of one kind, such as
+// ListClient[ListIPValue] for ip lists. Changes wait for the bulk
+// operation they start to finish.
+type ListClient[V ListItemValue[V]] struct {
+	api    *API
+	rc     *ResourceContainer
+	listID string
+
+	// Poll configures how bulk operations are waited for. Its ID is
+	// ignored.
+	Poll PollListBulkOperationParams
+}
+
+// NewListClient returns a client for the items of a list.
+func NewListClient[V ListItemValue[V]](api *API, rc *ResourceContainer, listID string) *ListClient[V] {
+	return &ListClient[V]{api: api, rc: rc, listID: listID}
+}
+
+// Items returns every item of the list.
+func (c *ListClient[V]) Items(ctx context.Context) ([]ListEntry[V], error) {
+	items, err := c.api.ListListItems(ctx, c.rc, ListListItemsParams{ID: c.listID})
+	if err != nil {
+		return nil, err
+	}
+
+	var zero V
+	entries := make([]ListEntry[V], 0, len(items))
+	for _, item := range items {
+		v, ok := zero.fromListItem(item)
+		if !ok {
+			return nil, fmt.Errorf("%w: item %s isn't a %s item", ErrListItemKindMismatch, item.ID, zero.ListKind())
+		}
+		entries = append(entries, ListEntry[V]{ID: item.ID, Value: v, Comment: item.Comment})
+	}
+	return entries, nil
+}
+
+// Add adds items to the list, replacing the comment of items already in it.
+func (c *ListClient[V]) Add(ctx context.Context, entries ...ListEntry[V]) (ListBulkOperation, error) {
+	normalized, err := normalizeListEntries(entries)
+	if err != nil {
+		return ListBulkOperation{}, err
+	}
+	return c.add(ctx, normalized)
+}
+
+func (c *ListClient[V]) add(ctx context.Context, entries []ListEntry[V]) (ListBulkOperation, error) {
+	if len(entries) == 0 {
+		return ListBulkOperation{}, nil
+	}
+
+	res, err := c.api.CreateListItemsAsync(ctx, c.rc, ListCreateItemsParams{ID: c.listID, Items: listEntryRequests(entries)})
+	if err != nil {
+		return ListBulkOperation{}, err
+	}
+	return c.poll(ctx, res.Result.OperationID)
+}
+
+// Remove removes items from the list by ID.
+func (c *ListClient[V]) Remove(ctx context.Context, ids ...string) (ListBulkOperation, error) {
+	if len(ids) == 0 {
+		return ListBulkOperation{}, nil
+	}
+
+	items := make([]ListItemDeleteItemRequest, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, ListItemDeleteItemRequest{ID: id})
+	}
+
+	res, err := c.api.DeleteListItemsAsync(ctx, c.rc, ListDeleteItemsParams{ID: c.listID, Items: ListItemDeleteRequest{Items: items}})
+	if err != nil {
+		return ListBulkOperation{}, err
+	}
+	return c.poll(ctx, res.Result.OperationID)
+}
+
+// Replace replaces every item of the list.
+func (c *ListClient[V]) Replace(ctx context.Context, entries ...ListEntry[V]) (ListBulkOperation, error) {
+	normalized, err := normalizeListEntries(entries)
+	if err != nil {
+		return ListBulkOperation{}, err
+	}
+
+	res, err := c.api.ReplaceListItemsAsync(ctx, c.rc, ListReplaceItemsParams{ID: c.listID, Items: listEntryRequests(normalized)})
+	if err != nil {
+		return ListBulkOperation{}, err
+	}
+	return c.poll(ctx, res.Result.OperationID)
+}
+
+func (c *ListClient[V]) poll(ctx context.Context, operationID string) (ListBulkOperation, error) {
+	params := c.Poll
+	params.ID = operationID
+	return c.api.PollListBulkOperation(ctx, c.rc, params)
+}
+
+// ListSyncParams are the items a list should hold.
+type ListSyncParams[V ListItemValue[V]] struct {
+	Items []ListEntry[V]
+	// DryRun computes the changes without making them.
+	DryRun bool
+}
+
+// ListSyncResult holds the changes made by ListClient.Sync. An item whose
+// comment or redirect changed is only added, as adding it replaces it.
+type ListSyncResult[V ListItemValue[V]] struct {
+	Added   []ListEntry[V]
+	Removed []ListEntry[V]
+}
+
+// Sync makes the list hold exactly the given items by removing and adding
+// only the items which differ, instead of replacing every item. Removals
+// happen before additions.
+func (c *ListClient[V]) Sync(ctx context.Context, params ListSyncParams[V]) (ListSyncResult[V], error) {
+	desired, err := normalizeListEntries(params.Items)
+	if err != nil {
+		return ListSyncResult[V]{}, err
+	}
+
+	current, err := c.Items(ctx)
+	if err != nil {
+		return ListSyncResult[V]{}, err
+	}
+
+	currentByKey := make(map[string]ListEntry[V], len(current))
+	for _, e := range current {
+		currentByKey[e.Value.key()] = e
+	}
+
+	var result ListSyncResult[V]
+	kept := map[string]bool{}
+	for _, d := range desired {
+		key := d.Value.key()
+		kept[key] = true
+		cur, ok := currentByKey[key]
+		if ok && cur.Comment == d.Comment && reflect.DeepEqual(cur.Value.withDefaults(), d.Value.withDefaults()) {
+			continue
+		}
+		result.Added = append(result.Added, d)
+	}
+	for _, e := range current {
+		if !kept[e.Value.key()] {
+			result.Removed = append(result.Removed, e)
+		}
+	}
+
+	if params.DryRun {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(result.Removed))
+	for _, e := range result.Removed {
+		ids = append(ids, e.ID)
+	}
+	if _, err := c.Remove(ctx, ids...); err != nil {
+		return ListSyncResult[V]{}, err
+	}
+	if _, err := c.add(ctx, result.Added); err != nil {
+		return ListSyncResult[V]{}, err
+	}
+
+	return result, nil
+}
+
+// normalizeListEntries normalizes the value of each entry and drops
+// duplicates, keeping the last one.
+func normalizeListEntries[V ListItemValue[V]](entries []ListEntry[V]) ([]ListEntry[V], error) {
+	normalized := make([]ListEntry[V], 0, len(entries))
+	index := map[string]int{}
+	for i, e := range entries {
+		v, err := e.Value.Normalize()
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		e.Value = v
+
+		if j, ok := index[v.key()]; ok {
+			normalized[j] = e
+			continue
+		}
+		index[v.key()] = len(normalized)
+		normalized = append(normalized, e)
+	}
+	return normalized, nil
+}
+
+func listEntryRequests[V ListItemValue[V]](entries []ListEntry[V]) []ListItemCreateRequest {
+	requests := make([]ListItemCreateRequest, 0, len(entries))
+	for _, e := range entries {
+		requests = append(requests, e.Value.createRequest(e.Comment))
+	}
+	return requests
+}
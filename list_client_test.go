@@ -0,0 +1,199 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListIPValue_Normalize(t *testing.T) {
+	testCases := map[ListIPValue]ListIPValue{
+		"192.0.2.1":               "192.0.2.1",
+		" 192.0.2.1/32 ":          "192.0.2.1",
+		"192.0.2.77/24":           "192.0.2.0/24",
+		"::ffff:192.0.2.1":        "192.0.2.1",
+		"2001:db8::1":             "2001:db8::/64",
+		"2001:DB8:0:0:1::/48":     "2001:db8::/48",
+		"10.0.0.0/7":              "",
+		"2001:db8::/96":           "",
+		"2001:db8::/8":            "",
+		"192.0.2.300":             "",
+		"fe80::1%eth0":            "",
+		"not an ip":               "",
+		"2001:db8:1:2:3:4:5:6/64": "2001:db8:1:2::/64",
+	}
+
+	for in, want := range testCases {
+		got, err := in.Normalize()
+		if want == "" {
+			assert.ErrorIs(t, err, ErrInvalidListItem, in)
+			continue
+		}
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+}
+
+func TestListItemValues_Normalize(t *testing.T) {
+	host, err := ListHostnameValue("*.Example.COM.").Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, ListHostnameValue("*.example.com"), host)
+
+	for _, h := range []ListHostnameValue{"", "-a.example.com", "a..example.com", "exa_mple.com", "a.*.example.com"} {
+		_, err := h.Normalize()
+		assert.ErrorIs(t, err, ErrInvalidListItem, h)
+	}
+
+	_, err = ListASNValue(13335).Normalize()
+	assert.NoError(t, err)
+	_, err = ListASNValue(23456).Normalize()
+	assert.ErrorIs(t, err, ErrInvalidListItem)
+
+	redirect, err := ListRedirectValue{SourceUrl: "HTTPS://Example.com/Old", TargetUrl: "https://example.com/new", StatusCode: IntPtr(301)}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/Old", redirect.SourceUrl)
+
+	for _, r := range []ListRedirectValue{
+		{SourceUrl: "example.com/a?b=c", TargetUrl: "https://example.com"},
+		{SourceUrl: "ftp://example.com/a", TargetUrl: "https://example.com"},
+		{SourceUrl: "example.com/a", TargetUrl: "/relative"},
+		{SourceUrl: "example.com/a", TargetUrl: "https://example.com", StatusCode: IntPtr(303)},
+		{SourceUrl: "example.com/a", TargetUrl: "https://example.com", PreservePathSuffix: BoolPtr(true)},
+	} {
+		_, err := r.Normalize()
+		assert.ErrorIs(t, err, ErrInvalidListItem, r.SourceUrl)
+	}
+}
+
+func TestListClient_Sync(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var deleted, created string
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/2c0fc9fa937b11eaa1b71c4d701ab86e/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{
+				"result": [
+					{"id": "1", "ip": "192.0.2.1", "comment": "keep"},
+					{"id": "2", "ip": "192.0.2.2", "comment": "old comment"},
+					{"id": "3", "ip": "192.0.2.3", "comment": "remove"}
+				],
+				"result_info": {"cursors": {}},
+				"success": true,
+				"errors": [],
+				"messages": []
+			}`)
+		case http.MethodDelete:
+			body, _ := io.ReadAll(r.Body)
+			deleted = string(body)
+			fmt.Fprint(w, `{"result": {"operation_id": "op1"}, "success": true, "errors": [], "messages": []}`)
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			created = string(body)
+			fmt.Fprint(w, `{"result": {"operation_id": "op2"}, "success": true, "errors": [], "messages": []}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	polls := 0
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/bulk_operations/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		polls++
+		status := "completed"
+		if polls == 1 {
+			status = "running"
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"result": {"id": "op", "status": "%s"}, "success": true, "errors": [], "messages": []}`, status)
+	})
+
+	lists := NewListClient[ListIPValue](client, AccountIdentifier(testAccountID), "2c0fc9fa937b11eaa1b71c4d701ab86e")
+	lists.Poll.Interval = time.Millisecond
+
+	desired := []ListEntry[ListIPValue]{
+		{Value: "192.0.2.1/32", Comment: "keep"},
+		{Value: "192.0.2.2", Comment: "new comment"},
+		{Value: "198.51.100.7/24"},
+		{Value: "198.51.100.0/24", Comment: "duplicate"},
+	}
+
+	result, err := lists.Sync(context.Background(), ListSyncParams[ListIPValue]{Items: desired, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []ListEntry[ListIPValue]{
+		{Value: "192.0.2.2", Comment: "new comment"},
+		{Value: "198.51.100.0/24", Comment: "duplicate"},
+	}, result.Added)
+	assert.Equal(t, []ListEntry[ListIPValue]{
+		{ID: "3", Value: "192.0.2.3", Comment: "remove"},
+	}, result.Removed)
+	assert.Empty(t, deleted)
+	assert.Empty(t, created)
+
+	_, err = lists.Sync(context.Background(), ListSyncParams[ListIPValue]{Items: desired})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items": [{"id": "3"}]}`, deleted)
+	assert.JSONEq(t, `[{"ip": "192.0.2.2", "comment": "new comment"}, {"ip": "198.51.100.0/24", "comment": "duplicate"}]`, created)
+	assert.Equal(t, 3, polls)
+
+	_, err = lists.Add(context.Background(), ListEntry[ListIPValue]{Value: "bogus"})
+	assert.ErrorIs(t, err, ErrInvalidListItem)
+}
+
+func TestListClient_SyncRedirectDefaults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/2c0fc9fa937b11eaa1b71c4d701ab86e/items", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"result": [
+				{"id": "1", "redirect": {"source_url": "example.com/a", "target_url": "https://example.net/a", "include_subdomains": false, "status_code": 301, "preserve_query_string": false, "subpath_matching": false, "preserve_path_suffix": false}},
+				{"id": "2", "redirect": {"source_url": "example.com/b", "target_url": "https://example.net/b", "include_subdomains": false, "status_code": 301, "preserve_query_string": false, "subpath_matching": false, "preserve_path_suffix": false}}
+			],
+			"result_info": {"cursors": {}},
+			"success": true,
+			"errors": [],
+			"messages": []
+		}`)
+	})
+
+	lists := NewListClient[ListRedirectValue](client, AccountIdentifier(testAccountID), "2c0fc9fa937b11eaa1b71c4d701ab86e")
+	result, err := lists.Sync(context.Background(), ListSyncParams[ListRedirectValue]{
+		Items: []ListEntry[ListRedirectValue]{
+			{Value: ListRedirectValue{SourceUrl: "example.com/a", TargetUrl: "https://example.net/a"}},
+			{Value: ListRedirectValue{SourceUrl: "example.com/b", TargetUrl: "https://example.net/b", StatusCode: IntPtr(302)}},
+		},
+		DryRun: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Added, 1)
+	assert.Equal(t, "example.com/b", result.Added[0].Value.SourceUrl)
+	assert.Empty(t, result.Removed)
+}
+
+func TestListClient_ItemsKindMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/2c0fc9fa937b11eaa1b71c4d701ab86e/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"result": [{"id": "1", "asn": 13335}], "result_info": {"cursors": {}}, "success": true, "errors": [], "messages": []}`)
+	})
+
+	_, err := NewListClient[ListHostnameValue](client, AccountIdentifier(testAccountID), "2c0fc9fa937b11eaa1b71c4d701ab86e").Items(context.Background())
+	assert.ErrorIs(t, err, ErrListItemKindMismatch)
+
+	items, err := NewListClient[ListASNValue](client, AccountIdentifier(testAccountID), "2c0fc9fa937b11eaa1b71c4d701ab86e").Items(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ListASNValue(13335), items[0].Value)
+}
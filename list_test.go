@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -1043,3 +1044,35 @@ func TestPollListTimeout(t *testing.T) {
 	assert.WithinDuration(t, start, time.Now(), time.Second,
 		"pollListBulkOperation took too much time with an expiring context")
 }
+
+func TestPollListBulkOperation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	statuses := map[string][]string{
+		"failed":  {"pending", "failed"},
+		"running": {"running", "running", "running"},
+	}
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/bulk_operations/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		id := strings.TrimPrefix(r.URL.Path, "/accounts/"+testAccountID+"/rules/lists/bulk_operations/")
+		status := statuses[id][0]
+		statuses[id] = statuses[id][1:]
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"result": {"id": "%s", "status": "%s", "error": "invalid item"}, "success": true, "errors": [], "messages": []}`, id, status)
+	})
+
+	op, err := client.PollListBulkOperation(context.Background(), AccountIdentifier(testAccountID), PollListBulkOperationParams{
+		ID:       "failed",
+		Interval: time.Millisecond,
+	})
+	assert.ErrorIs(t, err, ErrListBulkOperationFailed)
+	assert.Equal(t, "failed", op.Status)
+
+	_, err = client.PollListBulkOperation(context.Background(), AccountIdentifier(testAccountID), PollListBulkOperationParams{
+		ID:          "running",
+		Interval:    time.Millisecond,
+		MaxAttempts: 3,
+	})
+	assert.EqualError(t, err, errOperationStillRunning)
+}
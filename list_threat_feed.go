@@ -0,0 +1,377 @@
+package cloudflare
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrUnknownThreatFeedFormat = errors.New("unknown threat feed format")
+	ErrInvalidThreatFeedName   = errors.New("threat feed name can only contain letters, numbers and underscores")
+)
+
+// defaultThreatFeedListSize is the number of items of each list holding a
+// threat feed unless set otherwise.
+const defaultThreatFeedListSize = 10000
+
+// ThreatFeedFormat is the format of a threat feed file.
+type ThreatFeedFormat string
+
+const (
+	// ThreatFeedFormatText is one IP address or CIDR range per line. Text
+	// after # or ; is ignored.
+	ThreatFeedFormatText ThreatFeedFormat = "text"
+	// ThreatFeedFormatCSV is a CSV file. The addresses are read from the
+	// ip, ip_address, cidr or indicator column, or else the first column;
+	// expiry times from the expires or valid_until column.
+	ThreatFeedFormatCSV ThreatFeedFormat = "csv"
+	// ThreatFeedFormatSTIX is a JSON bundle of STIX indicators with
+	// ipv4-addr or ipv6-addr patterns.
+	ThreatFeedFormatSTIX ThreatFeedFormat = "stix"
+)
+
+// ThreatFeedEntry is an address or range of a threat feed.
+type ThreatFeedEntry struct {
+	Value ListIPValue
+	// Expires is when the entry should be dropped, if ever.
+	Expires *time.Time
+}
+
+// ThreatFeed is a parsed threat feed file.
+type ThreatFeed struct {
+	Entries []ThreatFeedEntry
+	// Invalid holds the values which aren't IP addresses or ranges that
+	// lists accept.
+	Invalid []string
+}
+
+var threatFeedCSVColumns = map[string]bool{"ip": true, "ip_address": true, "cidr": true, "indicator": true}
+
+var threatFeedCSVExpiryColumns = map[string]bool{"expires": true, "valid_until": true}
+
+var stixAddressPattern = regexp.MustCompile(`(?:ipv4|ipv6)-addr:value\s*=\s*'([^']+)'`)
+
+// ParseThreatFeed parses a threat feed file.
+func ParseThreatFeed(r io.Reader, format ThreatFeedFormat) (ThreatFeed, error) {
+	var feed ThreatFeed
+	add := func(value string, expires *time.Time) {
+		v, err := ListIPValue(value).Normalize()
+		if err != nil {
+			feed.Invalid = append(feed.Invalid, value)
+			return
+		}
+		feed.Entries = append(feed.Entries, ThreatFeedEntry{Value: v, Expires: expires})
+	}
+
+	switch format {
+	case ThreatFeedFormatText:
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if i := strings.IndexAny(line, "#;"); i >= 0 {
+				line = line[:i]
+			}
+			if fields := strings.Fields(line); len(fields) > 0 {
+				add(fields[0], nil)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return ThreatFeed{}, err
+		}
+
+	case ThreatFeedFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return ThreatFeed{}, err
+		}
+
+		column, expiryColumn := 0, -1
+		if len(records) > 0 {
+			header := false
+			for i, name := range records[0] {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if threatFeedCSVColumns[name] {
+					column, header = i, true
+				}
+				if threatFeedCSVExpiryColumns[name] {
+					expiryColumn, header = i, true
+				}
+			}
+			if header {
+				records = records[1:]
+			}
+		}
+
+		for _, record := range records {
+			if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+				continue
+			}
+			var expires *time.Time
+			if expiryColumn >= 0 && expiryColumn < len(record) {
+				if t, err := time.Parse(time.RFC3339, strings.TrimSpace(record[expiryColumn])); err == nil {
+					expires = &t
+				}
+			}
+			add(strings.TrimSpace(record[column]), expires)
+		}
+
+	case ThreatFeedFormatSTIX:
+		var bundle struct {
+			Objects []struct {
+				Type       string     `json:"type"`
+				Pattern    string     `json:"pattern"`
+				ValidUntil *time.Time `json:"valid_until"`
+				Revoked    bool       `json:"revoked"`
+			} `json:"objects"`
+		}
+		if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+			return ThreatFeed{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+		for _, o := range bundle.Objects {
+			if o.Type != "indicator" || o.Revoked {
+				continue
+			}
+			for _, m := range stixAddressPattern.FindAllStringSubmatch(o.Pattern, -1) {
+				add(m[1], o.ValidUntil)
+			}
+		}
+
+	default:
+		return ThreatFeed{}, fmt.Errorf("%w: %q", ErrUnknownThreatFeedFormat, format)
+	}
+
+	return feed, nil
+}
+
+// AggregateThreatFeedEntries drops entries expired at now and duplicates,
+// then merges ranges contained in others and adjacent ranges into larger
+// ones, as long as lists accept them. Entries are returned sorted; a
+// merged entry expires with the last of the entries it replaces.
+func AggregateThreatFeedEntries(entries []ThreatFeedEntry, now time.Time) []ThreatFeedEntry {
+	type prefixEntry struct {
+		prefix  netip.Prefix
+		expires *time.Time
+	}
+
+	prefixes := make([]prefixEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Expires != nil && !e.Expires.After(now) {
+			continue
+		}
+		v, err := e.Value.Normalize()
+		if err != nil {
+			continue
+		}
+		prefixes = append(prefixes, prefixEntry{prefix: listIPPrefix(v), expires: e.Expires})
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool {
+		a, b := prefixes[i].prefix, prefixes[j].prefix
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+
+	later := func(a, b *time.Time) *time.Time {
+		if a == nil || b == nil {
+			return nil
+		}
+		if a.After(*b) {
+			return a
+		}
+		return b
+	}
+
+	var stack []prefixEntry
+	for _, p := range prefixes {
+		if n := len(stack); n > 0 && stack[n-1].prefix.Contains(p.prefix.Addr()) && stack[n-1].prefix.Bits() <= p.prefix.Bits() {
+			stack[n-1].expires = later(stack[n-1].expires, p.expires)
+			continue
+		}
+		stack = append(stack, p)
+
+		for n := len(stack); n >= 2; n = len(stack) {
+			parent, ok := mergeSiblingPrefixes(stack[n-2].prefix, stack[n-1].prefix)
+			if !ok {
+				break
+			}
+			merged := prefixEntry{prefix: parent, expires: later(stack[n-2].expires, stack[n-1].expires)}
+			stack = append(stack[:n-2], merged)
+		}
+	}
+
+	aggregated := make([]ThreatFeedEntry, 0, len(stack))
+	for _, p := range stack {
+		v, _ := ListIPValue(p.prefix.String()).Normalize()
+		aggregated = append(aggregated, ThreatFeedEntry{Value: v, Expires: p.expires})
+	}
+	return aggregated
+}
+
+func listIPPrefix(v ListIPValue) netip.Prefix {
+	if p, err := netip.ParsePrefix(string(v)); err == nil {
+		return p
+	}
+	addr, _ := netip.ParseAddr(string(v))
+	return netip.PrefixFrom(addr, addr.BitLen())
+}
+
+// mergeSiblingPrefixes returns the range made of a and b when they are the
+// two halves of a range lists accept.
+func mergeSiblingPrefixes(a, b netip.Prefix) (netip.Prefix, bool) {
+	minBits := 8
+	if a.Addr().Is6() {
+		minBits = 12
+	}
+	if a.Bits() != b.Bits() || a.Bits() <= minBits || a.Addr().Is4() != b.Addr().Is4() || a == b {
+		return netip.Prefix{}, false
+	}
+
+	parent, _ := a.Addr().Prefix(a.Bits() - 1)
+	if !parent.Contains(b.Addr()) {
+		return netip.Prefix{}, false
+	}
+	return parent, true
+}
+
+// ThreatFeedComment returns the comment of the items of a feed: its name
+// and the expiry time of the item.
+func ThreatFeedComment(name string, e ThreatFeedEntry) string {
+	if e.Expires == nil {
+		return "feed " + name
+	}
+	return fmt.Sprintf("feed %s, expires %s", name, e.Expires.UTC().Format(time.RFC3339))
+}
+
+type SyncThreatFeedParams struct {
+	// Name is the name of the feed. The feed is held by the lists named
+	// Name, Name_2, Name_3 and so on, which are created as needed.
+	Name    string
+	Entries []ThreatFeedEntry
+	// MaxItemsPerList defaults to 10000.
+	MaxItemsPerList int
+	// DryRun computes the changes without making them.
+	DryRun bool
+	// Poll configures how bulk operations are waited for.
+	Poll PollListBulkOperationParams
+}
+
+// ThreatFeedListChange describes the changes to one list of a feed.
+type ThreatFeedListChange struct {
+	ListID string
+	Name   string
+	// Created is set when the list didn't exist.
+	Created bool
+	Added   []ListEntry[ListIPValue]
+	Removed []ListEntry[ListIPValue]
+}
+
+// ThreatFeedSyncReport describes the outcome of SyncThreatFeed.
+type ThreatFeedSyncReport struct {
+	// Items is the number of items of the feed after aggregation.
+	Items int
+	Lists []ThreatFeedListChange
+}
+
+var threatFeedNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// SyncThreatFeed makes the lists of a feed hold its entries, aggregated
+// with AggregateThreatFeedEntries and commented with ThreatFeedComment.
+// Entries are split across as many lists as needed; lists left over from
+// larger syncs are emptied. Each list which changed has its items replaced
+// at once.
+func (api *API) SyncThreatFeed(ctx context.Context, rc *ResourceContainer, params SyncThreatFeedParams) (ThreatFeedSyncReport, error) {
+	if !threatFeedNamePattern.MatchString(params.Name) {
+		return ThreatFeedSyncReport{}, ErrInvalidThreatFeedName
+	}
+	if params.MaxItemsPerList <= 0 {
+		params.MaxItemsPerList = defaultThreatFeedListSize
+	}
+
+	entries := AggregateThreatFeedEntries(params.Entries, time.Now())
+	report := ThreatFeedSyncReport{Items: len(entries)}
+
+	existing, err := api.ListLists(ctx, rc, ListListsParams{})
+	if err != nil {
+		return ThreatFeedSyncReport{}, err
+	}
+	listsByName := map[string]List{}
+	for _, l := range existing {
+		listsByName[l.Name] = l
+	}
+
+	for i := 0; ; i++ {
+		name := params.Name
+		if i > 0 {
+			name = fmt.Sprintf("%s_%d", params.Name, i+1)
+		}
+		list, exists := listsByName[name]
+
+		if len(entries) == 0 && !exists {
+			break
+		}
+
+		var chunk []ListEntry[ListIPValue]
+		for ; len(chunk) < params.MaxItemsPerList && len(entries) > 0; entries = entries[1:] {
+			chunk = append(chunk, ListEntry[ListIPValue]{Value: entries[0].Value, Comment: ThreatFeedComment(params.Name, entries[0])})
+		}
+
+		change := ThreatFeedListChange{ListID: list.ID, Name: name, Created: !exists}
+		if exists {
+			if list.Kind != ListTypeIP {
+				return report, fmt.Errorf("%w: list %s is a %s list", ErrListItemKindMismatch, name, list.Kind)
+			}
+			client := NewListClient[ListIPValue](api, rc, list.ID)
+			diff, err := client.Sync(ctx, ListSyncParams[ListIPValue]{Items: chunk, DryRun: true})
+			if err != nil {
+				return report, err
+			}
+			change.Added, change.Removed = diff.Added, diff.Removed
+		} else {
+			change.Added = chunk
+		}
+
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		report.Lists = append(report.Lists, change)
+		if params.DryRun {
+			continue
+		}
+
+		if !exists {
+			list, err = api.CreateList(ctx, rc, ListCreateParams{
+				Name:        name,
+				Description: "Threat feed " + params.Name,
+				Kind:        ListTypeIP,
+			})
+			if err != nil {
+				return report, err
+			}
+			report.Lists[len(report.Lists)-1].ListID = list.ID
+		}
+
+		client := NewListClient[ListIPValue](api, rc, list.ID)
+		client.Poll = params.Poll
+		if _, err := client.Replace(ctx, chunk...); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,193 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThreatFeed(t *testing.T) {
+	feed, err := ParseThreatFeed(strings.NewReader(`# bad hosts
+192.0.2.1
+198.51.100.0/24   ; scanner
+not-an-ip
+
+2001:db8::1 # v6
+`), ThreatFeedFormatText)
+	require.NoError(t, err)
+	assert.Equal(t, []ThreatFeedEntry{{Value: "192.0.2.1"}, {Value: "198.51.100.0/24"}, {Value: "2001:db8::/64"}}, feed.Entries)
+	assert.Equal(t, []string{"not-an-ip"}, feed.Invalid)
+
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	feed, err = ParseThreatFeed(strings.NewReader(`first_seen,ip_address,valid_until
+2023-01-01,192.0.2.1,2030-01-01T00:00:00Z
+2023-01-02,203.0.113.9/33,
+2023-01-03,198.51.100.7,
+`), ThreatFeedFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []ThreatFeedEntry{{Value: "192.0.2.1", Expires: &expires}, {Value: "198.51.100.7"}}, feed.Entries)
+	assert.Equal(t, []string{"203.0.113.9/33"}, feed.Invalid)
+
+	feed, err = ParseThreatFeed(strings.NewReader(`{
+		"type": "bundle",
+		"objects": [
+			{"type": "indicator", "pattern": "[ipv4-addr:value = '192.0.2.1'] OR [ipv6-addr:value = '2001:db8::/48']", "valid_until": "2030-01-01T00:00:00Z"},
+			{"type": "indicator", "pattern": "[ipv4-addr:value = '198.51.100.1']", "revoked": true},
+			{"type": "indicator", "pattern": "[domain-name:value = 'example.com']"},
+			{"type": "malware", "name": "x"}
+		]
+	}`), ThreatFeedFormatSTIX)
+	require.NoError(t, err)
+	assert.Equal(t, []ThreatFeedEntry{{Value: "192.0.2.1", Expires: &expires}, {Value: "2001:db8::/48", Expires: &expires}}, feed.Entries)
+
+	_, err = ParseThreatFeed(strings.NewReader(""), "xml")
+	assert.ErrorIs(t, err, ErrUnknownThreatFeedFormat)
+}
+
+func TestAggregateThreatFeedEntries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	past, soon, later := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+
+	aggregated := AggregateThreatFeedEntries([]ThreatFeedEntry{
+		{Value: "192.0.2.128/25", Expires: &later},
+		{Value: "192.0.2.0/25", Expires: &soon},
+		{Value: "192.0.2.5"},
+		{Value: "198.51.100.1"},
+		{Value: "198.51.100.1/32"},
+		{Value: "198.51.100.9", Expires: &past},
+		{Value: "203.0.113.0"},
+		{Value: "203.0.113.1"},
+		{Value: "203.0.113.2"},
+		{Value: "203.0.113.3", Expires: &soon},
+		{Value: "2001:db8::1"},
+		{Value: "2001:db8:0:1::/64"},
+		{Value: "10.0.0.0/9"},
+		{Value: "10.128.0.0/9"},
+	}, now)
+
+	assert.Equal(t, []ThreatFeedEntry{
+		{Value: "10.0.0.0/8"},
+		{Value: "192.0.2.0/24"},
+		{Value: "198.51.100.1"},
+		{Value: "203.0.113.0/30"},
+		{Value: "2001:db8::/63"},
+	}, aggregated)
+
+	aggregated = AggregateThreatFeedEntries([]ThreatFeedEntry{
+		{Value: "192.0.2.0/25", Expires: &soon},
+		{Value: "192.0.2.128/25", Expires: &later},
+		{Value: "10.0.0.0/8"},
+		{Value: "11.0.0.0/8"},
+	}, now)
+	assert.Equal(t, []ThreatFeedEntry{{Value: "10.0.0.0/8"}, {Value: "11.0.0.0/8"}, {Value: "192.0.2.0/24", Expires: &later}}, aggregated)
+}
+
+func TestSyncThreatFeed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{
+				"result": [
+					{"id": "list1", "name": "feed", "kind": "ip", "num_items": 2},
+					{"id": "list3", "name": "feed_3", "kind": "ip", "num_items": 1},
+					{"id": "other", "name": "other", "kind": "ip", "num_items": 1}
+				],
+				"success": true,
+				"errors": [],
+				"messages": []
+			}`)
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"name": "feed_2", "description": "Threat feed feed", "kind": "ip"}`, string(body))
+			fmt.Fprint(w, `{"result": {"id": "list2", "name": "feed_2", "kind": "ip"}, "success": true, "errors": [], "messages": []}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	replaced := map[string]string{}
+	items := map[string]string{
+		"list1": `[{"id": "1", "ip": "192.0.2.1", "comment": "feed feed"}, {"id": "2", "ip": "192.0.2.2", "comment": "feed feed"}]`,
+		"list3": `[{"id": "3", "ip": "203.0.113.1", "comment": "feed feed"}]`,
+	}
+	for _, id := range []string{"list1", "list2", "list3"} {
+		id := id
+		mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/"+id+"/items", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprintf(w, `{"result": %s, "result_info": {"cursors": {}}, "success": true, "errors": [], "messages": []}`, items[id])
+			case http.MethodPut:
+				body, _ := io.ReadAll(r.Body)
+				replaced[id] = string(body)
+				fmt.Fprint(w, `{"result": {"operation_id": "op"}, "success": true, "errors": [], "messages": []}`)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+		})
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/rules/lists/bulk_operations/op", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"result": {"id": "op", "status": "completed"}, "success": true, "errors": [], "messages": []}`)
+	})
+
+	params := SyncThreatFeedParams{
+		Name: "feed",
+		Entries: []ThreatFeedEntry{
+			{Value: "192.0.2.1"},
+			{Value: "192.0.2.3"},
+			{Value: "198.51.100.1"},
+		},
+		MaxItemsPerList: 2,
+		DryRun:          true,
+		Poll:            PollListBulkOperationParams{Interval: time.Millisecond},
+	}
+
+	report, err := client.SyncThreatFeed(context.Background(), AccountIdentifier(testAccountID), params)
+	require.NoError(t, err)
+	assert.Equal(t, ThreatFeedSyncReport{
+		Items: 3,
+		Lists: []ThreatFeedListChange{
+			{
+				ListID:  "list1",
+				Name:    "feed",
+				Added:   []ListEntry[ListIPValue]{{Value: "192.0.2.3", Comment: "feed feed"}},
+				Removed: []ListEntry[ListIPValue]{{ID: "2", Value: "192.0.2.2", Comment: "feed feed"}},
+			},
+			{
+				Name:    "feed_2",
+				Created: true,
+				Added:   []ListEntry[ListIPValue]{{Value: "198.51.100.1", Comment: "feed feed"}},
+			},
+			{
+				ListID:  "list3",
+				Name:    "feed_3",
+				Removed: []ListEntry[ListIPValue]{{ID: "3", Value: "203.0.113.1", Comment: "feed feed"}},
+			},
+		},
+	}, report)
+	assert.Empty(t, replaced)
+
+	params.DryRun = false
+	report, err = client.SyncThreatFeed(context.Background(), AccountIdentifier(testAccountID), params)
+	require.NoError(t, err)
+	assert.Equal(t, "list2", report.Lists[1].ListID)
+	assert.JSONEq(t, `[{"ip": "192.0.2.1", "comment": "feed feed"}, {"ip": "192.0.2.3", "comment": "feed feed"}]`, replaced["list1"])
+	assert.JSONEq(t, `[{"ip": "198.51.100.1", "comment": "feed feed"}]`, replaced["list2"])
+	assert.JSONEq(t, `[]`, replaced["list3"])
+
+	_, err = client.SyncThreatFeed(context.Background(), AccountIdentifier(testAccountID), SyncThreatFeedParams{Name: "bad-name"})
+	assert.ErrorIs(t, err, ErrInvalidThreatFeedName)
+}
@@ -108,6 +108,155 @@ var PageRuleActions = map[string]string{
 	"waf":                         "Web Application Firewall",    // Value of type string
 }
 
+// PageRuleActionID is the ID of a page rule action.
+type PageRuleActionID string
+
+const (
+	PageRuleActionAlwaysOnline            PageRuleActionID = "always_online"
+	PageRuleActionAlwaysUseHTTPS          PageRuleActionID = "always_use_https"
+	PageRuleActionAutomaticHTTPSRewrites  PageRuleActionID = "automatic_https_rewrites"
+	PageRuleActionBrowserCacheTTL         PageRuleActionID = "browser_cache_ttl"
+	PageRuleActionBrowserCheck            PageRuleActionID = "browser_check"
+	PageRuleActionBypassCacheOnCookie     PageRuleActionID = "bypass_cache_on_cookie"
+	PageRuleActionCacheByDeviceType       PageRuleActionID = "cache_by_device_type"
+	PageRuleActionCacheDeceptionArmor     PageRuleActionID = "cache_deception_armor"
+	PageRuleActionCacheLevel              PageRuleActionID = "cache_level"
+	PageRuleActionCacheKeyFields          PageRuleActionID = "cache_key_fields"
+	PageRuleActionCacheOnCookie           PageRuleActionID = "cache_on_cookie"
+	PageRuleActionDisableApps             PageRuleActionID = "disable_apps"
+	PageRuleActionDisablePerformance      PageRuleActionID = "disable_performance"
+	PageRuleActionDisableRailgun          PageRuleActionID = "disable_railgun"
+	PageRuleActionDisableSecurity         PageRuleActionID = "disable_security"
+	PageRuleActionEdgeCacheTTL            PageRuleActionID = "edge_cache_ttl"
+	PageRuleActionEmailObfuscation        PageRuleActionID = "email_obfuscation"
+	PageRuleActionExplicitCacheControl    PageRuleActionID = "explicit_cache_control"
+	PageRuleActionForwardingURL           PageRuleActionID = "forwarding_url"
+	PageRuleActionHostHeaderOverride      PageRuleActionID = "host_header_override"
+	PageRuleActionIPGeolocation           PageRuleActionID = "ip_geolocation"
+	PageRuleActionMinify                  PageRuleActionID = "minify"
+	PageRuleActionMirage                  PageRuleActionID = "mirage"
+	PageRuleActionOpportunisticEncryption PageRuleActionID = "opportunistic_encryption"
+	PageRuleActionOriginErrorPagePassThru PageRuleActionID = "origin_error_page_pass_thru"
+	PageRuleActionPolish                  PageRuleActionID = "polish"
+	PageRuleActionResolveOverride         PageRuleActionID = "resolve_override"
+	PageRuleActionRespectStrongETag       PageRuleActionID = "respect_strong_etag"
+	PageRuleActionResponseBuffering       PageRuleActionID = "response_buffering"
+	PageRuleActionRocketLoader            PageRuleActionID = "rocket_loader"
+	PageRuleActionSecurityLevel           PageRuleActionID = "security_level"
+	PageRuleActionServerSideExclude       PageRuleActionID = "server_side_exclude"
+	PageRuleActionSortQueryStringForCache PageRuleActionID = "sort_query_string_for_cache"
+	PageRuleActionSSL                     PageRuleActionID = "ssl"
+	PageRuleActionTrueClientIPHeader      PageRuleActionID = "true_client_ip_header"
+	PageRuleActionWAF                     PageRuleActionID = "waf"
+)
+
+// PageRuleForwardingURL is the value of a forwarding_url action. URL may
+// refer to the wildcards of the target with $1, $2 and so on.
+type PageRuleForwardingURL struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
+
+// PageRuleMinify is the value of a minify action. Each field is "on" or
+// "off".
+type PageRuleMinify struct {
+	HTML string `json:"html"`
+	CSS  string `json:"css"`
+	JS   string `json:"js"`
+}
+
+// PageRuleCacheKeyFields is the value of a cache_key_fields action.
+type PageRuleCacheKeyFields struct {
+	QueryString PageRuleCacheKeyQueryString `json:"query_string"`
+	Header      PageRuleCacheKeyHeader      `json:"header"`
+	Cookie      PageRuleCacheKeyCookie      `json:"cookie"`
+	Host        PageRuleCacheKeyHost        `json:"host"`
+	User        PageRuleCacheKeyUser        `json:"user"`
+}
+
+// PageRuleCacheKeyQueryString selects the query string parameters in the
+// cache key. Include or Exclude may be every parameter.
+type PageRuleCacheKeyQueryString struct {
+	Include *RulesetRuleActionParametersCustomKeyList `json:"include,omitempty"`
+	Exclude *RulesetRuleActionParametersCustomKeyList `json:"exclude,omitempty"`
+}
+
+type PageRuleCacheKeyHeader struct {
+	Include       []string `json:"include,omitempty"`
+	Exclude       []string `json:"exclude,omitempty"`
+	CheckPresence []string `json:"check_presence,omitempty"`
+}
+
+type PageRuleCacheKeyCookie struct {
+	Include       []string `json:"include,omitempty"`
+	CheckPresence []string `json:"check_presence,omitempty"`
+}
+
+type PageRuleCacheKeyHost struct {
+	Resolved bool `json:"resolved"`
+}
+
+type PageRuleCacheKeyUser struct {
+	DeviceType bool `json:"device_type"`
+	Geo        bool `json:"geo"`
+	Lang       bool `json:"lang"`
+}
+
+// NewPageRuleAction returns an action with a typed value, such as a
+// PageRuleForwardingURL for PageRuleActionForwardingURL.
+func NewPageRuleAction(id PageRuleActionID, value interface{}) PageRuleAction {
+	return PageRuleAction{ID: string(id), Value: value}
+}
+
+// DecodeValue decodes the value of the action into v, which should point
+// to the type of value the action takes.
+func (a PageRuleAction) DecodeValue(v interface{}) error {
+	b, err := json.Marshal(a.Value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("invalid value for page rule action %q: %w", a.ID, err)
+	}
+	return nil
+}
+
+// StringValue returns the value of actions which take a string, such as
+// "on" or "off".
+func (a PageRuleAction) StringValue() (string, error) {
+	var s string
+	err := a.DecodeValue(&s)
+	return s, err
+}
+
+// IntValue returns the value of actions which take a number of seconds.
+func (a PageRuleAction) IntValue() (int, error) {
+	var i int
+	err := a.DecodeValue(&i)
+	return i, err
+}
+
+// ForwardingURL returns the value of a forwarding_url action.
+func (a PageRuleAction) ForwardingURL() (PageRuleForwardingURL, error) {
+	var v PageRuleForwardingURL
+	err := a.DecodeValue(&v)
+	return v, err
+}
+
+// Minify returns the value of a minify action.
+func (a PageRuleAction) Minify() (PageRuleMinify, error) {
+	var v PageRuleMinify
+	err := a.DecodeValue(&v)
+	return v, err
+}
+
+// CacheKeyFields returns the value of a cache_key_fields action.
+func (a PageRuleAction) CacheKeyFields() (PageRuleCacheKeyFields, error) {
+	var v PageRuleCacheKeyFields
+	err := a.DecodeValue(&v)
+	return v, err
+}
+
 // PageRule describes a Page Rule.
 type PageRule struct {
 	ID         string           `json:"id,omitempty"`
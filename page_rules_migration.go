@@ -0,0 +1,454 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PageRuleConversionIssue is a page rule action which was dropped or only
+// approximated when converting page rules to rulesets.
+type PageRuleConversionIssue struct {
+	PageRuleID string
+	// ActionID is empty when the whole page rule couldn't be converted.
+	ActionID PageRuleActionID
+	Reason   string
+	// Dropped is set when the action has no equivalent in the rulesets.
+	Dropped bool
+}
+
+// PageRulesConversion holds the rules replacing a list of page rules, by
+// phase, and the page rule actions which didn't convert exactly.
+type PageRulesConversion struct {
+	Rules  map[RulesetPhase][]RulesetRule
+	Issues []PageRuleConversionIssue
+}
+
+// pageRuleConversion holds what a single page rule converts to.
+type pageRuleConversion struct {
+	pageRuleID string
+	pattern    string
+	// captureOffset is the number of wildcards pattern has in front of the
+	// ones of the page rule.
+	captureOffset int
+	expression    string
+	opts          []RulesetRuleOption
+	issues        []PageRuleConversionIssue
+
+	redirectExpression string
+	redirect           *RulesetRuleActionParametersFromValue
+	cache              *RulesetCacheSettingsParameters
+	config             *RulesetConfigSettingsParameters
+	route              *RulesetRouteParameters
+}
+
+var pageRuleWildcardReference = regexp.MustCompile(`\$([0-9])`)
+
+// ConvertPageRules converts page rules to rules of the
+// http_request_dynamic_redirect, http_request_cache_settings,
+// http_config_settings and http_request_origin phases. Each page rule gives
+// at most one rule per phase, with the page rule ID as ref, matching its
+// URL pattern with the wildcard operator on http.request.full_uri.
+//
+// A request only uses the first page rule it matches while every matching
+// rule of a phase applies, later rules overriding earlier ones. Rules are
+// ordered so that higher priority page rules win, but settings of lower
+// priority page rules with overlapping patterns now apply too.
+func ConvertPageRules(pageRules []PageRule) (PageRulesConversion, error) {
+	conversion := PageRulesConversion{Rules: map[RulesetPhase][]RulesetRule{}}
+
+	ordered := make([]PageRule, len(pageRules))
+	copy(ordered, pageRules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority > ordered[j].Priority
+	})
+
+	converted := make([]*pageRuleConversion, len(ordered))
+	for i, pr := range ordered {
+		c, issues := convertPageRule(pr)
+		converted[i] = c
+		conversion.Issues = append(conversion.Issues, issues...)
+	}
+
+	// Redirects stop at the first matching rule, so the highest priority
+	// comes first. Settings of later rules override earlier ones, so it
+	// comes last in the other phases.
+	redirects := NewDynamicRedirectRulesetBuilder()
+	for _, c := range converted {
+		if c != nil && c.redirect != nil {
+			redirects.Redirect(c.redirectExpression, *c.redirect, c.opts...)
+		}
+	}
+
+	cache := NewCacheSettingsRulesetBuilder()
+	config := NewConfigSettingsRulesetBuilder()
+	origin := NewOriginRulesetBuilder()
+	for i := len(converted) - 1; i >= 0; i-- {
+		c := converted[i]
+		if c == nil {
+			continue
+		}
+		if c.cache != nil {
+			cache.SetCacheSettings(c.expression, *c.cache, c.opts...)
+		}
+		if c.config != nil {
+			config.SetConfig(c.expression, *c.config, c.opts...)
+		}
+		if c.route != nil {
+			origin.Route(c.expression, *c.route, c.opts...)
+		}
+	}
+
+	for _, b := range []interface {
+		Phase() RulesetPhase
+		Rules() ([]RulesetRule, error)
+	}{redirects, cache, config, origin} {
+		rules, err := b.Rules()
+		if err != nil {
+			return PageRulesConversion{}, fmt.Errorf("%s: %w", b.Phase(), err)
+		}
+		if len(rules) > 0 {
+			conversion.Rules[b.Phase()] = rules
+		}
+	}
+
+	return conversion, nil
+}
+
+// ConvertZonePageRules converts the page rules of a zone with
+// ConvertPageRules. The page rules are left in place.
+func (api *API) ConvertZonePageRules(ctx context.Context, zoneID string) (PageRulesConversion, error) {
+	pageRules, err := api.ListPageRules(ctx, zoneID)
+	if err != nil {
+		return PageRulesConversion{}, err
+	}
+	return ConvertPageRules(pageRules)
+}
+
+// pageRuleTargetPattern returns the URL pattern of a page rule.
+func pageRuleTargetPattern(pr PageRule) (string, bool) {
+	for _, t := range pr.Targets {
+		if t.Target == "url" && t.Constraint.Operator == "matches" {
+			return t.Constraint.Value, true
+		}
+	}
+	return "", false
+}
+
+// fullURIWildcardPattern turns a URL pattern such as "*example.com/api/*"
+// into a wildcard pattern of http.request.full_uri. Patterns without a
+// scheme match both http and https.
+func fullURIWildcardPattern(pattern string) string {
+	if !strings.Contains(pattern, "://") {
+		pattern = "http*://" + pattern
+	}
+	if rest := pattern[strings.Index(pattern, "://")+3:]; !strings.Contains(rest, "/") {
+		pattern += "/"
+	}
+	return pattern
+}
+
+func convertPageRule(pr PageRule) (*pageRuleConversion, []PageRuleConversionIssue) {
+	c := &pageRuleConversion{pageRuleID: pr.ID}
+
+	target, ok := pageRuleTargetPattern(pr)
+	if !ok {
+		c.issue("", true, "page rule has no URL target")
+		return nil, c.issues
+	}
+	c.pattern = fullURIWildcardPattern(target)
+	if !strings.Contains(target, "://") {
+		// The wildcard of the scheme comes before the page rule's own.
+		c.captureOffset = 1
+	}
+
+	c.expression = "http.request.full_uri wildcard " + quoteExpressionString(c.pattern)
+	c.opts = []RulesetRuleOption{
+		WithRulesetRuleRef(pr.ID),
+		WithRulesetRuleDescription("Page rule " + pr.Targets[0].Constraint.Value),
+	}
+	if pr.Status == "disabled" {
+		c.opts = append(c.opts, WithRulesetRuleDisabled())
+	}
+
+	for _, a := range pr.Actions {
+		if err := c.addAction(a); err != nil {
+			c.issue(PageRuleActionID(a.ID), true, "%s", err)
+		}
+	}
+
+	// Drop settings left empty by actions which failed to convert.
+	if c.cache != nil && reflect.DeepEqual(*c.cache, RulesetCacheSettingsParameters{}) {
+		c.cache = nil
+	}
+	if c.config != nil && reflect.DeepEqual(*c.config, RulesetConfigSettingsParameters{}) {
+		c.config = nil
+	}
+	if c.route != nil && reflect.DeepEqual(*c.route, RulesetRouteParameters{}) {
+		c.route = nil
+	}
+
+	return c, c.issues
+}
+
+func (c *pageRuleConversion) issue(id PageRuleActionID, dropped bool, format string, args ...interface{}) {
+	c.issues = append(c.issues, PageRuleConversionIssue{
+		PageRuleID: c.pageRuleID,
+		ActionID:   id,
+		Reason:     fmt.Sprintf(format, args...),
+		Dropped:    dropped,
+	})
+}
+
+func (c *pageRuleConversion) cacheSettings() *RulesetCacheSettingsParameters {
+	if c.cache == nil {
+		c.cache = &RulesetCacheSettingsParameters{}
+	}
+	return c.cache
+}
+
+func (c *pageRuleConversion) cacheKey() *RulesetRuleActionParametersCacheKey {
+	cache := c.cacheSettings()
+	if cache.CacheKey == nil {
+		cache.CacheKey = &RulesetRuleActionParametersCacheKey{}
+	}
+	return cache.CacheKey
+}
+
+func (c *pageRuleConversion) configSettings() *RulesetConfigSettingsParameters {
+	if c.config == nil {
+		c.config = &RulesetConfigSettingsParameters{}
+	}
+	return c.config
+}
+
+func (c *pageRuleConversion) routeSettings() *RulesetRouteParameters {
+	if c.route == nil {
+		c.route = &RulesetRouteParameters{}
+	}
+	return c.route
+}
+
+// addAction converts a page rule action. Approximations are recorded as
+// issues; an error means the action was dropped.
+func (c *pageRuleConversion) addAction(a PageRuleAction) error {
+	id := PageRuleActionID(a.ID)
+
+	onOff := func() (*bool, error) {
+		s, err := a.StringValue()
+		if err != nil {
+			return nil, err
+		}
+		if s != "on" && s != "off" {
+			return nil, fmt.Errorf("invalid value %q, want \"on\" or \"off\"", s)
+		}
+		return BoolPtr(s == "on"), nil
+	}
+
+	var err error
+	switch id {
+	case PageRuleActionForwardingURL:
+		var v PageRuleForwardingURL
+		if v, err = a.ForwardingURL(); err != nil {
+			return err
+		}
+		from := RulesetRuleActionParametersFromValue{
+			StatusCode:          uint16(v.StatusCode),
+			PreserveQueryString: BoolPtr(!strings.Contains(v.URL, "?")),
+		}
+		if pageRuleWildcardReference.MatchString(v.URL) {
+			target := pageRuleWildcardReference.ReplaceAllStringFunc(v.URL, func(ref string) string {
+				n, _ := strconv.Atoi(ref[1:])
+				return fmt.Sprintf("${%d}", n+c.captureOffset)
+			})
+			from.TargetURL.Expression = fmt.Sprintf("wildcard_replace(http.request.full_uri, %s, %s)",
+				quoteExpressionString(c.pattern), quoteExpressionString(target))
+		} else {
+			from.TargetURL.Value = v.URL
+		}
+		c.redirectExpression = c.expression
+		c.redirect = &from
+
+	case PageRuleActionAlwaysUseHTTPS:
+		c.redirectExpression = "(" + c.expression + ") and not ssl"
+		c.redirect = &RulesetRuleActionParametersFromValue{
+			StatusCode: 301,
+			TargetURL: RulesetRuleActionParametersTargetURL{
+				Expression: `concat("https://", http.host, http.request.uri.path)`,
+			},
+			PreserveQueryString: BoolPtr(true),
+		}
+
+	case PageRuleActionCacheLevel:
+		var s string
+		if s, err = a.StringValue(); err != nil {
+			return err
+		}
+		switch s {
+		case "bypass":
+			c.cacheSettings().Cache = BoolPtr(false)
+		case "cache_everything":
+			c.cacheSettings().Cache = BoolPtr(true)
+		case "simplified":
+			c.cacheKey().CustomKey = &RulesetRuleActionParametersCustomKey{
+				Query: &RulesetRuleActionParametersCustomKeyQuery{Exclude: &RulesetRuleActionParametersCustomKeyList{All: true}},
+			}
+		case "aggressive":
+		case "basic":
+			c.issue(id, true, "cache level %q has no equivalent, requests with a query string are cached", s)
+		default:
+			return fmt.Errorf("unknown cache level %q", s)
+		}
+
+	case PageRuleActionEdgeCacheTTL:
+		var ttl int
+		if ttl, err = a.IntValue(); err != nil {
+			return err
+		}
+		c.cacheSettings().EdgeTTL = &RulesetRuleActionParametersEdgeTTL{Mode: "override_origin", Default: UintPtr(uint(ttl))}
+
+	case PageRuleActionBrowserCacheTTL:
+		var ttl int
+		if ttl, err = a.IntValue(); err != nil {
+			return err
+		}
+		if ttl == 0 {
+			c.cacheSettings().BrowserTTL = &RulesetRuleActionParametersBrowserTTL{Mode: "respect_origin"}
+		} else {
+			c.cacheSettings().BrowserTTL = &RulesetRuleActionParametersBrowserTTL{Mode: "override_origin", Default: UintPtr(uint(ttl))}
+		}
+
+	case PageRuleActionExplicitCacheControl:
+		var on *bool
+		if on, err = onOff(); err != nil {
+			return err
+		}
+		if *on {
+			c.cacheSettings().EdgeTTL = &RulesetRuleActionParametersEdgeTTL{Mode: "respect_origin"}
+			c.issue(id, false, "origin cache control is approximated by respecting the origin edge TTL")
+		}
+
+	case PageRuleActionCacheDeceptionArmor:
+		c.cacheKey().CacheDeceptionArmor, err = onOff()
+	case PageRuleActionCacheByDeviceType:
+		c.cacheKey().CacheByDeviceType, err = onOff()
+	case PageRuleActionSortQueryStringForCache:
+		c.cacheKey().IgnoreQueryStringsOrder, err = onOff()
+	case PageRuleActionRespectStrongETag:
+		c.cacheSettings().RespectStrongETags, err = onOff()
+	case PageRuleActionOriginErrorPagePassThru:
+		c.cacheSettings().OriginErrorPagePassthru, err = onOff()
+
+	case PageRuleActionCacheKeyFields:
+		var v PageRuleCacheKeyFields
+		if v, err = a.CacheKeyFields(); err != nil {
+			return err
+		}
+		key := &RulesetRuleActionParametersCustomKey{
+			Query: &RulesetRuleActionParametersCustomKeyQuery{Include: v.QueryString.Include, Exclude: v.QueryString.Exclude},
+			Host:  &RulesetRuleActionParametersCustomKeyHost{Resolved: BoolPtr(v.Host.Resolved)},
+			User: &RulesetRuleActionParametersCustomKeyUser{
+				DeviceType: BoolPtr(v.User.DeviceType),
+				Geo:        BoolPtr(v.User.Geo),
+				Lang:       BoolPtr(v.User.Lang),
+			},
+		}
+		if len(v.Header.Include) > 0 || len(v.Header.CheckPresence) > 0 {
+			key.Header = &RulesetRuleActionParametersCustomKeyHeader{
+				RulesetRuleActionParametersCustomKeyFields: RulesetRuleActionParametersCustomKeyFields{
+					Include:       v.Header.Include,
+					CheckPresence: v.Header.CheckPresence,
+				},
+			}
+		}
+		if len(v.Header.Exclude) > 0 {
+			c.issue(id, true, "excluding headers from the cache key has no equivalent")
+		}
+		if len(v.Cookie.Include) > 0 || len(v.Cookie.CheckPresence) > 0 {
+			key.Cookie = &RulesetRuleActionParametersCustomKeyCookie{
+				Include:       v.Cookie.Include,
+				CheckPresence: v.Cookie.CheckPresence,
+			}
+		}
+		c.cacheKey().CustomKey = key
+
+	case PageRuleActionAutomaticHTTPSRewrites:
+		c.configSettings().AutomaticHTTPSRewrites, err = onOff()
+	case PageRuleActionBrowserCheck:
+		c.configSettings().BrowserIntegrityCheck, err = onOff()
+	case PageRuleActionEmailObfuscation:
+		c.configSettings().EmailObfuscation, err = onOff()
+	case PageRuleActionMirage:
+		c.configSettings().Mirage, err = onOff()
+	case PageRuleActionOpportunisticEncryption:
+		c.configSettings().OpportunisticEncryption, err = onOff()
+	case PageRuleActionRocketLoader:
+		c.configSettings().RocketLoader, err = onOff()
+	case PageRuleActionServerSideExclude:
+		c.configSettings().ServerSideExcludes, err = onOff()
+	case PageRuleActionDisableApps:
+		c.configSettings().DisableApps = BoolPtr(true)
+	case PageRuleActionDisableRailgun:
+		c.configSettings().DisableRailgun = BoolPtr(true)
+
+	case PageRuleActionDisablePerformance:
+		cfg := c.configSettings()
+		cfg.RocketLoader = BoolPtr(false)
+		cfg.Mirage = BoolPtr(false)
+		cfg.Polish = PolishOff.IntoRef()
+		cfg.AutoMinify = &RulesetRuleActionParametersAutoMinify{}
+
+	case PageRuleActionPolish:
+		var s string
+		if s, err = a.StringValue(); err != nil {
+			return err
+		}
+		c.configSettings().Polish, err = PolishFromString(s)
+	case PageRuleActionSecurityLevel:
+		var s string
+		if s, err = a.StringValue(); err != nil {
+			return err
+		}
+		c.configSettings().SecurityLevel, err = SecurityLevelFromString(s)
+	case PageRuleActionSSL:
+		var s string
+		if s, err = a.StringValue(); err != nil {
+			return err
+		}
+		c.configSettings().SSL, err = SSLFromString(s)
+
+	case PageRuleActionMinify:
+		var v PageRuleMinify
+		if v, err = a.Minify(); err != nil {
+			return err
+		}
+		c.configSettings().AutoMinify = &RulesetRuleActionParametersAutoMinify{
+			HTML: v.HTML == "on",
+			CSS:  v.CSS == "on",
+			JS:   v.JS == "on",
+		}
+
+	case PageRuleActionHostHeaderOverride:
+		c.routeSettings().HostHeader, err = a.StringValue()
+	case PageRuleActionResolveOverride:
+		var host string
+		if host, err = a.StringValue(); err != nil {
+			return err
+		}
+		c.routeSettings().Origin = &RulesetRuleActionParametersOrigin{Host: host}
+
+	case PageRuleActionAlwaysOnline, PageRuleActionBypassCacheOnCookie, PageRuleActionCacheOnCookie,
+		PageRuleActionDisableSecurity, PageRuleActionIPGeolocation, PageRuleActionResponseBuffering,
+		PageRuleActionTrueClientIPHeader, PageRuleActionWAF:
+		return fmt.Errorf("%s has no equivalent in these phases", id)
+
+	default:
+		return fmt.Errorf("unknown action %q", a.ID)
+	}
+
+	return err
+}
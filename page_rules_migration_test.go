@@ -0,0 +1,153 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPageRule(id, pattern string, priority int, actions ...PageRuleAction) PageRule {
+	pr := PageRule{ID: id, Priority: priority, Status: "active", Actions: actions}
+	pr.Targets = []PageRuleTarget{{Target: "url"}}
+	pr.Targets[0].Constraint.Operator = "matches"
+	pr.Targets[0].Constraint.Value = pattern
+	return pr
+}
+
+func TestConvertPageRules(t *testing.T) {
+	disabled := testPageRule("pr4", "example.com", 4, NewPageRuleAction(PageRuleActionSSL, "full"))
+	disabled.Status = "disabled"
+
+	conversion, err := ConvertPageRules([]PageRule{
+		testPageRule("pr1", "example.com/static/*", 1,
+			NewPageRuleAction(PageRuleActionCacheLevel, "cache_everything"),
+			NewPageRuleAction(PageRuleActionEdgeCacheTTL, float64(7200)),
+			NewPageRuleAction(PageRuleActionCacheDeceptionArmor, "on"),
+			NewPageRuleAction(PageRuleActionRocketLoader, "off"),
+			NewPageRuleAction(PageRuleActionAlwaysOnline, "on"),
+		),
+		testPageRule("pr2", "*.example.com/*", 3,
+			NewPageRuleAction(PageRuleActionForwardingURL, PageRuleForwardingURL{URL: "https://example.com/$2", StatusCode: 301}),
+		),
+		testPageRule("pr3", "https://example.com/api/*", 2,
+			NewPageRuleAction(PageRuleActionSecurityLevel, "high"),
+			NewPageRuleAction(PageRuleActionResolveOverride, "api.internal.example.com"),
+			NewPageRuleAction(PageRuleActionCacheLevel, "basic"),
+		),
+		disabled,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []RulesetRule{{
+		Action:     "redirect",
+		Expression: `http.request.full_uri wildcard "http*://*.example.com/*"`,
+		ActionParameters: &RulesetRuleActionParameters{FromValue: &RulesetRuleActionParametersFromValue{
+			StatusCode: 301,
+			TargetURL: RulesetRuleActionParametersTargetURL{
+				Expression: `wildcard_replace(http.request.full_uri, "http*://*.example.com/*", "https://example.com/${3}")`,
+			},
+			PreserveQueryString: BoolPtr(true),
+		}},
+		Ref:         "pr2",
+		Description: "Page rule *.example.com/*",
+	}}, conversion.Rules[RulesetPhaseHTTPRequestDynamicRedirect])
+
+	assert.Equal(t, []RulesetRule{{
+		Action:     "set_cache_settings",
+		Expression: `http.request.full_uri wildcard "http*://example.com/static/*"`,
+		ActionParameters: &RulesetRuleActionParameters{
+			Cache:    BoolPtr(true),
+			EdgeTTL:  &RulesetRuleActionParametersEdgeTTL{Mode: "override_origin", Default: UintPtr(7200)},
+			CacheKey: &RulesetRuleActionParametersCacheKey{CacheDeceptionArmor: BoolPtr(true)},
+		},
+		Ref:         "pr1",
+		Description: "Page rule example.com/static/*",
+	}}, conversion.Rules[RulesetPhaseHTTPRequestCacheSettings])
+
+	// The highest priority page rule comes last so that its settings win.
+	config := conversion.Rules[RulesetPhaseHTTPConfigSettings]
+	require.Len(t, config, 3)
+	assert.Equal(t, []string{"pr1", "pr3", "pr4"}, []string{config[0].Ref, config[1].Ref, config[2].Ref})
+	assert.Equal(t, BoolPtr(false), config[0].ActionParameters.RocketLoader)
+	assert.Equal(t, SecurityLevelHigh.IntoRef(), config[1].ActionParameters.SecurityLevel)
+	assert.Equal(t, `http.request.full_uri wildcard "https://example.com/api/*"`, config[1].Expression)
+	assert.Equal(t, `http.request.full_uri wildcard "http*://example.com/"`, config[2].Expression)
+	assert.Equal(t, BoolPtr(false), config[2].Enabled)
+
+	assert.Equal(t, []RulesetRule{{
+		Action:           "route",
+		Expression:       `http.request.full_uri wildcard "https://example.com/api/*"`,
+		ActionParameters: &RulesetRuleActionParameters{Origin: &RulesetRuleActionParametersOrigin{Host: "api.internal.example.com"}},
+		Ref:              "pr3",
+		Description:      "Page rule https://example.com/api/*",
+	}}, conversion.Rules[RulesetPhaseHTTPRequestOrigin])
+
+	assert.Equal(t, []PageRuleConversionIssue{
+		{PageRuleID: "pr3", ActionID: PageRuleActionCacheLevel, Reason: `cache level "basic" has no equivalent, requests with a query string are cached`, Dropped: true},
+		{PageRuleID: "pr1", ActionID: PageRuleActionAlwaysOnline, Reason: "always_online has no equivalent in these phases", Dropped: true},
+	}, conversion.Issues)
+}
+
+func TestConvertPageRules_ForwardingURLReferences(t *testing.T) {
+	testCases := map[string]struct {
+		pattern, url, expression string
+	}{
+		"subdomain": {
+			pattern:    "*.example.com/*",
+			url:        "https://$1.example.net/",
+			expression: `wildcard_replace(http.request.full_uri, "http*://*.example.com/*", "https://${2}.example.net/")`,
+		},
+		"scheme in pattern": {
+			pattern:    "https://*.example.com/*",
+			url:        "https://example.net/$2",
+			expression: `wildcard_replace(http.request.full_uri, "https://*.example.com/*", "https://example.net/${2}")`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			conversion, err := ConvertPageRules([]PageRule{
+				testPageRule("pr1", tc.pattern, 1, NewPageRuleAction(PageRuleActionForwardingURL, PageRuleForwardingURL{URL: tc.url, StatusCode: 302})),
+			})
+			require.NoError(t, err)
+			rules := conversion.Rules[RulesetPhaseHTTPRequestDynamicRedirect]
+			require.Len(t, rules, 1)
+			assert.Equal(t, tc.expression, rules[0].ActionParameters.FromValue.TargetURL.Expression)
+		})
+	}
+}
+
+func TestConvertZonePageRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/pagerules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "pr1",
+				"targets": [{"target": "url", "constraint": {"operator": "matches", "value": "example.com/*"}}],
+				"actions": [{"id": "always_use_https"}],
+				"priority": 1,
+				"status": "active"
+			}]
+		}`)
+	})
+
+	conversion, err := client.ConvertZonePageRules(context.Background(), testZoneID)
+	require.NoError(t, err)
+
+	redirects := conversion.Rules[RulesetPhaseHTTPRequestDynamicRedirect]
+	require.Len(t, redirects, 1)
+	assert.Equal(t, `(http.request.full_uri wildcard "http*://example.com/*") and not ssl`, redirects[0].Expression)
+	assert.Equal(t, `concat("https://", http.host, http.request.uri.path)`, redirects[0].ActionParameters.FromValue.TargetURL.Expression)
+	assert.Empty(t, conversion.Issues)
+}
@@ -7,7 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -196,3 +198,41 @@ func TestDeletePageRule(t *testing.T) {
 	err := client.DeletePageRule(context.Background(), testZoneID, pageRuleID)
 	assert.NoError(t, err)
 }
+
+func TestPageRuleAction_Values(t *testing.T) {
+	var rule PageRule
+	err := json.Unmarshal([]byte(`{"actions": [
+		{"id": "forwarding_url", "value": {"url": "https://example.com/$1", "status_code": 301}},
+		{"id": "edge_cache_ttl", "value": 7200},
+		{"id": "minify", "value": {"html": "on", "css": "off", "js": "on"}},
+		{"id": "cache_key_fields", "value": {"query_string": {"exclude": "*"}, "header": {"include": ["x-a"]}, "cookie": {}, "host": {"resolved": true}, "user": {"geo": true}}}
+	]}`), &rule)
+	require.NoError(t, err)
+
+	forwarding, err := rule.Actions[0].ForwardingURL()
+	require.NoError(t, err)
+	assert.Equal(t, PageRuleForwardingURL{URL: "https://example.com/$1", StatusCode: 301}, forwarding)
+
+	ttl, err := rule.Actions[1].IntValue()
+	require.NoError(t, err)
+	assert.Equal(t, 7200, ttl)
+
+	_, err = rule.Actions[1].StringValue()
+	assert.Error(t, err)
+
+	minify, err := rule.Actions[2].Minify()
+	require.NoError(t, err)
+	assert.Equal(t, PageRuleMinify{HTML: "on", CSS: "off", JS: "on"}, minify)
+
+	fields, err := rule.Actions[3].CacheKeyFields()
+	require.NoError(t, err)
+	assert.True(t, fields.QueryString.Exclude.All)
+	assert.Equal(t, []string{"x-a"}, fields.Header.Include)
+	assert.True(t, fields.Host.Resolved)
+	assert.True(t, fields.User.Geo)
+
+	action := NewPageRuleAction(PageRuleActionForwardingURL, PageRuleForwardingURL{URL: "https://example.com", StatusCode: 302})
+	b, err := json.Marshal(action)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "forwarding_url", "value": {"url": "https://example.com", "status_code": 302}}`, string(b))
+}
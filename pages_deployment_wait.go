@@ -0,0 +1,169 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	pagesDeploymentStageDeploy = "deploy"
+
+	pagesDeploymentStatusSuccess  = "success"
+	pagesDeploymentStatusFailure  = "failure"
+	pagesDeploymentStatusCanceled = "canceled"
+
+	pagesDeploymentDefaultMinPoll = 2 * time.Second
+	pagesDeploymentDefaultMaxPoll = 15 * time.Second
+)
+
+type WaitForPagesDeploymentParams struct {
+	ProjectName  string
+	DeploymentID string
+
+	// OnLog is called with every new build log line as it appears.
+	OnLog func(PagesDeploymentLogEntry)
+
+	// LogWriter receives every new build log line, newline terminated.
+	LogWriter io.Writer
+
+	// MinPollInterval and MaxPollInterval bound the backoff between polls.
+	// They default to 2 and 15 seconds. The interval is reset to the
+	// minimum whenever new log lines show up.
+	MinPollInterval time.Duration
+	MaxPollInterval time.Duration
+}
+
+// PagesDeploymentFailedError is returned by WaitForPagesDeployment when a
+// deployment stage fails or the deployment is canceled.
+type PagesDeploymentFailedError struct {
+	ProjectName  string
+	DeploymentID string
+	Stage        PagesProjectDeploymentStage
+	Deployment   PagesProjectDeployment
+}
+
+func (e *PagesDeploymentFailedError) Error() string {
+	return fmt.Sprintf("pages deployment %s of project %s stopped at stage %q with status %q",
+		e.DeploymentID, e.ProjectName, e.Stage.Name, e.Stage.Status)
+}
+
+// WaitForPagesDeployment polls a deployment until it either succeeds or
+// fails, passing build log lines to the callback and writer of params as
+// they appear. A failed deployment is reported as a
+// *PagesDeploymentFailedError.
+func (api *API) WaitForPagesDeployment(ctx context.Context, rc *ResourceContainer, params WaitForPagesDeploymentParams) (PagesProjectDeployment, error) {
+	if rc.Identifier == "" {
+		return PagesProjectDeployment{}, ErrMissingAccountID
+	}
+
+	if params.ProjectName == "" {
+		return PagesProjectDeployment{}, ErrMissingProjectName
+	}
+
+	if params.DeploymentID == "" {
+		return PagesProjectDeployment{}, ErrMissingDeploymentID
+	}
+
+	minPoll := params.MinPollInterval
+	if minPoll <= 0 {
+		minPoll = pagesDeploymentDefaultMinPoll
+	}
+	maxPoll := params.MaxPollInterval
+	if maxPoll < minPoll {
+		maxPoll = pagesDeploymentDefaultMaxPoll
+		if maxPoll < minPoll {
+			maxPoll = minPoll
+		}
+	}
+
+	seen := 0
+	interval := minPoll
+	for {
+		deployment, err := api.GetPagesDeploymentInfo(ctx, rc, params.ProjectName, params.DeploymentID)
+		if err != nil {
+			return PagesProjectDeployment{}, err
+		}
+
+		streamed, err := api.streamPagesDeploymentLogs(ctx, rc, params, seen)
+		if err != nil {
+			return deployment, err
+		}
+		seen += streamed
+
+		if failed := pagesDeploymentFailedStage(deployment); failed != nil {
+			return deployment, &PagesDeploymentFailedError{
+				ProjectName:  params.ProjectName,
+				DeploymentID: params.DeploymentID,
+				Stage:        *failed,
+				Deployment:   deployment,
+			}
+		}
+
+		if deployment.IsSkipped || (deployment.LatestStage.Name == pagesDeploymentStageDeploy && deployment.LatestStage.Status == pagesDeploymentStatusSuccess) {
+			return deployment, nil
+		}
+
+		if streamed > 0 {
+			interval = minPoll
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return deployment, fmt.Errorf("waiting for pages deployment %s aborted: %w", params.DeploymentID, ctx.Err())
+		}
+
+		interval *= 2
+		if interval > maxPoll {
+			interval = maxPoll
+		}
+	}
+}
+
+// streamPagesDeploymentLogs passes the log lines after the first seen to the
+// log consumers of params and returns how many were passed on.
+func (api *API) streamPagesDeploymentLogs(ctx context.Context, rc *ResourceContainer, params WaitForPagesDeploymentParams, seen int) (int, error) {
+	if params.OnLog == nil && params.LogWriter == nil {
+		return 0, nil
+	}
+
+	logs, err := api.GetPagesDeploymentLogs(ctx, rc, GetPagesDeploymentLogsParams{
+		ProjectName:  params.ProjectName,
+		DeploymentID: params.DeploymentID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if seen >= len(logs.Data) {
+		return 0, nil
+	}
+
+	for _, entry := range logs.Data[seen:] {
+		if params.OnLog != nil {
+			params.OnLog(entry)
+		}
+		if params.LogWriter != nil {
+			if _, err := io.WriteString(params.LogWriter, entry.Line+"\n"); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(logs.Data) - seen, nil
+}
+
+// pagesDeploymentFailedStage returns the stage that failed or was canceled,
+// if any.
+func pagesDeploymentFailedStage(deployment PagesProjectDeployment) *PagesProjectDeploymentStage {
+	stages := append([]PagesProjectDeploymentStage{deployment.LatestStage}, deployment.Stages...)
+	for i := range stages {
+		switch stages[i].Status {
+		case pagesDeploymentStatusFailure, pagesDeploymentStatusCanceled:
+			return &stages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPagesDeploymentStatusBody(stage, status string) string {
+	return fmt.Sprintf(`{
+		"success": true,
+		"errors": [],
+		"messages": [],
+		"result": {
+			"id": "0012e50b-fa5d-44db-8cb5-1f372785dcbe",
+			"project_name": "test",
+			"latest_stage": {"name": %q, "status": %q},
+			"stages": [
+				{"name": "queued", "status": "success"},
+				{"name": %q, "status": %q}
+			]
+		}
+	}`, stage, status, stage, status)
+}
+
+func testPagesDeploymentLogsBody(lines ...string) string {
+	entries := make([]string, 0, len(lines))
+	for _, l := range lines {
+		entries = append(entries, fmt.Sprintf(`{"ts": "2021-01-01T00:00:00Z", "line": %q}`, l))
+	}
+	return fmt.Sprintf(`{
+		"success": true,
+		"errors": [],
+		"messages": [],
+		"result": {"total": %d, "includes_container_logs": true, "data": [%s]}
+	}`, len(lines), strings.Join(entries, ","))
+}
+
+func TestWaitForPagesDeployment(t *testing.T) {
+	setup()
+	defer teardown()
+
+	statuses := [][2]string{{"build", "active"}, {"build", "active"}, {"deploy", "active"}, {"deploy", "success"}}
+	logs := [][]string{{"Cloning repository"}, {"Cloning repository", "Building"}, {"Cloning repository", "Building", "Deploying"}, {"Cloning repository", "Building", "Deploying", "Success"}}
+	calls := 0
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/deployments/0012e50b-fa5d-44db-8cb5-1f372785dcbe", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testPagesDeploymentStatusBody(statuses[calls][0], statuses[calls][1]))
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/deployments/0012e50b-fa5d-44db-8cb5-1f372785dcbe/history/logs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testPagesDeploymentLogsBody(logs[calls]...))
+		calls++
+	})
+
+	var lines []string
+	var out strings.Builder
+	deployment, err := client.WaitForPagesDeployment(context.Background(), AccountIdentifier(testAccountID), WaitForPagesDeploymentParams{
+		ProjectName:     "test",
+		DeploymentID:    "0012e50b-fa5d-44db-8cb5-1f372785dcbe",
+		OnLog:           func(e PagesDeploymentLogEntry) { lines = append(lines, e.Line) },
+		LogWriter:       &out,
+		MinPollInterval: time.Millisecond,
+		MaxPollInterval: 2 * time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "success", deployment.LatestStage.Status)
+	assert.Equal(t, []string{"Cloning repository", "Building", "Deploying", "Success"}, lines)
+	assert.Equal(t, "Cloning repository\nBuilding\nDeploying\nSuccess\n", out.String())
+}
+
+func TestWaitForPagesDeployment_Failure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/deployments/0012e50b-fa5d-44db-8cb5-1f372785dcbe", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testPagesDeploymentStatusBody("build", "failure"))
+	})
+
+	_, err := client.WaitForPagesDeployment(context.Background(), AccountIdentifier(testAccountID), WaitForPagesDeploymentParams{
+		ProjectName:     "test",
+		DeploymentID:    "0012e50b-fa5d-44db-8cb5-1f372785dcbe",
+		MinPollInterval: time.Millisecond,
+	})
+
+	var failed *PagesDeploymentFailedError
+	require.True(t, errors.As(err, &failed))
+	assert.Equal(t, "build", failed.Stage.Name)
+	assert.Equal(t, "failure", failed.Stage.Status)
+}
+
+func TestWaitForPagesDeployment_ContextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/deployments/0012e50b-fa5d-44db-8cb5-1f372785dcbe", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testPagesDeploymentStatusBody("build", "active"))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForPagesDeployment(ctx, AccountIdentifier(testAccountID), WaitForPagesDeploymentParams{
+		ProjectName:     "test",
+		DeploymentID:    "0012e50b-fa5d-44db-8cb5-1f372785dcbe",
+		MinPollInterval: 10 * time.Millisecond,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
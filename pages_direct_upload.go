@@ -0,0 +1,465 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	pagesMaxAssetSize         = 25 * 1024 * 1024
+	pagesMaxAssetCount        = 20000
+	pagesMaxUploadBatchSize   = 50 * 1024 * 1024
+	pagesMaxUploadBatchCount  = 5000
+	pagesDefaultUploadWorkers = 3
+)
+
+var (
+	ErrMissingPagesDirectory = errors.New("required directory of assets to upload is missing")
+	ErrPagesAssetTooLarge    = errors.New("Pages assets cannot be larger than 25 MiB")
+	ErrTooManyPagesAssets    = errors.New("Pages deployments cannot contain more than 20000 files")
+)
+
+// pagesSpecialFiles are read from the root of the upload directory and sent
+// alongside the deployment rather than uploaded as assets.
+var pagesSpecialFiles = []string{"_headers", "_redirects", "_routes.json", "_worker.js"}
+
+// pagesIgnoredNames are never uploaded, at any depth.
+var pagesIgnoredNames = []string{".DS_Store", ".git"}
+
+// pagesIgnoredRootPaths are not uploaded from the root of the upload
+// directory only; nested directories with the same names are assets.
+var pagesIgnoredRootPaths = []string{"node_modules", "functions"}
+
+type CreatePagesDirectUploadDeploymentParams struct {
+	ProjectName string
+
+	// Directory is the local build output to deploy.
+	Directory string
+
+	// Branch decides whether this is a production or preview deployment.
+	// Defaults to the production branch of the project.
+	Branch        string
+	CommitHash    string
+	CommitMessage string
+	CommitDirty   bool
+
+	// FunctionsBundle and FunctionsRoutingConfig are the compiled Pages
+	// Functions bundle and its routing configuration.
+	FunctionsBundle        []byte
+	FunctionsRoutingConfig []byte
+
+	// Concurrency is the number of asset batches uploaded at once. Defaults
+	// to 3.
+	Concurrency int
+}
+
+// PagesAsset is a file of a direct upload deployment.
+type PagesAsset struct {
+	// Path is the URL path the file is served from, e.g. "/css/app.css".
+	Path        string
+	Hash        string
+	ContentType string
+	Size        int64
+
+	filePath string
+}
+
+type pagesUploadTokenResponse struct {
+	Response
+	Result struct {
+		JWT string `json:"jwt"`
+	} `json:"result"`
+}
+
+type pagesCheckMissingResponse struct {
+	Response
+	Result []string `json:"result"`
+}
+
+type pagesAssetUpload struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Metadata struct {
+		ContentType string `json:"contentType"`
+	} `json:"metadata"`
+	Base64 bool `json:"base64"`
+}
+
+// pagesAssetHash derives the content key of an asset. Any stable hash of the
+// content works as the key, the extension is included so that the same bytes
+// served with different content types are stored separately.
+func pagesAssetHash(content []byte, filename string) string {
+	sum := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(content) + strings.TrimPrefix(filepath.Ext(filename), ".")))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// CollectPagesAssets walks a build output directory and returns the assets
+// a direct upload deployment would contain. Configuration files such as
+// _headers and _redirects at the root are skipped.
+func CollectPagesAssets(dir string) ([]PagesAsset, error) {
+	var assets []PagesAsset
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if contains(pagesIgnoredNames, d.Name()) || contains(pagesIgnoredRootPaths, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if contains(pagesSpecialFiles, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > pagesMaxAssetSize {
+			return fmt.Errorf("%s: %w", rel, ErrPagesAssetTooLarge)
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(rel))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		assets = append(assets, PagesAsset{
+			Path:        "/" + rel,
+			Hash:        pagesAssetHash(content, rel),
+			ContentType: contentType,
+			Size:        info.Size(),
+			filePath:    p,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(assets) > pagesMaxAssetCount {
+		return nil, ErrTooManyPagesAssets
+	}
+
+	return assets, nil
+}
+
+// CreatePagesDirectUploadDeployment deploys a local directory to a Pages
+// project without a connected git repository. Only assets whose content
+// isn't already stored by Pages are uploaded.
+//
+// API reference: https://developers.cloudflare.com/pages/how-to/use-direct-upload-with-continuous-integration/
+func (api *API) CreatePagesDirectUploadDeployment(ctx context.Context, rc *ResourceContainer, params CreatePagesDirectUploadDeploymentParams) (PagesProjectDeployment, error) {
+	if rc.Identifier == "" {
+		return PagesProjectDeployment{}, ErrMissingAccountID
+	}
+
+	if params.ProjectName == "" {
+		return PagesProjectDeployment{}, ErrMissingProjectName
+	}
+
+	if params.Directory == "" {
+		return PagesProjectDeployment{}, ErrMissingPagesDirectory
+	}
+
+	assets, err := CollectPagesAssets(params.Directory)
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	jwt, err := api.getPagesUploadToken(ctx, rc, params.ProjectName)
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	hashes := make([]string, 0, len(assets))
+	seen := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		if !seen[a.Hash] {
+			seen[a.Hash] = true
+			hashes = append(hashes, a.Hash)
+		}
+	}
+
+	missing, err := api.checkMissingPagesAssets(ctx, jwt, hashes)
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	err = api.uploadPagesAssets(ctx, jwt, pagesMissingAssets(assets, missing), params.Concurrency)
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	_, err = api.makeRequestWithAuthTypeAndHeaders(ctx, http.MethodPost, "/pages/assets/upsert-hashes", map[string][]string{"hashes": hashes}, 0, pagesJWTHeaders(jwt))
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	contentType, body, err := pagesDeploymentForm(params, assets)
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/pages/projects/%s/deployments", rc.Identifier, params.ProjectName)
+	res, err := api.makeRequestContextWithHeaders(ctx, http.MethodPost, uri, body, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return PagesProjectDeployment{}, err
+	}
+
+	var r pagesDeploymentResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return PagesProjectDeployment{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+func pagesJWTHeaders(jwt string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + jwt}}
+}
+
+// getPagesUploadToken returns the short-lived token used to authenticate
+// asset uploads for a project.
+func (api *API) getPagesUploadToken(ctx context.Context, rc *ResourceContainer, projectName string) (string, error) {
+	uri := fmt.Sprintf("/accounts/%s/pages/projects/%s/upload-token", rc.Identifier, projectName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var r pagesUploadTokenResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result.JWT, nil
+}
+
+func (api *API) checkMissingPagesAssets(ctx context.Context, jwt string, hashes []string) ([]string, error) {
+	res, err := api.makeRequestWithAuthTypeAndHeaders(ctx, http.MethodPost, "/pages/assets/check-missing", map[string][]string{"hashes": hashes}, 0, pagesJWTHeaders(jwt))
+	if err != nil {
+		return nil, err
+	}
+
+	var r pagesCheckMissingResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// pagesMissingAssets returns one asset per missing hash, largest first so
+// batches are packed evenly.
+func pagesMissingAssets(assets []PagesAsset, missing []string) []PagesAsset {
+	want := make(map[string]bool, len(missing))
+	for _, h := range missing {
+		want[h] = true
+	}
+
+	var out []PagesAsset
+	for _, a := range assets {
+		if want[a.Hash] {
+			out = append(out, a)
+			delete(want, a.Hash)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out
+}
+
+// uploadPagesAssets uploads assets in batches bounded by size and count,
+// running at most concurrency uploads at once.
+func (api *API) uploadPagesAssets(ctx context.Context, jwt string, assets []PagesAsset, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = pagesDefaultUploadWorkers
+	}
+
+	var batches [][]PagesAsset
+	var current []PagesAsset
+	var currentSize int64
+	for _, a := range assets {
+		// Assets are sent base64 encoded, which grows them by a third.
+		encodedSize := int64(base64.StdEncoding.EncodedLen(int(a.Size)))
+		if len(current) > 0 && (currentSize+encodedSize > pagesMaxUploadBatchSize || len(current) >= pagesMaxUploadBatchCount) {
+			batches = append(batches, current)
+			current, currentSize = nil, 0
+		}
+		current = append(current, a)
+		currentSize += encodedSize
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, batch := range batches {
+		batch := batch
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := api.uploadPagesAssetBatch(ctx, jwt, batch)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func (api *API) uploadPagesAssetBatch(ctx context.Context, jwt string, batch []PagesAsset) error {
+	payload := make([]pagesAssetUpload, 0, len(batch))
+	for _, a := range batch {
+		content, err := os.ReadFile(a.filePath)
+		if err != nil {
+			return err
+		}
+
+		upload := pagesAssetUpload{
+			Key:    a.Hash,
+			Value:  base64.StdEncoding.EncodeToString(content),
+			Base64: true,
+		}
+		upload.Metadata.ContentType = a.ContentType
+		payload = append(payload, upload)
+	}
+
+	_, err := api.makeRequestWithAuthTypeAndHeaders(ctx, http.MethodPost, "/pages/assets/upload", payload, 0, pagesJWTHeaders(jwt))
+	if err != nil {
+		return fmt.Errorf("failed to upload Pages assets: %w", err)
+	}
+	return nil
+}
+
+// pagesDeploymentForm builds the multipart body creating the deployment from
+// the asset manifest and the optional configuration files.
+func pagesDeploymentForm(params CreatePagesDirectUploadDeploymentParams, assets []PagesAsset) (string, []byte, error) {
+	manifest := make(map[string]string, len(assets))
+	for _, a := range assets {
+		manifest[a.Path] = a.Hash
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	mpw := multipart.NewWriter(buf)
+
+	fields := [][2]string{
+		{"manifest", string(manifestJSON)},
+		{"branch", params.Branch},
+		{"commit_hash", params.CommitHash},
+		{"commit_message", params.CommitMessage},
+	}
+	if params.CommitDirty {
+		fields = append(fields, [2]string{"commit_dirty", strconv.FormatBool(params.CommitDirty)})
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := mpw.WriteField(f[0], f[1]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	files := map[string][]byte{
+		"_worker.bundle":                         params.FunctionsBundle,
+		"functions-filepath-routing-config.json": params.FunctionsRoutingConfig,
+	}
+	for _, name := range pagesSpecialFiles {
+		content, err := os.ReadFile(filepath.Join(params.Directory, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		files[name] = content
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if files[name] == nil {
+			continue
+		}
+		w, err := mpw.CreateFormFile(name, name)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := mpw.Close(); err != nil {
+		return "", nil, err
+	}
+
+	return mpw.FormDataContentType(), buf.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePagesTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestCollectPagesAssets(t *testing.T) {
+	dir := writePagesTestFiles(t, map[string]string{
+		"index.html":                "<h1>hello</h1>",
+		"css/app.css":               "body {}",
+		"_headers":                  "/*\n  X-Frame-Options: DENY",
+		"_redirects":                "/old /new 301",
+		"node_modules/pkg/index.js": "module.exports = {}",
+		"functions/api.js":          "export function onRequest() {}",
+		".DS_Store":                 "",
+		"assets/functions/x.js":     "console.log(1)",
+		"assets/node_modules/y.js":  "console.log(2)",
+		"assets/.DS_Store":          "",
+	})
+
+	assets, err := CollectPagesAssets(dir)
+	require.NoError(t, err)
+
+	paths := make(map[string]PagesAsset)
+	for _, a := range assets {
+		paths[a.Path] = a
+	}
+	assert.Len(t, paths, 4)
+	assert.Contains(t, paths, "/assets/functions/x.js")
+	assert.Contains(t, paths, "/assets/node_modules/y.js")
+	assert.Equal(t, "text/css; charset=utf-8", paths["/css/app.css"].ContentType)
+	assert.Equal(t, pagesAssetHash([]byte("<h1>hello</h1>"), "index.html"), paths["/index.html"].Hash)
+	assert.Len(t, paths["/index.html"].Hash, 32)
+}
+
+func TestCreatePagesDirectUploadDeployment(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dir := writePagesTestFiles(t, map[string]string{
+		"index.html":  "<h1>hello</h1>",
+		"css/app.css": "body {}",
+		"_headers":    "/*\n  X-Frame-Options: DENY",
+	})
+	missingHash := pagesAssetHash([]byte("body {}"), "app.css")
+
+	var mu sync.Mutex
+	var uploaded []string
+	var upserted bool
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/upload-token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"jwt": "upload-jwt"}}`)
+	})
+
+	mux.HandleFunc("/pages/assets/check-missing", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "Bearer upload-jwt", r.Header.Get("Authorization"))
+
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Len(t, body["hashes"], 2)
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [%q]}`, missingHash)
+	})
+
+	mux.HandleFunc("/pages/assets/upload", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		assert.Equal(t, "Bearer upload-jwt", r.Header.Get("Authorization"))
+
+		var body []pagesAssetUpload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		for _, a := range body {
+			uploaded = append(uploaded, a.Key)
+			assert.True(t, a.Base64)
+			assert.Equal(t, "text/css; charset=utf-8", a.Metadata.ContentType)
+		}
+		mu.Unlock()
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+	})
+
+	mux.HandleFunc("/pages/assets/upsert-hashes", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		upserted = true
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": true}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/pages/projects/test/deployments", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		var manifest map[string]string
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("manifest")), &manifest))
+		assert.Equal(t, missingHash, manifest["/css/app.css"])
+		assert.Contains(t, manifest, "/index.html")
+		assert.Equal(t, "main", r.FormValue("branch"))
+
+		f, _, err := r.FormFile("_headers")
+		require.NoError(t, err)
+		headers, _ := io.ReadAll(f)
+		assert.Equal(t, "/*\n  X-Frame-Options: DENY", string(headers))
+
+		_, _, err = r.FormFile("_redirects")
+		assert.ErrorIs(t, err, http.ErrMissingFile)
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s
+		}`, testPagesDeploymentResponse)
+	})
+
+	actual, err := client.CreatePagesDirectUploadDeployment(context.Background(), AccountIdentifier(testAccountID), CreatePagesDirectUploadDeploymentParams{
+		ProjectName: "test",
+		Directory:   dir,
+		Branch:      "main",
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, *expectedPagesDeployment, actual)
+		assert.Equal(t, []string{missingHash}, uploaded)
+		assert.True(t, upserted)
+	}
+}
+
+func TestCreatePagesDirectUploadDeployment_MissingDirectory(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.CreatePagesDirectUploadDeployment(context.Background(), AccountIdentifier(testAccountID), CreatePagesDirectUploadDeploymentParams{
+		ProjectName: "test",
+	})
+	assert.ErrorIs(t, err, ErrMissingPagesDirectory)
+}
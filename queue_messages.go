@@ -0,0 +1,329 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingQueueID       = errors.New("required queue ID is missing")
+	ErrMissingQueueMessages = errors.New("at least one queue message is required")
+	ErrMissingQueueHandler  = errors.New("a queue message handler is required")
+	ErrQueueHandlerPanic    = errors.New("queue message handler panicked")
+)
+
+// queueAckTimeout bounds how long ConsumeQueueMessages waits to settle a
+// batch.
+const queueAckTimeout = 30 * time.Second
+
+// QueueMessageContentType describes how the body of a queue message is
+// encoded.
+type QueueMessageContentType string
+
+const (
+	QueueMessageContentTypeText  QueueMessageContentType = "text"
+	QueueMessageContentTypeJSON  QueueMessageContentType = "json"
+	QueueMessageContentTypeBytes QueueMessageContentType = "bytes"
+	QueueMessageContentTypeV8    QueueMessageContentType = "v8"
+)
+
+// QueueMessageSend is a single message to be sent to a queue.
+type QueueMessageSend struct {
+	// Body is marshalled according to ContentType. A []byte body is base64
+	// encoded, which is what the "bytes" content type expects.
+	Body         interface{}             `json:"body"`
+	ContentType  QueueMessageContentType `json:"content_type,omitempty"`
+	DelaySeconds int                     `json:"delay_seconds,omitempty"`
+}
+
+type SendQueueMessagesParams struct {
+	QueueID  string             `json:"-"`
+	Messages []QueueMessageSend `json:"messages"`
+
+	// DelaySeconds applies to every message in the batch that doesn't set
+	// its own delay.
+	DelaySeconds int `json:"delay_seconds,omitempty"`
+}
+
+// QueueMessage is a message leased by a pull consumer.
+type QueueMessage struct {
+	ID string `json:"id"`
+	// Body is the encoded message body. JSON messages are returned as the
+	// serialized JSON document and bytes messages are base64 encoded.
+	Body        string            `json:"body"`
+	Attempts    int               `json:"attempts"`
+	TimestampMs int64             `json:"timestamp_ms"`
+	LeaseID     string            `json:"lease_id"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type PullQueueMessagesParams struct {
+	QueueID string `json:"-"`
+
+	// BatchSize is the maximum number of messages to lease. The API defaults
+	// to 5 when unset.
+	BatchSize int `json:"batch_size,omitempty"`
+
+	// VisibilityTimeoutMs is how long the leased messages are hidden from
+	// other consumers before being redelivered.
+	VisibilityTimeoutMs int `json:"visibility_timeout_ms,omitempty"`
+}
+
+type PullQueueMessagesResponse struct {
+	Response
+	Result struct {
+		Messages            []QueueMessage `json:"messages"`
+		MessageBacklogCount int            `json:"message_backlog_count"`
+	} `json:"result"`
+}
+
+// QueueMessageAck acknowledges a leased message so it is removed from the
+// queue.
+type QueueMessageAck struct {
+	LeaseID string `json:"lease_id"`
+}
+
+// QueueMessageRetry returns a leased message to the queue, optionally
+// delaying its redelivery.
+type QueueMessageRetry struct {
+	LeaseID      string `json:"lease_id"`
+	DelaySeconds int    `json:"delay_seconds,omitempty"`
+}
+
+type AckQueueMessagesParams struct {
+	QueueID string              `json:"-"`
+	Acks    []QueueMessageAck   `json:"acks"`
+	Retries []QueueMessageRetry `json:"retries"`
+}
+
+type AckQueueMessagesResult struct {
+	AckCount   int      `json:"ackCount"`
+	RetryCount int      `json:"retryCount"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+type AckQueueMessagesResponse struct {
+	Response
+	Result AckQueueMessagesResult `json:"result"`
+}
+
+// QueueMessageHandler processes a single pulled message. Returning nil
+// acknowledges the message, returning an error or panicking retries it.
+type QueueMessageHandler func(ctx context.Context, msg QueueMessage) error
+
+type ConsumeQueueMessagesParams struct {
+	QueueID             string
+	BatchSize           int
+	VisibilityTimeoutMs int
+
+	// Concurrency is the maximum number of messages handled at once.
+	// Defaults to 1.
+	Concurrency int
+
+	// PollInterval is how long to wait before pulling again after an empty
+	// batch. Defaults to 1 second.
+	PollInterval time.Duration
+
+	// RetryDelaySeconds delays redelivery of messages the handler failed.
+	RetryDelaySeconds int
+}
+
+// SendQueueMessages sends a batch of messages to a queue.
+//
+// API reference: https://developers.cloudflare.com/api/operations/queue-v2-messages-send-batch
+func (api *API) SendQueueMessages(ctx context.Context, rc *ResourceContainer, params SendQueueMessagesParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.QueueID == "" {
+		return ErrMissingQueueID
+	}
+
+	if len(params.Messages) == 0 {
+		return ErrMissingQueueMessages
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/queues/%s/messages/batch", rc.Identifier, params.QueueID)
+	_, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errMakeRequestError, err)
+	}
+
+	return nil
+}
+
+// PullQueueMessages leases a batch of messages from a queue configured with
+// an HTTP pull consumer. Leased messages must be acknowledged or retried with
+// AckQueueMessages before the visibility timeout expires.
+//
+// API reference: https://developers.cloudflare.com/api/operations/queue-v2-messages-pull
+func (api *API) PullQueueMessages(ctx context.Context, rc *ResourceContainer, params PullQueueMessagesParams) ([]QueueMessage, error) {
+	if rc.Identifier == "" {
+		return []QueueMessage{}, ErrMissingAccountID
+	}
+
+	if params.QueueID == "" {
+		return []QueueMessage{}, ErrMissingQueueID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/queues/%s/messages/pull", rc.Identifier, params.QueueID)
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return []QueueMessage{}, fmt.Errorf("%s: %w", errMakeRequestError, err)
+	}
+
+	var r PullQueueMessagesResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []QueueMessage{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result.Messages, nil
+}
+
+// AckQueueMessages acknowledges and retries messages previously leased with
+// PullQueueMessages.
+//
+// API reference: https://developers.cloudflare.com/api/operations/queue-v2-messages-ack
+func (api *API) AckQueueMessages(ctx context.Context, rc *ResourceContainer, params AckQueueMessagesParams) (AckQueueMessagesResult, error) {
+	if rc.Identifier == "" {
+		return AckQueueMessagesResult{}, ErrMissingAccountID
+	}
+
+	if params.QueueID == "" {
+		return AckQueueMessagesResult{}, ErrMissingQueueID
+	}
+
+	if params.Acks == nil {
+		params.Acks = []QueueMessageAck{}
+	}
+	if params.Retries == nil {
+		params.Retries = []QueueMessageRetry{}
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/queues/%s/messages/ack", rc.Identifier, params.QueueID)
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return AckQueueMessagesResult{}, fmt.Errorf("%s: %w", errMakeRequestError, err)
+	}
+
+	var r AckQueueMessagesResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return AckQueueMessagesResult{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// ConsumeQueueMessages pulls messages from a queue and passes each one to
+// handler, running at most params.Concurrency handlers at once. After every
+// batch, successfully handled messages are acknowledged and failed ones are
+// retried. It runs until ctx is cancelled, returning the context error, or
+// until pulling or acknowledging fails. Messages of a batch which can't be
+// settled before ctx is cancelled are redelivered once their visibility
+// timeout expires.
+func (api *API) ConsumeQueueMessages(ctx context.Context, rc *ResourceContainer, params ConsumeQueueMessagesParams, handler QueueMessageHandler) error {
+	if handler == nil {
+		return ErrMissingQueueHandler
+	}
+
+	concurrency := params.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	pollInterval := params.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		messages, err := api.PullQueueMessages(ctx, rc, PullQueueMessagesParams{
+			QueueID:             params.QueueID,
+			BatchSize:           params.BatchSize,
+			VisibilityTimeoutMs: params.VisibilityTimeoutMs,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		if len(messages) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		ack := AckQueueMessagesParams{QueueID: params.QueueID}
+
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, concurrency)
+		)
+		for _, msg := range messages {
+			msg := msg
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				handlerErr := handleQueueMessage(ctx, handler, msg)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if handlerErr != nil {
+					ack.Retries = append(ack.Retries, QueueMessageRetry{
+						LeaseID:      msg.LeaseID,
+						DelaySeconds: params.RetryDelaySeconds,
+					})
+					return
+				}
+				ack.Acks = append(ack.Acks, QueueMessageAck{LeaseID: msg.LeaseID})
+			}()
+		}
+		wg.Wait()
+
+		ackCtx, cancelAck := context.WithTimeout(ctx, queueAckTimeout)
+		_, err = api.AckQueueMessages(ackCtx, rc, ack)
+		cancelAck()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+// handleQueueMessage calls handler, turning a panic into an error so that
+// the message is retried instead of the consumer crashing.
+func handleQueueMessage(ctx context.Context, handler QueueMessageHandler, msg QueueMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrQueueHandlerPanic, r)
+		}
+	}()
+
+	return handler(ctx, msg)
+}
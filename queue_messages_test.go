@@ -0,0 +1,223 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQueueMessagesPullResponse = `{
+  "success": true,
+  "errors": [],
+  "messages": [],
+  "result": {
+    "messages": [
+      {
+        "id": "b01b5594f784d0165c2985833f5660dd",
+        "body": "{\"greeting\":\"hello\"}",
+        "attempts": 1,
+        "timestamp_ms": 1689615013586,
+        "lease_id": "lease-1",
+        "metadata": {"CF-Content-Type": "json"}
+      },
+      {
+        "id": "a8e3b0b2e2a5e35d4e0f33f7a1e8e8c9",
+        "body": "fail me",
+        "attempts": 2,
+        "timestamp_ms": 1689615013587,
+        "lease_id": "lease-2",
+        "metadata": {"CF-Content-Type": "text"}
+      }
+    ],
+    "message_backlog_count": 2
+  }
+}`
+
+func TestQueue_SendQueueMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/batch", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"messages": [
+				{"body": {"greeting": "hello"}, "content_type": "json"},
+				{"body": "aGVsbG8=", "content_type": "bytes", "delay_seconds": 10}
+			],
+			"delay_seconds": 5
+		}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+	})
+
+	err := client.SendQueueMessages(context.Background(), AccountIdentifier(testAccountID), SendQueueMessagesParams{})
+	assert.ErrorIs(t, err, ErrMissingQueueID)
+
+	err = client.SendQueueMessages(context.Background(), AccountIdentifier(testAccountID), SendQueueMessagesParams{QueueID: testQueueID})
+	assert.ErrorIs(t, err, ErrMissingQueueMessages)
+
+	err = client.SendQueueMessages(context.Background(), AccountIdentifier(testAccountID), SendQueueMessagesParams{
+		QueueID: testQueueID,
+		Messages: []QueueMessageSend{
+			{Body: map[string]string{"greeting": "hello"}, ContentType: QueueMessageContentTypeJSON},
+			{Body: []byte("hello"), ContentType: QueueMessageContentTypeBytes, DelaySeconds: 10},
+		},
+		DelaySeconds: 5,
+	})
+	assert.NoError(t, err)
+}
+
+func TestQueue_PullQueueMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/pull", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"batch_size": 10, "visibility_timeout_ms": 6000}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testQueueMessagesPullResponse)
+	})
+
+	messages, err := client.PullQueueMessages(context.Background(), AccountIdentifier(testAccountID), PullQueueMessagesParams{
+		QueueID:             testQueueID,
+		BatchSize:           10,
+		VisibilityTimeoutMs: 6000,
+	})
+	if assert.NoError(t, err) {
+		assert.Len(t, messages, 2)
+		assert.Equal(t, QueueMessage{
+			ID:          "b01b5594f784d0165c2985833f5660dd",
+			Body:        `{"greeting":"hello"}`,
+			Attempts:    1,
+			TimestampMs: 1689615013586,
+			LeaseID:     "lease-1",
+			Metadata:    map[string]string{"CF-Content-Type": "json"},
+		}, messages[0])
+	}
+}
+
+func TestQueue_AckQueueMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/ack", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"acks": [{"lease_id": "lease-1"}], "retries": [{"lease_id": "lease-2", "delay_seconds": 30}]}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"ackCount": 1, "retryCount": 1, "warnings": []}}`)
+	})
+
+	res, err := client.AckQueueMessages(context.Background(), AccountIdentifier(testAccountID), AckQueueMessagesParams{
+		QueueID: testQueueID,
+		Acks:    []QueueMessageAck{{LeaseID: "lease-1"}},
+		Retries: []QueueMessageRetry{{LeaseID: "lease-2", DelaySeconds: 30}},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, AckQueueMessagesResult{AckCount: 1, RetryCount: 1, Warnings: []string{}}, res)
+	}
+}
+
+func TestQueue_ConsumeQueueMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/pull", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testQueueMessagesPullResponse)
+	})
+
+	var acked AckQueueMessagesParams
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/ack", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &acked))
+
+		// Stop consuming after the first batch has been settled.
+		cancel()
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"ackCount": 1, "retryCount": 1}}`)
+	})
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	err := client.ConsumeQueueMessages(ctx, AccountIdentifier(testAccountID), ConsumeQueueMessagesParams{
+		QueueID:           testQueueID,
+		Concurrency:       2,
+		RetryDelaySeconds: 15,
+	}, func(ctx context.Context, msg QueueMessage) error {
+		mu.Lock()
+		handled = append(handled, msg.ID)
+		mu.Unlock()
+
+		if msg.Body == "fail me" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ElementsMatch(t, []string{"b01b5594f784d0165c2985833f5660dd", "a8e3b0b2e2a5e35d4e0f33f7a1e8e8c9"}, handled)
+	assert.Equal(t, []QueueMessageAck{{LeaseID: "lease-1"}}, acked.Acks)
+	assert.Equal(t, []QueueMessageRetry{{LeaseID: "lease-2", DelaySeconds: 15}}, acked.Retries)
+}
+
+func TestQueue_ConsumeQueueMessages_HandlerPanic(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/pull", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testQueueMessagesPullResponse)
+	})
+
+	var acked AckQueueMessagesParams
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/queues/%s/messages/ack", testAccountID, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &acked))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"ackCount": 1, "retryCount": 1}}`)
+		cancel()
+	})
+
+	err := client.ConsumeQueueMessages(ctx, AccountIdentifier(testAccountID), ConsumeQueueMessagesParams{
+		QueueID:     testQueueID,
+		Concurrency: 2,
+	}, func(ctx context.Context, msg QueueMessage) error {
+		if msg.Body == "fail me" {
+			panic("bad message")
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []QueueMessageAck{{LeaseID: "lease-1"}}, acked.Acks)
+	assert.Equal(t, []QueueMessageRetry{{LeaseID: "lease-2"}}, acked.Retries)
+}
@@ -0,0 +1,152 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingR2CORSRules         = errors.New("at least one CORS rule is required")
+	ErrMissingR2CORSAllowed       = errors.New("CORS rules require at least one allowed origin and method")
+	ErrInvalidR2CORSMaxAge        = errors.New("CORS max age cannot be negative")
+	ErrInvalidR2CORSAllowedMethod = errors.New("CORS allowed methods must be one of GET, PUT, POST, DELETE or HEAD")
+)
+
+var r2CORSAllowedMethods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodHead,
+}
+
+// R2BucketCORSRule is a single CORS rule applied to requests made to a
+// bucket through its public or custom domains.
+//
+// API reference: https://developers.cloudflare.com/r2/buckets/cors/
+type R2BucketCORSRule struct {
+	ID            string                  `json:"id,omitempty"`
+	Allowed       R2BucketCORSRuleAllowed `json:"allowed"`
+	ExposeHeaders []string                `json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds int                     `json:"maxAgeSeconds,omitempty"`
+}
+
+// R2BucketCORSRuleAllowed lists the origins, methods and request headers a
+// CORS rule permits.
+type R2BucketCORSRuleAllowed struct {
+	Origins []string `json:"origins"`
+	Methods []string `json:"methods"`
+	Headers []string `json:"headers,omitempty"`
+}
+
+// R2BucketCORSPolicy is the set of CORS rules of a bucket.
+type R2BucketCORSPolicy struct {
+	Rules []R2BucketCORSRule `json:"rules"`
+}
+
+type R2BucketCORSPolicyResponse struct {
+	Result R2BucketCORSPolicy `json:"result"`
+	Response
+}
+
+type SetR2BucketCORSPolicyParams struct {
+	BucketName string             `json:"-"`
+	Rules      []R2BucketCORSRule `json:"rules"`
+}
+
+// Validate checks that the rule can be accepted by the API.
+func (r R2BucketCORSRule) Validate() error {
+	if len(r.Allowed.Origins) == 0 || len(r.Allowed.Methods) == 0 {
+		return ErrMissingR2CORSAllowed
+	}
+
+	for _, m := range r.Allowed.Methods {
+		if !contains(r2CORSAllowedMethods, strings.ToUpper(m)) {
+			return fmt.Errorf("%w: %q", ErrInvalidR2CORSAllowedMethod, m)
+		}
+	}
+
+	if r.MaxAgeSeconds < 0 {
+		return ErrInvalidR2CORSMaxAge
+	}
+
+	return nil
+}
+
+// GetR2BucketCORSPolicy returns the CORS rules of a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-get-bucket-cors-policy
+func (api *API) GetR2BucketCORSPolicy(ctx context.Context, rc *ResourceContainer, bucketName string) (R2BucketCORSPolicy, error) {
+	if rc.Identifier == "" {
+		return R2BucketCORSPolicy{}, ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return R2BucketCORSPolicy{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", rc.Identifier, bucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return R2BucketCORSPolicy{}, err
+	}
+
+	var r R2BucketCORSPolicyResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketCORSPolicy{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// SetR2BucketCORSPolicy replaces the CORS rules of a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-put-bucket-cors-policy
+func (api *API) SetR2BucketCORSPolicy(ctx context.Context, rc *ResourceContainer, params SetR2BucketCORSPolicyParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	if len(params.Rules) == 0 {
+		return ErrMissingR2CORSRules
+	}
+
+	for _, rule := range params.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", rc.Identifier, params.BucketName)
+	_, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+
+	return err
+}
+
+// DeleteR2BucketCORSPolicy removes every CORS rule from a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-delete-bucket-cors-policy
+func (api *API) DeleteR2BucketCORSPolicy(ctx context.Context, rc *ResourceContainer, bucketName string) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", rc.Identifier, bucketName)
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+
+	return err
+}
@@ -0,0 +1,105 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestR2_GetBucketCORSPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+  "success": true,
+  "errors": [],
+  "messages": [],
+  "result": {
+    "rules": [
+      {
+        "id": "allow-app",
+        "allowed": {
+          "origins": ["https://example.com"],
+          "methods": ["GET", "HEAD"],
+          "headers": ["x-requested-by"]
+        },
+        "exposeHeaders": ["Content-Encoding"],
+        "maxAgeSeconds": 3600
+      }
+    ]
+  }
+}`)
+	})
+
+	want := R2BucketCORSPolicy{
+		Rules: []R2BucketCORSRule{
+			{
+				ID: "allow-app",
+				Allowed: R2BucketCORSRuleAllowed{
+					Origins: []string{"https://example.com"},
+					Methods: []string{"GET", "HEAD"},
+					Headers: []string{"x-requested-by"},
+				},
+				ExposeHeaders: []string{"Content-Encoding"},
+				MaxAgeSeconds: 3600,
+			},
+		},
+	}
+
+	actual, err := client.GetR2BucketCORSPolicy(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestR2_SetBucketCORSPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rules": [{"allowed": {"origins": ["*"], "methods": ["GET"]}, "maxAgeSeconds": 60}]}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+	})
+
+	err := client.SetR2BucketCORSPolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketCORSPolicyParams{BucketName: testBucketName})
+	assert.ErrorIs(t, err, ErrMissingR2CORSRules)
+
+	err = client.SetR2BucketCORSPolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketCORSPolicyParams{
+		BucketName: testBucketName,
+		Rules:      []R2BucketCORSRule{{Allowed: R2BucketCORSRuleAllowed{Origins: []string{"*"}, Methods: []string{"PATCH"}}}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidR2CORSAllowedMethod)
+
+	err = client.SetR2BucketCORSPolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketCORSPolicyParams{
+		BucketName: testBucketName,
+		Rules:      []R2BucketCORSRule{{Allowed: R2BucketCORSRuleAllowed{Origins: []string{"*"}, Methods: []string{"GET"}}, MaxAgeSeconds: 60}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestR2_DeleteBucketCORSPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/cors", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+	})
+
+	err := client.DeleteR2BucketCORSPolicy(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	assert.NoError(t, err)
+}
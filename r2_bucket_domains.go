@@ -0,0 +1,275 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingR2CustomDomain = errors.New("required custom domain is missing")
+	ErrInvalidR2MinTLS       = errors.New("minimum TLS version must be one of 1.0, 1.1, 1.2 or 1.3")
+)
+
+var r2MinTLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+// R2BucketCustomDomain is a domain on a Cloudflare zone that serves the
+// contents of a bucket publicly.
+//
+// API reference: https://developers.cloudflare.com/r2/buckets/public-buckets/#custom-domains
+type R2BucketCustomDomain struct {
+	Domain   string                     `json:"domain"`
+	Enabled  bool                       `json:"enabled"`
+	Status   R2BucketCustomDomainStatus `json:"status"`
+	MinTLS   string                     `json:"minTLS,omitempty"`
+	ZoneID   string                     `json:"zoneId,omitempty"`
+	ZoneName string                     `json:"zoneName,omitempty"`
+}
+
+// R2BucketCustomDomainStatus is the ownership and certificate status of a
+// custom domain.
+type R2BucketCustomDomainStatus struct {
+	Ownership string `json:"ownership"`
+	SSL       string `json:"ssl"`
+}
+
+// R2BucketManagedDomain is the r2.dev subdomain of a bucket.
+type R2BucketManagedDomain struct {
+	BucketID string `json:"bucketId"`
+	Domain   string `json:"domain"`
+	Enabled  bool   `json:"enabled"`
+}
+
+type R2BucketCustomDomainsResponse struct {
+	Result struct {
+		Domains []R2BucketCustomDomain `json:"domains"`
+	} `json:"result"`
+	Response
+}
+
+type R2BucketCustomDomainResponse struct {
+	Result R2BucketCustomDomain `json:"result"`
+	Response
+}
+
+type R2BucketManagedDomainResponse struct {
+	Result R2BucketManagedDomain `json:"result"`
+	Response
+}
+
+type AddR2BucketCustomDomainParams struct {
+	BucketName string `json:"-"`
+	Domain     string `json:"domain"`
+	ZoneID     string `json:"zoneId"`
+	Enabled    bool   `json:"enabled"`
+	MinTLS     string `json:"minTLS,omitempty"`
+}
+
+type UpdateR2BucketCustomDomainParams struct {
+	BucketName string `json:"-"`
+	Domain     string `json:"-"`
+	Enabled    *bool  `json:"enabled,omitempty"`
+	MinTLS     string `json:"minTLS,omitempty"`
+}
+
+type DeleteR2BucketCustomDomainParams struct {
+	BucketName string
+	Domain     string
+}
+
+type SetR2BucketManagedDomainParams struct {
+	BucketName string `json:"-"`
+	Enabled    bool   `json:"enabled"`
+}
+
+func validateR2MinTLS(v string) error {
+	if v != "" && !contains(r2MinTLSVersions, v) {
+		return ErrInvalidR2MinTLS
+	}
+	return nil
+}
+
+// ListR2BucketCustomDomains returns the custom domains connected to a
+// bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-list-custom-domains
+func (api *API) ListR2BucketCustomDomains(ctx context.Context, rc *ResourceContainer, bucketName string) ([]R2BucketCustomDomain, error) {
+	if rc.Identifier == "" {
+		return []R2BucketCustomDomain{}, ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return []R2BucketCustomDomain{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom", rc.Identifier, bucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []R2BucketCustomDomain{}, err
+	}
+
+	var r R2BucketCustomDomainsResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return []R2BucketCustomDomain{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result.Domains, nil
+}
+
+// AddR2BucketCustomDomain connects a domain on a zone in the same account
+// to a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-add-custom-domain
+func (api *API) AddR2BucketCustomDomain(ctx context.Context, rc *ResourceContainer, params AddR2BucketCustomDomainParams) (R2BucketCustomDomain, error) {
+	if rc.Identifier == "" {
+		return R2BucketCustomDomain{}, ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return R2BucketCustomDomain{}, ErrMissingBucketName
+	}
+
+	if params.Domain == "" {
+		return R2BucketCustomDomain{}, ErrMissingR2CustomDomain
+	}
+
+	if params.ZoneID == "" {
+		return R2BucketCustomDomain{}, ErrMissingZoneID
+	}
+
+	if err := validateR2MinTLS(params.MinTLS); err != nil {
+		return R2BucketCustomDomain{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom", rc.Identifier, params.BucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return R2BucketCustomDomain{}, err
+	}
+
+	var r R2BucketCustomDomainResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketCustomDomain{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// UpdateR2BucketCustomDomain enables, disables or changes the minimum TLS
+// version of a custom domain.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-edit-custom-domain-settings
+func (api *API) UpdateR2BucketCustomDomain(ctx context.Context, rc *ResourceContainer, params UpdateR2BucketCustomDomainParams) (R2BucketCustomDomain, error) {
+	if rc.Identifier == "" {
+		return R2BucketCustomDomain{}, ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return R2BucketCustomDomain{}, ErrMissingBucketName
+	}
+
+	if params.Domain == "" {
+		return R2BucketCustomDomain{}, ErrMissingR2CustomDomain
+	}
+
+	if err := validateR2MinTLS(params.MinTLS); err != nil {
+		return R2BucketCustomDomain{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom/%s", rc.Identifier, params.BucketName, params.Domain)
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+	if err != nil {
+		return R2BucketCustomDomain{}, err
+	}
+
+	var r R2BucketCustomDomainResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketCustomDomain{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// DeleteR2BucketCustomDomain disconnects a custom domain from a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-delete-custom-domain
+func (api *API) DeleteR2BucketCustomDomain(ctx context.Context, rc *ResourceContainer, params DeleteR2BucketCustomDomainParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	if params.Domain == "" {
+		return ErrMissingR2CustomDomain
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom/%s", rc.Identifier, params.BucketName, params.Domain)
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+
+	return err
+}
+
+// GetR2BucketManagedDomain returns the r2.dev domain of a bucket and whether
+// public access through it is enabled.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-get-bucket-public-policy
+func (api *API) GetR2BucketManagedDomain(ctx context.Context, rc *ResourceContainer, bucketName string) (R2BucketManagedDomain, error) {
+	if rc.Identifier == "" {
+		return R2BucketManagedDomain{}, ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return R2BucketManagedDomain{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/managed", rc.Identifier, bucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return R2BucketManagedDomain{}, err
+	}
+
+	var r R2BucketManagedDomainResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketManagedDomain{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// SetR2BucketManagedDomain enables or disables public access to a bucket
+// through its r2.dev domain.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-put-bucket-public-policy
+func (api *API) SetR2BucketManagedDomain(ctx context.Context, rc *ResourceContainer, params SetR2BucketManagedDomainParams) (R2BucketManagedDomain, error) {
+	if rc.Identifier == "" {
+		return R2BucketManagedDomain{}, ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return R2BucketManagedDomain{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/managed", rc.Identifier, params.BucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+	if err != nil {
+		return R2BucketManagedDomain{}, err
+	}
+
+	var r R2BucketManagedDomainResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketManagedDomain{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
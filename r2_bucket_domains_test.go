@@ -0,0 +1,162 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testR2CustomDomain = `{
+  "domain": "assets.example.com",
+  "enabled": true,
+  "status": {"ownership": "active", "ssl": "active"},
+  "minTLS": "1.2",
+  "zoneId": "` + testZoneID + `",
+  "zoneName": "example.com"
+}`
+
+var expectedR2CustomDomain = R2BucketCustomDomain{
+	Domain:   "assets.example.com",
+	Enabled:  true,
+	Status:   R2BucketCustomDomainStatus{Ownership: "active", SSL: "active"},
+	MinTLS:   "1.2",
+	ZoneID:   testZoneID,
+	ZoneName: "example.com",
+}
+
+func TestR2_ListBucketCustomDomains(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"domains": [%s]}}`, testR2CustomDomain)
+	})
+
+	actual, err := client.ListR2BucketCustomDomains(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []R2BucketCustomDomain{expectedR2CustomDomain}, actual)
+	}
+}
+
+func TestR2_AddBucketCustomDomain(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"domain": "assets.example.com", "zoneId": "%s", "enabled": true, "minTLS": "1.2"}`, testZoneID), string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, testR2CustomDomain)
+	})
+
+	params := AddR2BucketCustomDomainParams{
+		BucketName: testBucketName,
+		Domain:     "assets.example.com",
+		Enabled:    true,
+		MinTLS:     "1.2",
+	}
+	_, err := client.AddR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), params)
+	assert.ErrorIs(t, err, ErrMissingZoneID)
+
+	params.ZoneID = testZoneID
+	params.MinTLS = "1.4"
+	_, err = client.AddR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), params)
+	assert.ErrorIs(t, err, ErrInvalidR2MinTLS)
+
+	params.MinTLS = "1.2"
+	actual, err := client.AddR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedR2CustomDomain, actual)
+	}
+}
+
+func TestR2_UpdateBucketCustomDomain(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom/assets.example.com", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"enabled": true}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, testR2CustomDomain)
+	})
+
+	actual, err := client.UpdateR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), UpdateR2BucketCustomDomainParams{
+		BucketName: testBucketName,
+		Domain:     "assets.example.com",
+		Enabled:    BoolPtr(true),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, expectedR2CustomDomain, actual)
+	}
+}
+
+func TestR2_DeleteBucketCustomDomain(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/custom/assets.example.com", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"domain": "assets.example.com"}}`)
+	})
+
+	err := client.DeleteR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), DeleteR2BucketCustomDomainParams{BucketName: testBucketName})
+	assert.ErrorIs(t, err, ErrMissingR2CustomDomain)
+
+	err = client.DeleteR2BucketCustomDomain(context.Background(), AccountIdentifier(testAccountID), DeleteR2BucketCustomDomainParams{
+		BucketName: testBucketName,
+		Domain:     "assets.example.com",
+	})
+	assert.NoError(t, err)
+}
+
+func TestR2_BucketManagedDomain(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/domains/managed", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		enabled := "false"
+		if r.Method == http.MethodPut {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"enabled": true}`, string(body))
+			enabled = "true"
+		}
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"bucketId": "0113a9e4549cf9b1ff1bf56e04da0cef", "domain": "pub-0113a9e4549cf9b1ff1bf56e04da0cef.r2.dev", "enabled": %s}}`, enabled)
+	})
+
+	want := R2BucketManagedDomain{
+		BucketID: "0113a9e4549cf9b1ff1bf56e04da0cef",
+		Domain:   "pub-0113a9e4549cf9b1ff1bf56e04da0cef.r2.dev",
+	}
+
+	actual, err := client.GetR2BucketManagedDomain(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	want.Enabled = true
+	actual, err = client.SetR2BucketManagedDomain(context.Background(), AccountIdentifier(testAccountID), SetR2BucketManagedDomainParams{
+		BucketName: testBucketName,
+		Enabled:    true,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
@@ -0,0 +1,193 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingR2EventNotificationRules   = errors.New("at least one event notification rule is required")
+	ErrMissingR2EventNotificationActions = errors.New("event notification rules require at least one action")
+	ErrInvalidR2EventNotificationAction  = errors.New("unknown event notification action")
+)
+
+// R2EventNotificationAction is the type of object change that triggers a
+// notification.
+type R2EventNotificationAction string
+
+const (
+	R2EventNotificationActionPutObject               R2EventNotificationAction = "PutObject"
+	R2EventNotificationActionCopyObject              R2EventNotificationAction = "CopyObject"
+	R2EventNotificationActionDeleteObject            R2EventNotificationAction = "DeleteObject"
+	R2EventNotificationActionCompleteMultipartUpload R2EventNotificationAction = "CompleteMultipartUpload"
+	R2EventNotificationActionLifecycleDeletion       R2EventNotificationAction = "LifecycleDeletion"
+)
+
+// R2EventNotificationActionValues returns all valid event notification
+// actions.
+func R2EventNotificationActionValues() []string {
+	return []string{
+		string(R2EventNotificationActionPutObject),
+		string(R2EventNotificationActionCopyObject),
+		string(R2EventNotificationActionDeleteObject),
+		string(R2EventNotificationActionCompleteMultipartUpload),
+		string(R2EventNotificationActionLifecycleDeletion),
+	}
+}
+
+// R2EventNotificationRule sends a message to a queue whenever an object
+// matching the prefix and suffix is changed by one of the actions.
+//
+// API reference: https://developers.cloudflare.com/r2/buckets/event-notifications/
+type R2EventNotificationRule struct {
+	RuleID      string                      `json:"ruleId,omitempty"`
+	Actions     []R2EventNotificationAction `json:"actions"`
+	Prefix      string                      `json:"prefix,omitempty"`
+	Suffix      string                      `json:"suffix,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	CreatedAt   *time.Time                  `json:"createdAt,omitempty"`
+}
+
+// R2EventNotificationQueue is a queue receiving notifications for a bucket
+// along with the rules that target it.
+type R2EventNotificationQueue struct {
+	QueueID   string                    `json:"queueId"`
+	QueueName string                    `json:"queueName"`
+	Rules     []R2EventNotificationRule `json:"rules"`
+}
+
+// R2BucketEventNotifications is the event notification configuration of a
+// bucket.
+type R2BucketEventNotifications struct {
+	BucketName string                     `json:"bucketName"`
+	Queues     []R2EventNotificationQueue `json:"queues"`
+}
+
+type R2BucketEventNotificationsResponse struct {
+	Result R2BucketEventNotifications `json:"result"`
+	Response
+}
+
+type SetR2BucketEventNotificationParams struct {
+	BucketName string                    `json:"-"`
+	QueueID    string                    `json:"-"`
+	Rules      []R2EventNotificationRule `json:"rules"`
+}
+
+type DeleteR2BucketEventNotificationParams struct {
+	BucketName string `json:"-"`
+	QueueID    string `json:"-"`
+
+	// RuleIDs limits the deletion to the given rules. When empty, every rule
+	// targeting the queue is removed.
+	RuleIDs []string `json:"ruleIds,omitempty"`
+}
+
+// Validate checks that the rule can be accepted by the API.
+func (r R2EventNotificationRule) Validate() error {
+	if len(r.Actions) == 0 {
+		return ErrMissingR2EventNotificationActions
+	}
+
+	for _, a := range r.Actions {
+		if !contains(R2EventNotificationActionValues(), string(a)) {
+			return fmt.Errorf("%w: %q", ErrInvalidR2EventNotificationAction, a)
+		}
+	}
+
+	return nil
+}
+
+// GetR2BucketEventNotifications returns the event notification rules of a
+// bucket grouped by destination queue.
+//
+// API reference: https://developers.cloudflare.com/api/operations/event-notifications-r2-bucket-read-configuration
+func (api *API) GetR2BucketEventNotifications(ctx context.Context, rc *ResourceContainer, bucketName string) (R2BucketEventNotifications, error) {
+	if rc.Identifier == "" {
+		return R2BucketEventNotifications{}, ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return R2BucketEventNotifications{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration", rc.Identifier, bucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return R2BucketEventNotifications{}, err
+	}
+
+	var r R2BucketEventNotificationsResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketEventNotifications{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// SetR2BucketEventNotification adds rules sending notifications for a bucket
+// to a queue.
+//
+// API reference: https://developers.cloudflare.com/api/operations/event-notifications-r2-bucket-create-configuration
+func (api *API) SetR2BucketEventNotification(ctx context.Context, rc *ResourceContainer, params SetR2BucketEventNotificationParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	if params.QueueID == "" {
+		return ErrMissingQueueID
+	}
+
+	if len(params.Rules) == 0 {
+		return ErrMissingR2EventNotificationRules
+	}
+
+	for _, rule := range params.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration/queues/%s", rc.Identifier, params.BucketName, params.QueueID)
+	_, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+
+	return err
+}
+
+// DeleteR2BucketEventNotification removes rules sending notifications for a
+// bucket to a queue.
+//
+// API reference: https://developers.cloudflare.com/api/operations/event-notifications-r2-bucket-delete-configuration
+func (api *API) DeleteR2BucketEventNotification(ctx context.Context, rc *ResourceContainer, params DeleteR2BucketEventNotificationParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	if params.QueueID == "" {
+		return ErrMissingQueueID
+	}
+
+	var body interface{}
+	if len(params.RuleIDs) > 0 {
+		body = params
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration/queues/%s", rc.Identifier, params.BucketName, params.QueueID)
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, body)
+
+	return err
+}
@@ -0,0 +1,125 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestR2_GetBucketEventNotifications(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+  "success": true,
+  "errors": [],
+  "messages": [],
+  "result": {
+    "bucketName": "example-bucket",
+    "queues": [
+      {
+        "queueId": "%s",
+        "queueName": "%s",
+        "rules": [
+          {
+            "ruleId": "11d1e0b9-bb5b-4ad8-a4b5-d6b1d5c7c2b1",
+            "actions": ["PutObject", "CopyObject"],
+            "prefix": "uploads/",
+            "suffix": ".png",
+            "createdAt": "2024-05-01T00:00:00Z"
+          }
+        ]
+      }
+    ]
+  }
+}`, testQueueID, testQueueName)
+	})
+
+	createdAt, _ := time.Parse(time.RFC3339, "2024-05-01T00:00:00Z")
+	want := R2BucketEventNotifications{
+		BucketName: testBucketName,
+		Queues: []R2EventNotificationQueue{
+			{
+				QueueID:   testQueueID,
+				QueueName: testQueueName,
+				Rules: []R2EventNotificationRule{
+					{
+						RuleID:    "11d1e0b9-bb5b-4ad8-a4b5-d6b1d5c7c2b1",
+						Actions:   []R2EventNotificationAction{R2EventNotificationActionPutObject, R2EventNotificationActionCopyObject},
+						Prefix:    "uploads/",
+						Suffix:    ".png",
+						CreatedAt: &createdAt,
+					},
+				},
+			},
+		},
+	}
+
+	actual, err := client.GetR2BucketEventNotifications(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestR2_SetBucketEventNotification(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration/queues/%s", testAccountID, testBucketName, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rules": [{"actions": ["DeleteObject", "LifecycleDeletion"], "prefix": "tmp/"}]}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+	})
+
+	err := client.SetR2BucketEventNotification(context.Background(), AccountIdentifier(testAccountID), SetR2BucketEventNotificationParams{
+		BucketName: testBucketName,
+		QueueID:    testQueueID,
+		Rules:      []R2EventNotificationRule{{Actions: []R2EventNotificationAction{"RenameObject"}}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidR2EventNotificationAction)
+
+	err = client.SetR2BucketEventNotification(context.Background(), AccountIdentifier(testAccountID), SetR2BucketEventNotificationParams{
+		BucketName: testBucketName,
+		QueueID:    testQueueID,
+		Rules: []R2EventNotificationRule{{
+			Actions: []R2EventNotificationAction{R2EventNotificationActionDeleteObject, R2EventNotificationActionLifecycleDeletion},
+			Prefix:  "tmp/",
+		}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestR2_DeleteBucketEventNotification(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/event_notifications/r2/%s/configuration/queues/%s", testAccountID, testBucketName, testQueueID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"ruleIds": ["11d1e0b9-bb5b-4ad8-a4b5-d6b1d5c7c2b1"]}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+	})
+
+	err := client.DeleteR2BucketEventNotification(context.Background(), AccountIdentifier(testAccountID), DeleteR2BucketEventNotificationParams{
+		BucketName: testBucketName,
+		QueueID:    testQueueID,
+		RuleIDs:    []string{"11d1e0b9-bb5b-4ad8-a4b5-d6b1d5c7c2b1"},
+	})
+	assert.NoError(t, err)
+}
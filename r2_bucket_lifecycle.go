@@ -0,0 +1,201 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingR2LifecycleRuleID         = errors.New("lifecycle rules require an ID")
+	ErrMissingR2LifecycleTransition     = errors.New("lifecycle rules require at least one transition")
+	ErrInvalidR2LifecycleCondition      = errors.New("lifecycle conditions require a positive max age for Age conditions or a date for Date conditions")
+	ErrInvalidR2LifecycleMultipartAbort = errors.New("aborting multipart uploads only supports Age conditions")
+	ErrMissingR2LifecycleStorageClass   = errors.New("storage class transitions require a storage class")
+)
+
+// R2LifecycleConditionType determines whether a lifecycle transition is
+// applied after objects reach an age or on a fixed date.
+type R2LifecycleConditionType string
+
+const (
+	R2LifecycleConditionTypeAge  R2LifecycleConditionType = "Age"
+	R2LifecycleConditionTypeDate R2LifecycleConditionType = "Date"
+)
+
+// R2StorageClass is the storage class objects can be transitioned to.
+type R2StorageClass string
+
+const (
+	R2StorageClassStandard         R2StorageClass = "Standard"
+	R2StorageClassInfrequentAccess R2StorageClass = "InfrequentAccess"
+)
+
+// R2BucketLifecycleRule removes or transitions objects matching its
+// conditions.
+//
+// API reference: https://developers.cloudflare.com/r2/buckets/object-lifecycles/
+type R2BucketLifecycleRule struct {
+	ID                              string                               `json:"id"`
+	Enabled                         bool                                 `json:"enabled"`
+	Conditions                      R2BucketLifecycleRuleConditions      `json:"conditions"`
+	DeleteObjectsTransition         *R2BucketLifecycleTransition         `json:"deleteObjectsTransition,omitempty"`
+	AbortMultipartUploadsTransition *R2BucketLifecycleTransition         `json:"abortMultipartUploadsTransition,omitempty"`
+	StorageClassTransitions         []R2BucketLifecycleStorageTransition `json:"storageClassTransitions,omitempty"`
+}
+
+// R2BucketLifecycleRuleConditions restricts a rule to objects with a key
+// prefix. An empty prefix matches every object.
+type R2BucketLifecycleRuleConditions struct {
+	Prefix string `json:"prefix"`
+}
+
+// R2BucketLifecycleTransition is applied once its condition is met.
+type R2BucketLifecycleTransition struct {
+	Condition R2BucketLifecycleCondition `json:"condition"`
+}
+
+// R2BucketLifecycleStorageTransition moves objects to another storage class
+// once its condition is met.
+type R2BucketLifecycleStorageTransition struct {
+	Condition    R2BucketLifecycleCondition `json:"condition"`
+	StorageClass R2StorageClass             `json:"storageClass"`
+}
+
+// R2BucketLifecycleCondition is either an age in seconds since the object
+// was uploaded or a fixed date.
+type R2BucketLifecycleCondition struct {
+	Type   R2LifecycleConditionType `json:"type"`
+	MaxAge int                      `json:"maxAge,omitempty"`
+	Date   *time.Time               `json:"date,omitempty"`
+}
+
+// R2BucketLifecyclePolicy is the set of lifecycle rules of a bucket.
+type R2BucketLifecyclePolicy struct {
+	Rules []R2BucketLifecycleRule `json:"rules"`
+}
+
+type R2BucketLifecyclePolicyResponse struct {
+	Result R2BucketLifecyclePolicy `json:"result"`
+	Response
+}
+
+type SetR2BucketLifecyclePolicyParams struct {
+	BucketName string                  `json:"-"`
+	Rules      []R2BucketLifecycleRule `json:"rules"`
+}
+
+// Validate checks that the condition is complete for its type.
+func (c R2BucketLifecycleCondition) Validate() error {
+	switch c.Type {
+	case R2LifecycleConditionTypeAge:
+		if c.MaxAge <= 0 {
+			return ErrInvalidR2LifecycleCondition
+		}
+	case R2LifecycleConditionTypeDate:
+		if c.Date == nil || c.Date.IsZero() {
+			return ErrInvalidR2LifecycleCondition
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidR2LifecycleCondition, c.Type)
+	}
+
+	return nil
+}
+
+// Validate checks that the rule can be accepted by the API.
+func (r R2BucketLifecycleRule) Validate() error {
+	if r.ID == "" {
+		return ErrMissingR2LifecycleRuleID
+	}
+
+	if r.DeleteObjectsTransition == nil && r.AbortMultipartUploadsTransition == nil && len(r.StorageClassTransitions) == 0 {
+		return ErrMissingR2LifecycleTransition
+	}
+
+	if r.DeleteObjectsTransition != nil {
+		if err := r.DeleteObjectsTransition.Condition.Validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.ID, err)
+		}
+	}
+
+	if r.AbortMultipartUploadsTransition != nil {
+		if r.AbortMultipartUploadsTransition.Condition.Type != R2LifecycleConditionTypeAge {
+			return fmt.Errorf("rule %q: %w", r.ID, ErrInvalidR2LifecycleMultipartAbort)
+		}
+		if err := r.AbortMultipartUploadsTransition.Condition.Validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.ID, err)
+		}
+	}
+
+	for _, t := range r.StorageClassTransitions {
+		if t.StorageClass == "" {
+			return fmt.Errorf("rule %q: %w", r.ID, ErrMissingR2LifecycleStorageClass)
+		}
+		if err := t.Condition.Validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetR2BucketLifecyclePolicy returns the object lifecycle rules of a bucket.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-get-bucket-lifecycle-configuration
+func (api *API) GetR2BucketLifecyclePolicy(ctx context.Context, rc *ResourceContainer, bucketName string) (R2BucketLifecyclePolicy, error) {
+	if rc.Identifier == "" {
+		return R2BucketLifecyclePolicy{}, ErrMissingAccountID
+	}
+
+	if bucketName == "" {
+		return R2BucketLifecyclePolicy{}, ErrMissingBucketName
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/lifecycle", rc.Identifier, bucketName)
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return R2BucketLifecyclePolicy{}, err
+	}
+
+	var r R2BucketLifecyclePolicyResponse
+	err = json.Unmarshal(res, &r)
+	if err != nil {
+		return R2BucketLifecyclePolicy{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return r.Result, nil
+}
+
+// SetR2BucketLifecyclePolicy replaces the object lifecycle rules of a
+// bucket. Passing no rules removes every lifecycle rule.
+//
+// API reference: https://developers.cloudflare.com/api/operations/r2-put-bucket-lifecycle-configuration
+func (api *API) SetR2BucketLifecyclePolicy(ctx context.Context, rc *ResourceContainer, params SetR2BucketLifecyclePolicyParams) error {
+	if rc.Identifier == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.BucketName == "" {
+		return ErrMissingBucketName
+	}
+
+	if params.Rules == nil {
+		params.Rules = []R2BucketLifecycleRule{}
+	}
+
+	for _, rule := range params.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/r2/buckets/%s/lifecycle", rc.Identifier, params.BucketName)
+	_, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+
+	return err
+}
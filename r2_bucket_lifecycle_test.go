@@ -0,0 +1,110 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestR2_GetBucketLifecyclePolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/lifecycle", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+  "success": true,
+  "errors": [],
+  "messages": [],
+  "result": {
+    "rules": [
+      {
+        "id": "expire-logs",
+        "enabled": true,
+        "conditions": {"prefix": "logs/"},
+        "deleteObjectsTransition": {"condition": {"type": "Age", "maxAge": 2592000}},
+        "abortMultipartUploadsTransition": {"condition": {"type": "Age", "maxAge": 604800}},
+        "storageClassTransitions": [
+          {"condition": {"type": "Date", "date": "2024-01-01T00:00:00Z"}, "storageClass": "InfrequentAccess"}
+        ]
+      }
+    ]
+  }
+}`)
+	})
+
+	date, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
+	want := R2BucketLifecyclePolicy{
+		Rules: []R2BucketLifecycleRule{
+			{
+				ID:         "expire-logs",
+				Enabled:    true,
+				Conditions: R2BucketLifecycleRuleConditions{Prefix: "logs/"},
+				DeleteObjectsTransition: &R2BucketLifecycleTransition{
+					Condition: R2BucketLifecycleCondition{Type: R2LifecycleConditionTypeAge, MaxAge: 2592000},
+				},
+				AbortMultipartUploadsTransition: &R2BucketLifecycleTransition{
+					Condition: R2BucketLifecycleCondition{Type: R2LifecycleConditionTypeAge, MaxAge: 604800},
+				},
+				StorageClassTransitions: []R2BucketLifecycleStorageTransition{
+					{
+						Condition:    R2BucketLifecycleCondition{Type: R2LifecycleConditionTypeDate, Date: &date},
+						StorageClass: R2StorageClassInfrequentAccess,
+					},
+				},
+			},
+		},
+	}
+
+	actual, err := client.GetR2BucketLifecyclePolicy(context.Background(), AccountIdentifier(testAccountID), testBucketName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestR2_SetBucketLifecyclePolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/r2/buckets/%s/lifecycle", testAccountID, testBucketName), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rules": [{"id": "expire", "enabled": true, "conditions": {"prefix": ""}, "deleteObjectsTransition": {"condition": {"type": "Age", "maxAge": 86400}}}]}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {}}`)
+	})
+
+	err := client.SetR2BucketLifecyclePolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketLifecyclePolicyParams{
+		BucketName: testBucketName,
+		Rules:      []R2BucketLifecycleRule{{ID: "expire", Enabled: true}},
+	})
+	assert.ErrorIs(t, err, ErrMissingR2LifecycleTransition)
+
+	err = client.SetR2BucketLifecyclePolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketLifecyclePolicyParams{
+		BucketName: testBucketName,
+		Rules: []R2BucketLifecycleRule{{
+			ID:                              "abort",
+			AbortMultipartUploadsTransition: &R2BucketLifecycleTransition{Condition: R2BucketLifecycleCondition{Type: R2LifecycleConditionTypeDate}},
+		}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidR2LifecycleMultipartAbort)
+
+	err = client.SetR2BucketLifecyclePolicy(context.Background(), AccountIdentifier(testAccountID), SetR2BucketLifecyclePolicyParams{
+		BucketName: testBucketName,
+		Rules: []R2BucketLifecycleRule{{
+			ID:                      "expire",
+			Enabled:                 true,
+			DeleteObjectsTransition: &R2BucketLifecycleTransition{Condition: R2BucketLifecycleCondition{Type: R2LifecycleConditionTypeAge, MaxAge: 86400}},
+		}},
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,793 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissingR2ObjectKey       = errors.New("required object key is missing")
+	ErrMissingR2Credentials     = errors.New("R2 access keys are required when the client is not authenticated with an API token")
+	ErrMissingR2UploadID        = errors.New("required multipart upload ID is missing")
+	ErrInvalidR2PartNumber      = errors.New("part numbers must be between 1 and 10000")
+	ErrMissingR2MultipartParts  = errors.New("at least one part is required to complete a multipart upload")
+	ErrInvalidR2PresignDuration = errors.New("presigned URLs must expire after between 1 second and 7 days")
+)
+
+const (
+	r2ObjectRegion           = "auto"
+	r2DefaultPartSize        = 8 * 1024 * 1024
+	r2DefaultPartConcurrency = 4
+	r2MaxParts               = 10000
+	r2MaxPresignDuration     = 7 * 24 * time.Hour
+)
+
+// R2ObjectClient manages objects through the S3-compatible endpoint of an
+// account's R2 storage. Create one with API.NewR2ObjectClient.
+//
+// API reference: https://developers.cloudflare.com/r2/api/s3/api/
+type R2ObjectClient struct {
+	endpoint   *url.URL
+	signer     sigV4Signer
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+type R2ObjectClientParams struct {
+	// AccessKeyID and SecretAccessKey are the R2 access keys to sign requests
+	// with. When empty, keys are derived from the API token the client was
+	// created with.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// Jurisdiction selects the endpoint of buckets created in a jurisdiction,
+	// for example "eu".
+	Jurisdiction string
+
+	// Endpoint overrides the S3 endpoint, for example to point at a local
+	// S3-compatible server in tests.
+	Endpoint string
+}
+
+// R2ObjectError is returned when the S3-compatible endpoint rejects a
+// request.
+type R2ObjectError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *R2ObjectError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("R2 request failed with HTTP status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("R2 request failed with HTTP status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// R2Object describes a stored object.
+type R2Object struct {
+	Key          string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+	ContentType  string
+	StorageClass string
+	Metadata     map[string]string
+}
+
+type PutR2ObjectParams struct {
+	Bucket string
+	Key    string
+	Body   io.Reader
+
+	// ContentLength must be set unless Body is a *bytes.Buffer,
+	// *bytes.Reader or *strings.Reader, whose length is known.
+	ContentLength int64
+	ContentType   string
+	CacheControl  string
+
+	// Metadata is stored as x-amz-meta-* headers on the object.
+	Metadata map[string]string
+}
+
+type GetR2ObjectParams struct {
+	Bucket string
+	Key    string
+
+	// Range requests part of the object, for example "bytes=0-1023".
+	Range string
+}
+
+type ListR2ObjectsParams struct {
+	Bucket            string
+	Prefix            string
+	Delimiter         string
+	StartAfter        string
+	ContinuationToken string
+	MaxKeys           int
+}
+
+// R2ObjectList is a page of objects in a bucket.
+type R2ObjectList struct {
+	Objects               []R2Object
+	CommonPrefixes        []string
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+type CreateR2MultipartUploadParams struct {
+	Bucket      string
+	Key         string
+	ContentType string
+	Metadata    map[string]string
+}
+
+// R2ObjectPart is an uploaded part of a multipart upload.
+type R2ObjectPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+	Size       int64  `xml:"Size,omitempty"`
+}
+
+type UploadR2ObjectPartParams struct {
+	Bucket        string
+	Key           string
+	UploadID      string
+	PartNumber    int
+	Body          io.Reader
+	ContentLength int64
+}
+
+type CompleteR2MultipartUploadParams struct {
+	Bucket   string
+	Key      string
+	UploadID string
+	Parts    []R2ObjectPart
+}
+
+type UploadR2ObjectParams struct {
+	Bucket      string
+	Key         string
+	Body        io.ReaderAt
+	Size        int64
+	ContentType string
+	Metadata    map[string]string
+
+	// PartSize defaults to 8 MiB. R2 requires every part but the last to be
+	// at least 5 MiB and of the same size.
+	PartSize int64
+
+	// Concurrency is the number of parts uploaded at once. Defaults to 4.
+	Concurrency int
+
+	// UploadID resumes a previous upload. Parts that were already uploaded
+	// are listed and skipped.
+	UploadID string
+
+	// OnPartUploaded is called after each part has been uploaded.
+	OnPartUploaded func(part R2ObjectPart)
+}
+
+// R2MultipartUpload is the result of UploadObject. UploadID is set even
+// when the upload fails so that it can be resumed.
+type R2MultipartUpload struct {
+	UploadID string
+	ETag     string
+}
+
+// NewR2ObjectClient returns a client for the S3-compatible endpoint of the
+// account's R2 storage.
+//
+// When no access keys are given, the client must be authenticated with an API
+// token. The token is verified to find its ID, which is used as the access
+// key ID, while the SHA-256 hash of the token is used as the secret.
+//
+// API reference: https://developers.cloudflare.com/r2/api/s3/tokens/
+func (api *API) NewR2ObjectClient(ctx context.Context, rc *ResourceContainer, params R2ObjectClientParams) (*R2ObjectClient, error) {
+	if rc.Identifier == "" {
+		return nil, ErrMissingAccountID
+	}
+
+	accessKeyID, secret := params.AccessKeyID, params.SecretAccessKey
+	if accessKeyID == "" || secret == "" {
+		if api.APIToken == "" {
+			return nil, ErrMissingR2Credentials
+		}
+
+		token, err := api.VerifyAPIToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up API token ID: %w", err)
+		}
+
+		hash := sha256.Sum256([]byte(api.APIToken))
+		accessKeyID, secret = token.ID, hex.EncodeToString(hash[:])
+	}
+
+	endpoint := params.Endpoint
+	if endpoint == "" {
+		host := rc.Identifier
+		if params.Jurisdiction != "" {
+			host += "." + params.Jurisdiction
+		}
+		endpoint = fmt.Sprintf("https://%s.r2.cloudflarestorage.com", host)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid R2 endpoint: %w", err)
+	}
+
+	return &R2ObjectClient{
+		endpoint: u,
+		signer: sigV4Signer{
+			accessKeyID:     accessKeyID,
+			secretAccessKey: secret,
+			region:          r2ObjectRegion,
+			service:         "s3",
+		},
+		httpClient: api.httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// objectURL returns the path-style URL of an object. The path is escaped
+// the same way it is signed.
+func (c *R2ObjectClient) objectURL(bucket, key string, query url.Values) *url.URL {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = sigV4EscapePath(u.Path)
+	u.RawQuery = sigV4CanonicalQuery(query)
+	return &u
+}
+
+func (c *R2ObjectClient) do(ctx context.Context, method string, u *url.URL, body io.Reader, contentLength int64, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request creation failed: %w", err)
+	}
+
+	if contentLength > 0 {
+		req.ContentLength = contentLength
+	}
+	copyHeader(req.Header, headers)
+	c.signer.sign(req, sigV4UnsignedPayload, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		objErr := &R2ObjectError{StatusCode: resp.StatusCode}
+		if b, readErr := io.ReadAll(resp.Body); readErr == nil && len(b) > 0 {
+			_ = xml.Unmarshal(b, objErr)
+		}
+		return nil, objErr
+	}
+
+	return resp, nil
+}
+
+func (c *R2ObjectClient) doXML(ctx context.Context, method string, u *url.URL, body io.Reader, headers http.Header, v interface{}) error {
+	resp, err := c.do(ctx, method, u, body, 0, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if v == nil {
+		return nil
+	}
+
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return nil
+}
+
+func r2MetadataHeaders(contentType string, metadata map[string]string) http.Header {
+	headers := make(http.Header)
+	if contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+	for k, v := range metadata {
+		headers.Set("x-amz-meta-"+k, v)
+	}
+	return headers
+}
+
+func r2ObjectFromHeaders(key string, h http.Header) R2Object {
+	obj := R2Object{
+		Key:          key,
+		ETag:         h.Get("ETag"),
+		ContentType:  h.Get("Content-Type"),
+		StorageClass: h.Get("x-amz-storage-class"),
+	}
+	obj.Size, _ = strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	obj.LastModified, _ = time.Parse(http.TimeFormat, h.Get("Last-Modified"))
+
+	for name, v := range h {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-meta-") && len(v) > 0 {
+			if obj.Metadata == nil {
+				obj.Metadata = make(map[string]string)
+			}
+			obj.Metadata[strings.TrimPrefix(name, "x-amz-meta-")] = v[0]
+		}
+	}
+
+	return obj
+}
+
+func validateR2ObjectLocation(bucket, key string) error {
+	if bucket == "" {
+		return ErrMissingBucketName
+	}
+	if key == "" {
+		return ErrMissingR2ObjectKey
+	}
+	return nil
+}
+
+// PutObject uploads an object in a single request.
+func (c *R2ObjectClient) PutObject(ctx context.Context, params PutR2ObjectParams) (R2Object, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return R2Object{}, err
+	}
+
+	headers := r2MetadataHeaders(params.ContentType, params.Metadata)
+	if params.CacheControl != "" {
+		headers.Set("Cache-Control", params.CacheControl)
+	}
+
+	body := params.Body
+	if body == nil {
+		body = http.NoBody
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, c.objectURL(params.Bucket, params.Key, nil), body, params.ContentLength, headers)
+	if err != nil {
+		return R2Object{}, err
+	}
+	defer resp.Body.Close()
+
+	return R2Object{
+		Key:         params.Key,
+		ETag:        resp.Header.Get("ETag"),
+		ContentType: params.ContentType,
+		Metadata:    params.Metadata,
+	}, nil
+}
+
+// GetObject downloads an object. The caller must close the returned body.
+func (c *R2ObjectClient) GetObject(ctx context.Context, params GetR2ObjectParams) (io.ReadCloser, R2Object, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return nil, R2Object{}, err
+	}
+
+	headers := make(http.Header)
+	if params.Range != "" {
+		headers.Set("Range", params.Range)
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, c.objectURL(params.Bucket, params.Key, nil), nil, 0, headers)
+	if err != nil {
+		return nil, R2Object{}, err
+	}
+
+	return resp.Body, r2ObjectFromHeaders(params.Key, resp.Header), nil
+}
+
+// HeadObject returns the metadata of an object without downloading it.
+func (c *R2ObjectClient) HeadObject(ctx context.Context, bucket, key string) (R2Object, error) {
+	if err := validateR2ObjectLocation(bucket, key); err != nil {
+		return R2Object{}, err
+	}
+
+	resp, err := c.do(ctx, http.MethodHead, c.objectURL(bucket, key, nil), nil, 0, nil)
+	if err != nil {
+		return R2Object{}, err
+	}
+	resp.Body.Close()
+
+	return r2ObjectFromHeaders(key, resp.Header), nil
+}
+
+// DeleteObject deletes an object. Deleting a missing object is not an error.
+func (c *R2ObjectClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := validateR2ObjectLocation(bucket, key); err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, c.objectURL(bucket, key, nil), nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// ListObjects returns a page of objects in a bucket. Pass
+// NextContinuationToken back as ContinuationToken to get the next page.
+func (c *R2ObjectClient) ListObjects(ctx context.Context, params ListR2ObjectsParams) (R2ObjectList, error) {
+	if params.Bucket == "" {
+		return R2ObjectList{}, ErrMissingBucketName
+	}
+
+	q := url.Values{"list-type": {"2"}}
+	if params.Prefix != "" {
+		q.Set("prefix", params.Prefix)
+	}
+	if params.Delimiter != "" {
+		q.Set("delimiter", params.Delimiter)
+	}
+	if params.StartAfter != "" {
+		q.Set("start-after", params.StartAfter)
+	}
+	if params.ContinuationToken != "" {
+		q.Set("continuation-token", params.ContinuationToken)
+	}
+	if params.MaxKeys > 0 {
+		q.Set("max-keys", strconv.Itoa(params.MaxKeys))
+	}
+
+	var res struct {
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+		Contents              []struct {
+			Key          string    `xml:"Key"`
+			LastModified time.Time `xml:"LastModified"`
+			ETag         string    `xml:"ETag"`
+			Size         int64     `xml:"Size"`
+			StorageClass string    `xml:"StorageClass"`
+		} `xml:"Contents"`
+		CommonPrefixes []struct {
+			Prefix string `xml:"Prefix"`
+		} `xml:"CommonPrefixes"`
+	}
+	if err := c.doXML(ctx, http.MethodGet, c.objectURL(params.Bucket, "", q), nil, nil, &res); err != nil {
+		return R2ObjectList{}, err
+	}
+
+	list := R2ObjectList{
+		IsTruncated:           res.IsTruncated,
+		NextContinuationToken: res.NextContinuationToken,
+	}
+	for _, o := range res.Contents {
+		list.Objects = append(list.Objects, R2Object{
+			Key:          o.Key,
+			ETag:         o.ETag,
+			Size:         o.Size,
+			LastModified: o.LastModified,
+			StorageClass: o.StorageClass,
+		})
+	}
+	for _, p := range res.CommonPrefixes {
+		list.CommonPrefixes = append(list.CommonPrefixes, p.Prefix)
+	}
+
+	return list, nil
+}
+
+// CreateMultipartUpload starts a multipart upload and returns its ID.
+func (c *R2ObjectClient) CreateMultipartUpload(ctx context.Context, params CreateR2MultipartUploadParams) (string, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return "", err
+	}
+
+	var res struct {
+		UploadID string `xml:"UploadId"`
+	}
+	q := url.Values{"uploads": {""}}
+	err := c.doXML(ctx, http.MethodPost, c.objectURL(params.Bucket, params.Key, q), nil, r2MetadataHeaders(params.ContentType, params.Metadata), &res)
+	if err != nil {
+		return "", err
+	}
+
+	return res.UploadID, nil
+}
+
+// UploadPart uploads a single part of a multipart upload.
+func (c *R2ObjectClient) UploadPart(ctx context.Context, params UploadR2ObjectPartParams) (R2ObjectPart, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return R2ObjectPart{}, err
+	}
+	if params.UploadID == "" {
+		return R2ObjectPart{}, ErrMissingR2UploadID
+	}
+	if params.PartNumber < 1 || params.PartNumber > r2MaxParts {
+		return R2ObjectPart{}, ErrInvalidR2PartNumber
+	}
+
+	q := url.Values{
+		"partNumber": {strconv.Itoa(params.PartNumber)},
+		"uploadId":   {params.UploadID},
+	}
+	body := params.Body
+	if body == nil {
+		body = http.NoBody
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, c.objectURL(params.Bucket, params.Key, q), body, params.ContentLength, nil)
+	if err != nil {
+		return R2ObjectPart{}, err
+	}
+	resp.Body.Close()
+
+	return R2ObjectPart{
+		PartNumber: params.PartNumber,
+		ETag:       resp.Header.Get("ETag"),
+		Size:       params.ContentLength,
+	}, nil
+}
+
+// ListParts returns the parts uploaded so far for a multipart upload.
+func (c *R2ObjectClient) ListParts(ctx context.Context, bucket, key, uploadID string) ([]R2ObjectPart, error) {
+	if err := validateR2ObjectLocation(bucket, key); err != nil {
+		return nil, err
+	}
+	if uploadID == "" {
+		return nil, ErrMissingR2UploadID
+	}
+
+	var parts []R2ObjectPart
+	marker := ""
+	for {
+		q := url.Values{"uploadId": {uploadID}}
+		if marker != "" {
+			q.Set("part-number-marker", marker)
+		}
+
+		var res struct {
+			Parts                []R2ObjectPart `xml:"Part"`
+			IsTruncated          bool           `xml:"IsTruncated"`
+			NextPartNumberMarker string         `xml:"NextPartNumberMarker"`
+		}
+		if err := c.doXML(ctx, http.MethodGet, c.objectURL(bucket, key, q), nil, nil, &res); err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, res.Parts...)
+		if !res.IsTruncated || res.NextPartNumberMarker == "" {
+			break
+		}
+		marker = res.NextPartNumberMarker
+	}
+
+	return parts, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the final object
+// and returns its ETag.
+func (c *R2ObjectClient) CompleteMultipartUpload(ctx context.Context, params CompleteR2MultipartUploadParams) (string, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return "", err
+	}
+	if params.UploadID == "" {
+		return "", ErrMissingR2UploadID
+	}
+	if len(params.Parts) == 0 {
+		return "", ErrMissingR2MultipartParts
+	}
+
+	type completedPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	complete := struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{}
+	for _, p := range params.Parts {
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return "", err
+	}
+
+	var res struct {
+		ETag string `xml:"ETag"`
+	}
+	q := url.Values{"uploadId": {params.UploadID}}
+	headers := http.Header{"Content-Type": {"application/xml"}}
+	if err := c.doXML(ctx, http.MethodPost, c.objectURL(params.Bucket, params.Key, q), bytes.NewReader(body), headers, &res); err != nil {
+		return "", err
+	}
+
+	return res.ETag, nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its uploaded parts.
+func (c *R2ObjectClient) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	if err := validateR2ObjectLocation(bucket, key); err != nil {
+		return err
+	}
+	if uploadID == "" {
+		return ErrMissingR2UploadID
+	}
+
+	q := url.Values{"uploadId": {uploadID}}
+	resp, err := c.do(ctx, http.MethodDelete, c.objectURL(bucket, key, q), nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// UploadObject uploads a large object as a multipart upload, sending up to
+// params.Concurrency parts at once. If params.UploadID is set, the upload
+// is resumed and parts already stored are skipped.
+//
+// On failure the upload is left in place so it can be resumed with the
+// returned UploadID, or discarded with AbortMultipartUpload.
+func (c *R2ObjectClient) UploadObject(ctx context.Context, params UploadR2ObjectParams) (R2MultipartUpload, error) {
+	if err := validateR2ObjectLocation(params.Bucket, params.Key); err != nil {
+		return R2MultipartUpload{}, err
+	}
+
+	partSize := params.PartSize
+	if partSize <= 0 {
+		partSize = r2DefaultPartSize
+	}
+
+	partCount := int((params.Size + partSize - 1) / partSize)
+	if partCount == 0 {
+		partCount = 1
+	}
+	if partCount > r2MaxParts {
+		return R2MultipartUpload{}, fmt.Errorf("object needs %d parts with a part size of %d bytes: %w", partCount, partSize, ErrInvalidR2PartNumber)
+	}
+
+	concurrency := params.Concurrency
+	if concurrency < 1 {
+		concurrency = r2DefaultPartConcurrency
+	}
+
+	result := R2MultipartUpload{UploadID: params.UploadID}
+	parts := make([]R2ObjectPart, partCount)
+
+	if result.UploadID == "" {
+		uploadID, err := c.CreateMultipartUpload(ctx, CreateR2MultipartUploadParams{
+			Bucket:      params.Bucket,
+			Key:         params.Key,
+			ContentType: params.ContentType,
+			Metadata:    params.Metadata,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.UploadID = uploadID
+	} else {
+		existing, err := c.ListParts(ctx, params.Bucket, params.Key, result.UploadID)
+		if err != nil {
+			return result, err
+		}
+		for _, p := range existing {
+			if p.PartNumber >= 1 && p.PartNumber <= partCount && p.Size == r2PartLength(p.PartNumber, partSize, params.Size) {
+				parts[p.PartNumber-1] = p
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for i := range parts {
+		if parts[i].ETag != "" {
+			continue
+		}
+
+		partNumber := i + 1
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			length := r2PartLength(partNumber, partSize, params.Size)
+			part, err := c.UploadPart(ctx, UploadR2ObjectPartParams{
+				Bucket:        params.Bucket,
+				Key:           params.Key,
+				UploadID:      result.UploadID,
+				PartNumber:    partNumber,
+				Body:          io.NewSectionReader(params.Body, int64(partNumber-1)*partSize, length),
+				ContentLength: length,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+					cancel()
+				}
+				return
+			}
+			parts[partNumber-1] = part
+			if params.OnPartUploaded != nil {
+				params.OnPartUploaded(part)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return result, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	etag, err := c.CompleteMultipartUpload(ctx, CompleteR2MultipartUploadParams{
+		Bucket:   params.Bucket,
+		Key:      params.Key,
+		UploadID: result.UploadID,
+		Parts:    parts,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ETag = etag
+
+	return result, nil
+}
+
+// r2PartLength returns the length of a part, where only the last part may
+// be shorter than partSize.
+func r2PartLength(partNumber int, partSize, size int64) int64 {
+	start := int64(partNumber-1) * partSize
+	if remaining := size - start; remaining < partSize {
+		return remaining
+	}
+	return partSize
+}
+
+// PresignURL returns a URL granting method access to an object for the
+// given duration without further credentials, for example to let a browser
+// download or upload an object directly.
+func (c *R2ObjectClient) PresignURL(method, bucket, key string, expires time.Duration) (string, error) {
+	if err := validateR2ObjectLocation(bucket, key); err != nil {
+		return "", err
+	}
+	if expires < time.Second || expires > r2MaxPresignDuration {
+		return "", ErrInvalidR2PresignDuration
+	}
+
+	return c.signer.presign(method, c.objectURL(bucket, key, nil), expires, c.now()), nil
+}
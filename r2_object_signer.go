@@ -0,0 +1,180 @@
+package cloudflare
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm       = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat      = "20060102T150405Z"
+	sigV4DateFormat      = "20060102"
+	sigV4UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// sigV4Signer signs requests with AWS Signature Version 4, which is what the
+// S3-compatible R2 endpoint expects.
+type sigV4Signer struct {
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+	service         string
+}
+
+// sign adds the x-amz-date, x-amz-content-sha256 and Authorization headers
+// to req.
+func (s sigV4Signer) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("x-amz-date", t.Format(sigV4TimeFormat))
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders, canonicalHeaders := s.canonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4EscapePath(req.URL.Path),
+		sigV4CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	signature := s.signature(canonicalRequest, t)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.accessKeyID, s.scope(t), signedHeaders, signature))
+}
+
+// presign returns a copy of u carrying the query parameters of a presigned
+// request that is valid for expires.
+func (s sigV4Signer) presign(method string, u *url.URL, expires time.Duration, t time.Time) string {
+	t = t.UTC()
+
+	q := u.Query()
+	q.Set("X-Amz-Algorithm", sigV4Algorithm)
+	q.Set("X-Amz-Credential", s.accessKeyID+"/"+s.scope(t))
+	q.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	q.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		sigV4EscapePath(u.Path),
+		sigV4CanonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		sigV4UnsignedPayload,
+	}, "\n")
+
+	signed := *u
+	signed.RawQuery = sigV4CanonicalQuery(q) + "&X-Amz-Signature=" + s.signature(canonicalRequest, t)
+	return signed.String()
+}
+
+func (s sigV4Signer) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(sigV4DateFormat), s.region, s.service, "aws4_request"}, "/")
+}
+
+func (s sigV4Signer) signature(canonicalRequest string, t time.Time) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		t.Format(sigV4TimeFormat),
+		s.scope(t),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), t.Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalHeaders signs the host, every x-amz-* header and the standard
+// headers S3 requires to be signed when present.
+func (s sigV4Signer) canonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	values := map[string]string{"host": host}
+	for name, v := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" || name == "content-md5" || name == "range" {
+			values[name] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sigV4Escape percent-encodes everything but the unreserved characters, as
+// required by Signature Version 4.
+func sigV4Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sigV4EscapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = sigV4Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, sigV4Escape(k)+"="+sigV4Escape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
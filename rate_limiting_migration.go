@@ -0,0 +1,305 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// rateLimitRulePeriods are the periods rate limiting rules can count
+// requests over, in seconds.
+var rateLimitRulePeriods = []int{10, 60, 120, 300, 600, 3600}
+
+// rateLimitRuleMitigationTimeouts are the durations rate limiting rules
+// can block requests for, in seconds.
+var rateLimitRuleMitigationTimeouts = []int{0, 10, 60, 120, 300, 600, 3600, 86400}
+
+// RateLimitConversionIssue is a part of a rate limit which was dropped or
+// only approximated when converting it to a rate limiting rule.
+type RateLimitConversionIssue struct {
+	RateLimitID string
+	Reason      string
+	// Dropped is set when the rate limit, or part of it, has no
+	// equivalent in rate limiting rules.
+	Dropped bool
+}
+
+// RateLimitsConversion holds the http_ratelimit rules replacing a list of
+// rate limits and the rate limits which didn't convert exactly.
+type RateLimitsConversion struct {
+	Rules  []RulesetRule
+	Issues []RateLimitConversionIssue
+}
+
+// ConvertRateLimits converts rate limits to rules of the http_ratelimit
+// phase, counting requests by IP address and data center as rate limits
+// do. Each rule has the rate limit ID as ref.
+//
+// The URL pattern, schemes and methods of a rate limit give the rule
+// expression, bypassed URLs are excluded from it and response statuses and
+// headers give the counting expression. Periods and mitigation timeouts
+// which rules don't support are rounded up, scaling the threshold so that
+// the rate stays the same.
+func ConvertRateLimits(limits []RateLimit) (RateLimitsConversion, error) {
+	var conversion RateLimitsConversion
+	b := NewRateLimitRulesetBuilder()
+
+	for _, limit := range limits {
+		issue := func(dropped bool, format string, args ...interface{}) {
+			conversion.Issues = append(conversion.Issues, RateLimitConversionIssue{
+				RateLimitID: limit.ID,
+				Reason:      fmt.Sprintf(format, args...),
+				Dropped:     dropped,
+			})
+		}
+
+		if err := convertRateLimit(b, limit, issue); err != nil {
+			issue(true, "%s", err)
+		}
+	}
+
+	rules, err := b.Rules()
+	if err != nil {
+		return RateLimitsConversion{}, err
+	}
+	conversion.Rules = rules
+
+	return conversion, nil
+}
+
+func convertRateLimit(b *RateLimitRulesetBuilder, limit RateLimit, issue func(bool, string, ...interface{})) error {
+	if limit.Period <= 0 || limit.Threshold <= 0 {
+		return fmt.Errorf("invalid threshold of %d requests per %d seconds", limit.Threshold, limit.Period)
+	}
+
+	expression, err := rateLimitExpression(limit, issue)
+	if err != nil {
+		return err
+	}
+
+	rl := RulesetRuleRateLimit{
+		Characteristics:   []string{"ip.src", "cf.colo.id"},
+		Period:            roundUpTo(rateLimitRulePeriods, limit.Period),
+		RequestsPerPeriod: limit.Threshold,
+		RequestsToOrigin:  limit.Match.Response.OriginTraffic == nil || *limit.Match.Response.OriginTraffic,
+	}
+	if rl.Period != limit.Period {
+		rl.RequestsPerPeriod = limit.Threshold * rl.Period / limit.Period
+		if rl.RequestsPerPeriod < 1 {
+			rl.RequestsPerPeriod = 1
+		}
+		issue(false, "%d requests per %d seconds approximated by %d requests per %d seconds",
+			limit.Threshold, limit.Period, rl.RequestsPerPeriod, rl.Period)
+	}
+
+	if counting := rateLimitCountingExpression(limit.Match.Response); counting != "" {
+		rl.CountingExpression = expression + " and " + counting
+	}
+
+	if limit.Correlate != nil && limit.Correlate.By != "" {
+		issue(true, "correlation by %q has no equivalent", limit.Correlate.By)
+	}
+
+	opts := []RulesetRuleOption{WithRulesetRuleRef(limit.ID)}
+	if limit.Description != "" {
+		opts = append(opts, WithRulesetRuleDescription(limit.Description))
+	}
+	if limit.Disabled {
+		opts = append(opts, WithRulesetRuleDisabled())
+	}
+
+	if limit.Action.Mode == "ban" || limit.Action.Mode == "simulate" {
+		rl.MitigationTimeout = roundUpTo(rateLimitRuleMitigationTimeouts, limit.Action.Timeout)
+		if rl.MitigationTimeout != limit.Action.Timeout {
+			issue(false, "timeout of %d seconds rounded up to %d seconds", limit.Action.Timeout, rl.MitigationTimeout)
+		}
+	} else if limit.Action.Timeout > 0 {
+		issue(true, "timeout of %d seconds has no equivalent for %s", limit.Action.Timeout, limit.Action.Mode)
+	}
+
+	switch limit.Action.Mode {
+	case "ban":
+		var response *RulesetRuleActionParametersBlockResponse
+		if limit.Action.Response != nil {
+			response = &RulesetRuleActionParametersBlockResponse{
+				StatusCode:  429,
+				ContentType: limit.Action.Response.ContentType,
+				Content:     limit.Action.Response.Body,
+			}
+		}
+		b.Block(expression, rl, response, opts...)
+	case "simulate":
+		b.Log(expression, rl, opts...)
+	case "challenge":
+		b.Challenge(expression, rl, opts...)
+	case "js_challenge":
+		b.JSChallenge(expression, rl, opts...)
+	case "managed_challenge":
+		b.ManagedChallenge(expression, rl, opts...)
+	default:
+		return fmt.Errorf("action %q has no equivalent", limit.Action.Mode)
+	}
+
+	return nil
+}
+
+// rateLimitExpression returns the expression matching the requests a rate
+// limit applies to.
+func rateLimitExpression(limit RateLimit, issue func(bool, string, ...interface{})) (string, error) {
+	var parts []string
+
+	if pattern := limit.Match.Request.URLPattern; pattern != "" && pattern != "*" {
+		parts = append(parts, "http.request.full_uri wildcard "+quoteExpressionString(fullURIWildcardPattern(pattern)))
+	}
+
+	schemes := limit.Match.Request.Schemes
+	if !contains(schemes, "_ALL_") && len(schemes) == 1 {
+		switch strings.ToUpper(schemes[0]) {
+		case "HTTPS":
+			parts = append(parts, "ssl")
+		case "HTTP":
+			parts = append(parts, "not ssl")
+		default:
+			return "", fmt.Errorf("unknown scheme %q", schemes[0])
+		}
+	}
+
+	methods := limit.Match.Request.Methods
+	if len(methods) > 0 && !contains(methods, "_ALL_") {
+		quoted := make([]string, 0, len(methods))
+		for _, m := range methods {
+			quoted = append(quoted, quoteExpressionString(strings.ToUpper(m)))
+		}
+		parts = append(parts, "http.request.method in {"+strings.Join(quoted, " ")+"}")
+	}
+
+	for _, bypass := range limit.Bypass {
+		if bypass.Name != "url" {
+			issue(true, "bypass of %q has no equivalent", bypass.Name)
+			continue
+		}
+		parts = append(parts, "not http.request.full_uri wildcard "+quoteExpressionString(fullURIWildcardPattern(bypass.Value)))
+	}
+
+	if len(parts) == 0 {
+		return "true", nil
+	}
+	return strings.Join(parts, " and "), nil
+}
+
+// rateLimitCountingExpression returns the expression matching the responses
+// a rate limit counts, or an empty string when it counts every response.
+func rateLimitCountingExpression(m RateLimitResponseMatcher) string {
+	var parts []string
+
+	if len(m.Statuses) > 0 {
+		codes := make([]string, 0, len(m.Statuses))
+		for _, s := range m.Statuses {
+			codes = append(codes, fmt.Sprint(s))
+		}
+		parts = append(parts, "http.response.code in {"+strings.Join(codes, " ")+"}")
+	}
+
+	for _, h := range m.Headers {
+		match := fmt.Sprintf("any(http.response.headers[%s][*] eq %s)",
+			quoteExpressionString(strings.ToLower(h.Name)), quoteExpressionString(h.Value))
+		if h.Op == "ne" {
+			match = "not " + match
+		}
+		parts = append(parts, match)
+	}
+
+	return strings.Join(parts, " and ")
+}
+
+// roundUpTo returns the smallest of values, which are sorted, not less
+// than v, or the largest of values.
+func roundUpTo(values []int, v int) int {
+	for _, allowed := range values {
+		if allowed >= v {
+			return allowed
+		}
+	}
+	return values[len(values)-1]
+}
+
+// RateLimitsMigration is the outcome of MigrateRateLimits.
+type RateLimitsMigration struct {
+	RateLimitsConversion
+	// Diff holds the changes to the http_ratelimit entrypoint ruleset.
+	Diff RulesetDiff
+}
+
+type MigrateRateLimitsParams struct {
+	// DryRun only computes the changes to the entrypoint ruleset.
+	DryRun bool
+}
+
+// MigrateRateLimits converts the rate limits of a zone with
+// ConvertRateLimits and adds the rules to the http_ratelimit entrypoint
+// ruleset of the zone with ApplyEntrypointRulesetChanges, replacing rules
+// converted earlier from the same rate limits. Rate limits are left in
+// place and should be deleted once the rules are checked.
+func (api *API) MigrateRateLimits(ctx context.Context, rc *ResourceContainer, params MigrateRateLimitsParams) (RateLimitsMigration, error) {
+	if rc.Level != ZoneRouteLevel {
+		return RateLimitsMigration{}, fmt.Errorf(errInvalidResourceContainerAccess, rc.Level)
+	}
+
+	limits, err := api.ListAllRateLimits(ctx, rc.Identifier)
+	if err != nil {
+		return RateLimitsMigration{}, err
+	}
+
+	conversion, err := ConvertRateLimits(limits)
+	if err != nil {
+		return RateLimitsMigration{}, err
+	}
+	migration := RateLimitsMigration{RateLimitsConversion: conversion}
+
+	phase := string(RulesetPhaseRateLimit)
+	entrypoint, err := api.GetEntrypointRuleset(ctx, rc, phase)
+	if err != nil {
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			return RateLimitsMigration{}, err
+		}
+	}
+
+	converted := map[string]RulesetRule{}
+	for _, r := range conversion.Rules {
+		converted[r.Ref] = r
+	}
+
+	var rules []RulesetRule
+	for _, r := range entrypoint.Rules {
+		if c, ok := converted[r.Ref]; ok {
+			c.ID = r.ID
+			rules = append(rules, c)
+			delete(converted, r.Ref)
+			continue
+		}
+		rules = append(rules, r)
+	}
+	for _, r := range conversion.Rules {
+		if _, ok := converted[r.Ref]; ok {
+			rules = append(rules, r)
+		}
+	}
+
+	migration.Diff = DiffRulesets(entrypoint, Ruleset{Rules: rules})
+	if params.DryRun || migration.Diff.IsEmpty() {
+		return migration, nil
+	}
+
+	_, err = api.ApplyEntrypointRulesetChanges(ctx, rc, ApplyEntrypointRulesetChangesParams{
+		Phase: phase,
+		Base:  entrypoint,
+		Rules: rules,
+	})
+	if err != nil {
+		return RateLimitsMigration{}, err
+	}
+
+	return migration, nil
+}
@@ -0,0 +1,148 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertRateLimits(t *testing.T) {
+	conversion, err := ConvertRateLimits([]RateLimit{
+		{
+			ID:          "rl1",
+			Description: "login",
+			Match: RateLimitTrafficMatcher{
+				Request: RateLimitRequestMatcher{
+					Methods:    []string{"POST"},
+					Schemes:    []string{"HTTPS"},
+					URLPattern: "*example.com/login*",
+				},
+				Response: RateLimitResponseMatcher{
+					Statuses:      []int{401, 403},
+					OriginTraffic: BoolPtr(false),
+					Headers:       []RateLimitResponseMatcherHeader{{Name: "Cf-Cache-Status", Op: "ne", Value: "HIT"}},
+				},
+			},
+			Bypass:    []RateLimitKeyValue{{Name: "url", Value: "example.com/login/health"}},
+			Threshold: 5,
+			Period:    30,
+			Action: RateLimitAction{
+				Mode:     "ban",
+				Timeout:  90,
+				Response: &RateLimitActionResponse{ContentType: "text/plain", Body: "slow down"},
+			},
+		},
+		{
+			ID:        "rl2",
+			Disabled:  true,
+			Match:     RateLimitTrafficMatcher{Request: RateLimitRequestMatcher{Methods: []string{"_ALL_"}, Schemes: []string{"_ALL_"}, URLPattern: "*"}},
+			Threshold: 100,
+			Period:    60,
+			Action:    RateLimitAction{Mode: "managed_challenge"},
+		},
+		{
+			ID:        "rl3",
+			Match:     RateLimitTrafficMatcher{Request: RateLimitRequestMatcher{URLPattern: "example.com/*"}},
+			Threshold: 10,
+			Period:    60,
+			Action:    RateLimitAction{Mode: "tarpit"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []RulesetRule{
+		{
+			Action:     "block",
+			Expression: `http.request.full_uri wildcard "http*://*example.com/login*" and ssl and http.request.method in {"POST"} and not http.request.full_uri wildcard "http*://example.com/login/health"`,
+			ActionParameters: &RulesetRuleActionParameters{Response: &RulesetRuleActionParametersBlockResponse{
+				StatusCode:  429,
+				ContentType: "text/plain",
+				Content:     "slow down",
+			}},
+			RateLimit: &RulesetRuleRateLimit{
+				Characteristics:   []string{"ip.src", "cf.colo.id"},
+				RequestsPerPeriod: 10,
+				Period:            60,
+				MitigationTimeout: 120,
+				CountingExpression: `http.request.full_uri wildcard "http*://*example.com/login*" and ssl and http.request.method in {"POST"} and not http.request.full_uri wildcard "http*://example.com/login/health"` +
+					` and http.response.code in {401 403} and not any(http.response.headers["cf-cache-status"][*] eq "HIT")`,
+			},
+			Ref:         "rl1",
+			Description: "login",
+		},
+		{
+			Action:     "managed_challenge",
+			Expression: "true",
+			RateLimit: &RulesetRuleRateLimit{
+				Characteristics:   []string{"ip.src", "cf.colo.id"},
+				RequestsPerPeriod: 100,
+				Period:            60,
+				RequestsToOrigin:  true,
+			},
+			Ref:     "rl2",
+			Enabled: BoolPtr(false),
+		},
+	}, conversion.Rules)
+
+	assert.Equal(t, []RateLimitConversionIssue{
+		{RateLimitID: "rl1", Reason: "5 requests per 30 seconds approximated by 10 requests per 60 seconds"},
+		{RateLimitID: "rl1", Reason: "timeout of 90 seconds rounded up to 120 seconds"},
+		{RateLimitID: "rl3", Reason: `action "tarpit" has no equivalent`, Dropped: true},
+	}, conversion.Issues)
+}
+
+func TestMigrateRateLimits_DryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rate_limits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "rl1", "match": {"request": {"url": "example.com/api/*"}}, "threshold": 20, "period": 60, "action": {"mode": "ban", "timeout": 60}},
+				{"id": "rl2", "match": {"request": {"url": "example.com/login"}}, "threshold": 5, "period": 60, "action": {"mode": "challenge"}}
+			],
+			"result_info": {"page": 1, "per_page": 100, "count": 2, "total_count": 2}
+		}`)
+	})
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/phases/http_ratelimit/entrypoint", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "2c0fc9fa937b11eaa1b71c4d701ab86e",
+				"version": "3",
+				"phase": "http_ratelimit",
+				"rules": [{
+					"id": "1",
+					"ref": "rl1",
+					"action": "block",
+					"expression": "http.request.full_uri wildcard \"http*://example.com/api/*\"",
+					"ratelimit": {"characteristics": ["ip.src", "cf.colo.id"], "requests_per_period": 10, "period": 60, "mitigation_timeout": 60, "requests_to_origin": true}
+				}]
+			}
+		}`)
+	})
+
+	migration, err := client.MigrateRateLimits(context.Background(), ZoneIdentifier(testZoneID), MigrateRateLimitsParams{DryRun: true})
+	require.NoError(t, err)
+
+	assert.Len(t, migration.Rules, 2)
+	require.Len(t, migration.Diff.Added, 1)
+	assert.Equal(t, "rl2", migration.Diff.Added[0].Ref)
+	require.Len(t, migration.Diff.Changed, 1)
+	assert.Equal(t, []RulesetFieldChange{{Path: "ratelimit.requests_per_period", Old: float64(10), New: float64(20)}}, migration.Diff.Changed[0].Fields)
+	assert.Empty(t, migration.Diff.Removed)
+}
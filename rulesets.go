@@ -650,6 +650,7 @@ type RulesetRule struct {
 	ActionParameters       *RulesetRuleActionParameters       `json:"action_parameters,omitempty"`
 	Expression             string                             `json:"expression"`
 	Description            string                             `json:"description,omitempty"`
+	Categories             []string                           `json:"categories,omitempty"`
 	LastUpdated            *time.Time                         `json:"last_updated,omitempty"`
 	Ref                    string                             `json:"ref,omitempty"`
 	Enabled                *bool                              `json:"enabled,omitempty"`
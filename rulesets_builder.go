@@ -0,0 +1,741 @@
+package cloudflare
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrUnknownRulesetPhase                  = errors.New("unknown ruleset phase")
+	ErrUnknownRulesetRuleAction             = errors.New("unknown ruleset rule action")
+	ErrRulesetRuleActionNotAllowedInPhase   = errors.New("rule action is not allowed in ruleset phase")
+	ErrInvalidRulesetRuleActionParameters   = errors.New("invalid action parameters for rule action")
+	ErrMissingRulesetRuleActionParameters   = errors.New("required action parameters for rule action missing")
+	ErrMissingRulesetRuleExpression         = errors.New("required rule expression missing")
+	ErrInvalidRulesetRuleRateLimit          = errors.New("invalid rate limit for ruleset phase")
+	ErrDuplicateRulesetRuleRef              = errors.New("duplicate rule ref")
+	ErrInvalidRulesetRuleHeaderOperation    = errors.New("invalid header operation")
+	ErrInvalidRulesetRuleSkipTarget         = errors.New("invalid skip target")
+	ErrMissingRulesetRuleRateLimitThreshold = errors.New("rate limit rules require a period, characteristics and a request or score threshold")
+)
+
+// rulesetPhaseActions are the actions a rule may use in each phase. Phases
+// that aren't listed accept any known action.
+var rulesetPhaseActions = map[RulesetPhase][]RulesetRuleAction{
+	RulesetPhaseHTTPRequestFirewallCustom: {
+		RulesetRuleActionBlock, RulesetRuleActionChallenge, RulesetRuleActionJSChallenge,
+		RulesetRuleActionManagedChallenge, RulesetRuleActionLog, RulesetRuleActionSkip,
+		RulesetRuleActionExecute,
+	},
+	RulesetPhaseHTTPRequestFirewallManaged:  {RulesetRuleActionExecute, RulesetRuleActionSkip, RulesetRuleActionLog},
+	RulesetPhaseHTTPResponseFirewallManaged: {RulesetRuleActionExecute, RulesetRuleActionSkip, RulesetRuleActionLog},
+	RulesetPhaseRateLimit: {
+		RulesetRuleActionBlock, RulesetRuleActionChallenge, RulesetRuleActionJSChallenge,
+		RulesetRuleActionManagedChallenge, RulesetRuleActionLog, RulesetRuleActionExecute,
+	},
+	RulesetPhaseHTTPRequestTransform:         {RulesetRuleActionRewrite},
+	RulesetPhaseHTTPRequestLateTransform:     {RulesetRuleActionRewrite},
+	RulesetPhaseHTTPResponseHeadersTransform: {RulesetRuleActionRewrite},
+	RulesetPhaseHTTPRequestRedirect:          {RulesetRuleActionRedirect},
+	RulesetPhaseHTTPRequestDynamicRedirect:   {RulesetRuleActionRedirect},
+	RulesetPhaseHTTPRequestOrigin:            {RulesetRuleActionRoute},
+	RulesetPhaseHTTPRequestCacheSettings:     {RulesetRuleActionSetCacheSettings},
+	RulesetPhaseHTTPConfigSettings:           {RulesetRuleActionSetConfig},
+	RulesetPhaseHTTPCustomErrors:             {RulesetRuleActionServeError},
+	RulesetPhaseHTTPLogCustomFields:          {RulesetRuleActionLogCustomField},
+	RulesetPhaseHTTPResponseCompression:      {RulesetRuleActionCompressResponse},
+	RulesetPhaseMagicTransit:                 {RulesetRuleActionAllow, RulesetRuleActionBlock, RulesetRuleActionSkip},
+}
+
+// rulesetActionParameters are the JSON names of the action parameters each
+// action accepts. Actions that aren't listed accept no parameters.
+var rulesetActionParameters = map[RulesetRuleAction][]string{
+	RulesetRuleActionBlock:    {"response"},
+	RulesetRuleActionExecute:  {"id", "version", "overrides", "matched_data"},
+	RulesetRuleActionSkip:     {"ruleset", "rulesets", "rules", "phases", "products"},
+	RulesetRuleActionScore:    {"increment"},
+	RulesetRuleActionRewrite:  {"uri", "headers"},
+	RulesetRuleActionRedirect: {"from_list", "from_value"},
+	RulesetRuleActionRoute:    {"host_header", "origin", "sni"},
+	RulesetRuleActionSetCacheSettings: {
+		"cache", "edge_ttl", "browser_ttl", "serve_stale", "respect_strong_etags",
+		"cache_key", "origin_error_page_passthru",
+	},
+	RulesetRuleActionSetConfig: {
+		"automatic_https_rewrites", "autominify", "bic", "disable_apps", "disable_zaraz",
+		"disable_railgun", "email_obfuscation", "mirage", "opportunistic_encryption",
+		"polish", "rocket_loader", "security_level", "server_side_excludes", "ssl", "sxg",
+		"hotlink_protection",
+	},
+	RulesetRuleActionServeError:       {"content", "content_type", "status_code"},
+	RulesetRuleActionLogCustomField:   {"request_fields", "response_fields", "cookie_fields"},
+	RulesetRuleActionCompressResponse: {"algorithms"},
+}
+
+// rulesetActionRequiredParameters lists, for each action, the parameters at
+// least one of which must be set.
+var rulesetActionRequiredParameters = map[RulesetRuleAction][]string{
+	RulesetRuleActionExecute:          {"id"},
+	RulesetRuleActionSkip:             {"ruleset", "rulesets", "rules", "phases", "products"},
+	RulesetRuleActionRewrite:          {"uri", "headers"},
+	RulesetRuleActionRedirect:         {"from_list", "from_value"},
+	RulesetRuleActionRoute:            {"host_header", "origin", "sni"},
+	RulesetRuleActionServeError:       {"content"},
+	RulesetRuleActionLogCustomField:   {"request_fields", "response_fields", "cookie_fields"},
+	RulesetRuleActionCompressResponse: {"algorithms"},
+}
+
+// ValidateRulesetRule checks a rule against the phase of the ruleset it
+// belongs to: the action must be known and allowed in the phase, the
+// expression must be set and the action parameters must fit the action.
+// Nothing is sent to the API.
+func ValidateRulesetRule(phase RulesetPhase, rule RulesetRule) error {
+	if !contains(RulesetPhaseValues(), string(phase)) {
+		return fmt.Errorf("%w: %q", ErrUnknownRulesetPhase, phase)
+	}
+
+	action := RulesetRuleAction(rule.Action)
+	if !contains(RulesetRuleActionValues(), rule.Action) {
+		return fmt.Errorf("%w: %q", ErrUnknownRulesetRuleAction, rule.Action)
+	}
+
+	if allowed, ok := rulesetPhaseActions[phase]; ok && !containsRulesetRuleAction(allowed, action) {
+		return fmt.Errorf("%w: %q in %q", ErrRulesetRuleActionNotAllowedInPhase, action, phase)
+	}
+
+	if strings.TrimSpace(rule.Expression) == "" {
+		return ErrMissingRulesetRuleExpression
+	}
+
+	set := setRulesetRuleActionParameters(rule.ActionParameters)
+	for _, name := range set {
+		if !contains(rulesetActionParameters[action], name) {
+			return fmt.Errorf("%w: %q can't be used with %q", ErrInvalidRulesetRuleActionParameters, name, action)
+		}
+	}
+
+	if required, ok := rulesetActionRequiredParameters[action]; ok {
+		found := false
+		for _, name := range required {
+			if contains(set, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %q requires one of %s", ErrMissingRulesetRuleActionParameters, action, strings.Join(required, ", "))
+		}
+	}
+
+	switch action {
+	case RulesetRuleActionSkip:
+		if err := validateRulesetSkipParameters(rule.ActionParameters); err != nil {
+			return err
+		}
+	case RulesetRuleActionRewrite:
+		for name, header := range rule.ActionParameters.Headers {
+			if err := validateRulesetHeaderOperation(name, header); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateRulesetRuleRateLimit(phase, action, rule.RateLimit)
+}
+
+// ValidateRulesetRules validates every rule of a ruleset with
+// ValidateRulesetRule and checks that rule refs are unique.
+func ValidateRulesetRules(phase RulesetPhase, rules []RulesetRule) error {
+	refs := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if err := ValidateRulesetRule(phase, rule); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+		if rule.Ref == "" {
+			continue
+		}
+		if refs[rule.Ref] {
+			return fmt.Errorf("rule %d: %w: %q", i, ErrDuplicateRulesetRuleRef, rule.Ref)
+		}
+		refs[rule.Ref] = true
+	}
+	return nil
+}
+
+func containsRulesetRuleAction(actions []RulesetRuleAction, action RulesetRuleAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// setRulesetRuleActionParameters returns the JSON names of the action
+// parameters which aren't empty.
+func setRulesetRuleActionParameters(params *RulesetRuleActionParameters) []string {
+	if params == nil {
+		return nil
+	}
+
+	var set []string
+	v := reflect.ValueOf(*params)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if f.IsZero() || ((f.Kind() == reflect.Slice || f.Kind() == reflect.Map) && f.Len() == 0) {
+			continue
+		}
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		set = append(set, name)
+	}
+	return set
+}
+
+func validateRulesetSkipParameters(params *RulesetRuleActionParameters) error {
+	if params.Ruleset != "" && params.Ruleset != "current" {
+		return fmt.Errorf("%w: ruleset must be \"current\", got %q", ErrInvalidRulesetRuleSkipTarget, params.Ruleset)
+	}
+	for _, phase := range params.Phases {
+		if !contains(RulesetPhaseValues(), phase) {
+			return fmt.Errorf("%w: unknown phase %q", ErrInvalidRulesetRuleSkipTarget, phase)
+		}
+	}
+	for _, product := range params.Products {
+		if !contains(RulesetActionParameterProductValues(), product) {
+			return fmt.Errorf("%w: unknown product %q", ErrInvalidRulesetRuleSkipTarget, product)
+		}
+	}
+	return nil
+}
+
+func validateRulesetHeaderOperation(name string, header RulesetRuleActionParametersHTTPHeader) error {
+	if !contains(RulesetRuleActionParametersHTTPHeaderOperationValues(), header.Operation) {
+		return fmt.Errorf("%w: %q for header %q", ErrInvalidRulesetRuleHeaderOperation, header.Operation, name)
+	}
+
+	hasValue := header.Value != "" || header.Expression != ""
+	if header.Operation == string(RulesetRuleActionParametersHTTPHeaderOperationRemove) && hasValue {
+		return fmt.Errorf("%w: remove of header %q can't have a value", ErrInvalidRulesetRuleHeaderOperation, name)
+	}
+	if header.Operation != string(RulesetRuleActionParametersHTTPHeaderOperationRemove) && !hasValue {
+		return fmt.Errorf("%w: %s of header %q requires a value or expression", ErrInvalidRulesetRuleHeaderOperation, header.Operation, name)
+	}
+	return nil
+}
+
+func validateRulesetRuleRateLimit(phase RulesetPhase, action RulesetRuleAction, rl *RulesetRuleRateLimit) error {
+	if phase != RulesetPhaseRateLimit || action == RulesetRuleActionExecute {
+		if rl != nil {
+			return fmt.Errorf("%w: only %q rules can rate limit", ErrInvalidRulesetRuleRateLimit, RulesetPhaseRateLimit)
+		}
+		return nil
+	}
+
+	if rl == nil || rl.Period <= 0 || len(rl.Characteristics) == 0 || (rl.RequestsPerPeriod <= 0 && rl.ScorePerPeriod <= 0) {
+		return ErrMissingRulesetRuleRateLimitThreshold
+	}
+	return nil
+}
+
+// RulesetRuleOption sets optional fields of a rule added to a builder.
+type RulesetRuleOption func(*RulesetRule)
+
+// WithRulesetRuleDescription sets the description of a rule.
+func WithRulesetRuleDescription(description string) RulesetRuleOption {
+	return func(r *RulesetRule) { r.Description = description }
+}
+
+// WithRulesetRuleRef sets the ref of a rule, which stays the same across
+// updates of the ruleset.
+func WithRulesetRuleRef(ref string) RulesetRuleOption {
+	return func(r *RulesetRule) { r.Ref = ref }
+}
+
+// WithRulesetRuleDisabled adds the rule disabled.
+func WithRulesetRuleDisabled() RulesetRuleOption {
+	return func(r *RulesetRule) { r.Enabled = BoolPtr(false) }
+}
+
+// WithRulesetRuleLogging turns logging of rule matches on or off.
+func WithRulesetRuleLogging(enabled bool) RulesetRuleOption {
+	return func(r *RulesetRule) { r.Logging = &RulesetRuleLogging{Enabled: BoolPtr(enabled)} }
+}
+
+// rulesetBuilder holds the rules added by the phase specific builders.
+type rulesetBuilder struct {
+	phase RulesetPhase
+	rules []RulesetRule
+}
+
+func (b *rulesetBuilder) add(action RulesetRuleAction, expression string, params *RulesetRuleActionParameters, rl *RulesetRuleRateLimit, opts []RulesetRuleOption) {
+	rule := RulesetRule{
+		Action:           string(action),
+		Expression:       expression,
+		ActionParameters: params,
+		RateLimit:        rl,
+	}
+	for _, opt := range opts {
+		opt(&rule)
+	}
+	b.rules = append(b.rules, rule)
+}
+
+// Phase returns the phase of the ruleset being built.
+func (b *rulesetBuilder) Phase() RulesetPhase {
+	return b.phase
+}
+
+// Rules validates the rules added so far and returns them in order.
+func (b *rulesetBuilder) Rules() ([]RulesetRule, error) {
+	if err := ValidateRulesetRules(b.phase, b.rules); err != nil {
+		return nil, err
+	}
+	return append([]RulesetRule{}, b.rules...), nil
+}
+
+// CreateRulesetParams validates the rules and returns the parameters to
+// create a ruleset of the given kind with them.
+func (b *rulesetBuilder) CreateRulesetParams(kind RulesetKind, name, description string) (CreateRulesetParams, error) {
+	rules, err := b.Rules()
+	if err != nil {
+		return CreateRulesetParams{}, err
+	}
+	return CreateRulesetParams{
+		Name:        name,
+		Description: description,
+		Kind:        string(kind),
+		Phase:       string(b.phase),
+		Rules:       rules,
+	}, nil
+}
+
+// UpdateEntrypointRulesetParams validates the rules and returns the
+// parameters to replace the entrypoint ruleset of the phase with them.
+func (b *rulesetBuilder) UpdateEntrypointRulesetParams(description string) (UpdateEntrypointRulesetParams, error) {
+	rules, err := b.Rules()
+	if err != nil {
+		return UpdateEntrypointRulesetParams{}, err
+	}
+	return UpdateEntrypointRulesetParams{
+		Phase:       string(b.phase),
+		Description: description,
+		Rules:       rules,
+	}, nil
+}
+
+// RulesetSkipParameters are what a skip rule skips. Ruleset can only be
+// "current", skipping the remaining rules of the ruleset.
+type RulesetSkipParameters struct {
+	Ruleset  string
+	Rulesets []string
+	Rules    map[string][]string
+	Phases   []RulesetPhase
+	Products []RulesetActionParameterProduct
+}
+
+func (p RulesetSkipParameters) actionParameters() *RulesetRuleActionParameters {
+	params := &RulesetRuleActionParameters{
+		Ruleset:  p.Ruleset,
+		Rulesets: p.Rulesets,
+		Rules:    p.Rules,
+	}
+	for _, phase := range p.Phases {
+		params.Phases = append(params.Phases, string(phase))
+	}
+	for _, product := range p.Products {
+		params.Products = append(params.Products, string(product))
+	}
+	return params
+}
+
+// CustomFirewallRulesetBuilder builds rulesets of the
+// http_request_firewall_custom phase.
+type CustomFirewallRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewCustomFirewallRulesetBuilder returns a builder for WAF custom rules.
+func NewCustomFirewallRulesetBuilder() *CustomFirewallRulesetBuilder {
+	return &CustomFirewallRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestFirewallCustom}}
+}
+
+// Block adds a rule blocking matching requests, optionally with a custom
+// response.
+func (b *CustomFirewallRulesetBuilder) Block(expression string, response *RulesetRuleActionParametersBlockResponse, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	var params *RulesetRuleActionParameters
+	if response != nil {
+		params = &RulesetRuleActionParameters{Response: response}
+	}
+	b.add(RulesetRuleActionBlock, expression, params, nil, opts)
+	return b
+}
+
+// Challenge adds a rule presenting an interactive challenge.
+func (b *CustomFirewallRulesetBuilder) Challenge(expression string, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionChallenge, expression, nil, nil, opts)
+	return b
+}
+
+// JSChallenge adds a rule presenting a JavaScript challenge.
+func (b *CustomFirewallRulesetBuilder) JSChallenge(expression string, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionJSChallenge, expression, nil, nil, opts)
+	return b
+}
+
+// ManagedChallenge adds a rule presenting a managed challenge.
+func (b *CustomFirewallRulesetBuilder) ManagedChallenge(expression string, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionManagedChallenge, expression, nil, nil, opts)
+	return b
+}
+
+// Log adds a rule logging matching requests.
+func (b *CustomFirewallRulesetBuilder) Log(expression string, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionLog, expression, nil, nil, opts)
+	return b
+}
+
+// Skip adds a rule skipping rules, phases or products for matching requests.
+func (b *CustomFirewallRulesetBuilder) Skip(expression string, skip RulesetSkipParameters, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionSkip, expression, skip.actionParameters(), nil, opts)
+	return b
+}
+
+// Execute adds a rule executing a custom ruleset, which is only possible in
+// account level rulesets.
+func (b *CustomFirewallRulesetBuilder) Execute(expression, rulesetID string, opts ...RulesetRuleOption) *CustomFirewallRulesetBuilder {
+	b.add(RulesetRuleActionExecute, expression, &RulesetRuleActionParameters{ID: rulesetID}, nil, opts)
+	return b
+}
+
+// ManagedFirewallRulesetBuilder builds rulesets of the
+// http_request_firewall_managed phase.
+type ManagedFirewallRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewManagedFirewallRulesetBuilder returns a builder deploying WAF managed
+// rulesets.
+func NewManagedFirewallRulesetBuilder() *ManagedFirewallRulesetBuilder {
+	return &ManagedFirewallRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestFirewallManaged}}
+}
+
+// Execute adds a rule deploying a managed ruleset with optional overrides.
+func (b *ManagedFirewallRulesetBuilder) Execute(expression, rulesetID string, overrides *RulesetRuleActionParametersOverrides, opts ...RulesetRuleOption) *ManagedFirewallRulesetBuilder {
+	b.add(RulesetRuleActionExecute, expression, &RulesetRuleActionParameters{ID: rulesetID, Overrides: overrides}, nil, opts)
+	return b
+}
+
+// Skip adds a rule skipping managed rules for matching requests.
+func (b *ManagedFirewallRulesetBuilder) Skip(expression string, skip RulesetSkipParameters, opts ...RulesetRuleOption) *ManagedFirewallRulesetBuilder {
+	b.add(RulesetRuleActionSkip, expression, skip.actionParameters(), nil, opts)
+	return b
+}
+
+// RateLimitRulesetBuilder builds rulesets of the http_ratelimit phase. Every
+// rule needs a rate limit.
+type RateLimitRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewRateLimitRulesetBuilder returns a builder for rate limiting rules.
+func NewRateLimitRulesetBuilder() *RateLimitRulesetBuilder {
+	return &RateLimitRulesetBuilder{rulesetBuilder{phase: RulesetPhaseRateLimit}}
+}
+
+// Block adds a rule blocking requests over the rate limit, optionally with
+// a custom response.
+func (b *RateLimitRulesetBuilder) Block(expression string, rl RulesetRuleRateLimit, response *RulesetRuleActionParametersBlockResponse, opts ...RulesetRuleOption) *RateLimitRulesetBuilder {
+	var params *RulesetRuleActionParameters
+	if response != nil {
+		params = &RulesetRuleActionParameters{Response: response}
+	}
+	b.add(RulesetRuleActionBlock, expression, params, &rl, opts)
+	return b
+}
+
+// Challenge adds a rule presenting an interactive challenge to requests over
+// the rate limit.
+func (b *RateLimitRulesetBuilder) Challenge(expression string, rl RulesetRuleRateLimit, opts ...RulesetRuleOption) *RateLimitRulesetBuilder {
+	b.add(RulesetRuleActionChallenge, expression, nil, &rl, opts)
+	return b
+}
+
+// JSChallenge adds a rule presenting a JavaScript challenge to requests over
+// the rate limit.
+func (b *RateLimitRulesetBuilder) JSChallenge(expression string, rl RulesetRuleRateLimit, opts ...RulesetRuleOption) *RateLimitRulesetBuilder {
+	b.add(RulesetRuleActionJSChallenge, expression, nil, &rl, opts)
+	return b
+}
+
+// ManagedChallenge adds a rule presenting a managed challenge to requests
+// over the rate limit.
+func (b *RateLimitRulesetBuilder) ManagedChallenge(expression string, rl RulesetRuleRateLimit, opts ...RulesetRuleOption) *RateLimitRulesetBuilder {
+	b.add(RulesetRuleActionManagedChallenge, expression, nil, &rl, opts)
+	return b
+}
+
+// Log adds a rule logging requests over the rate limit.
+func (b *RateLimitRulesetBuilder) Log(expression string, rl RulesetRuleRateLimit, opts ...RulesetRuleOption) *RateLimitRulesetBuilder {
+	b.add(RulesetRuleActionLog, expression, nil, &rl, opts)
+	return b
+}
+
+// TransformRulesetBuilder builds rulesets of the transform phases.
+type TransformRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewURLRewriteRulesetBuilder returns a builder for URL rewrite rules, in the
+// http_request_transform phase.
+func NewURLRewriteRulesetBuilder() *TransformRulesetBuilder {
+	return &TransformRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestTransform}}
+}
+
+// NewRequestHeaderRulesetBuilder returns a builder for request header
+// modification rules, in the http_request_late_transform phase.
+func NewRequestHeaderRulesetBuilder() *TransformRulesetBuilder {
+	return &TransformRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestLateTransform}}
+}
+
+// NewResponseHeaderRulesetBuilder returns a builder for response header
+// modification rules, in the http_response_headers_transform phase.
+func NewResponseHeaderRulesetBuilder() *TransformRulesetBuilder {
+	return &TransformRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPResponseHeadersTransform}}
+}
+
+// RewriteURI adds a rule rewriting the path and query of matching requests.
+func (b *TransformRulesetBuilder) RewriteURI(expression string, uri RulesetRuleActionParametersURI, opts ...RulesetRuleOption) *TransformRulesetBuilder {
+	b.add(RulesetRuleActionRewrite, expression, &RulesetRuleActionParameters{URI: &uri}, nil, opts)
+	return b
+}
+
+// RewriteHeaders adds a rule setting, adding or removing headers, keyed by
+// header name.
+func (b *TransformRulesetBuilder) RewriteHeaders(expression string, headers map[string]RulesetRuleActionParametersHTTPHeader, opts ...RulesetRuleOption) *TransformRulesetBuilder {
+	b.add(RulesetRuleActionRewrite, expression, &RulesetRuleActionParameters{Headers: headers}, nil, opts)
+	return b
+}
+
+// RedirectRulesetBuilder builds rulesets of the redirect phases.
+type RedirectRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewDynamicRedirectRulesetBuilder returns a builder for single redirects, in
+// the http_request_dynamic_redirect phase.
+func NewDynamicRedirectRulesetBuilder() *RedirectRulesetBuilder {
+	return &RedirectRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestDynamicRedirect}}
+}
+
+// NewBulkRedirectRulesetBuilder returns a builder for bulk redirects using
+// redirect lists, in the http_request_redirect phase.
+func NewBulkRedirectRulesetBuilder() *RedirectRulesetBuilder {
+	return &RedirectRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestRedirect}}
+}
+
+// Redirect adds a rule redirecting matching requests to a target URL.
+func (b *RedirectRulesetBuilder) Redirect(expression string, from RulesetRuleActionParametersFromValue, opts ...RulesetRuleOption) *RedirectRulesetBuilder {
+	b.add(RulesetRuleActionRedirect, expression, &RulesetRuleActionParameters{FromValue: &from}, nil, opts)
+	return b
+}
+
+// RedirectFromList adds a rule redirecting matching requests using the
+// entries of a redirect list.
+func (b *RedirectRulesetBuilder) RedirectFromList(expression string, from RulesetRuleActionParametersFromList, opts ...RulesetRuleOption) *RedirectRulesetBuilder {
+	b.add(RulesetRuleActionRedirect, expression, &RulesetRuleActionParameters{FromList: &from}, nil, opts)
+	return b
+}
+
+// RulesetRouteParameters are the overrides of an origin rule.
+type RulesetRouteParameters struct {
+	HostHeader string
+	Origin     *RulesetRuleActionParametersOrigin
+	SNI        *RulesetRuleActionParametersSni
+}
+
+// OriginRulesetBuilder builds rulesets of the http_request_origin phase.
+type OriginRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewOriginRulesetBuilder returns a builder for origin rules.
+func NewOriginRulesetBuilder() *OriginRulesetBuilder {
+	return &OriginRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestOrigin}}
+}
+
+// Route adds a rule overriding the host header, origin or SNI of matching
+// requests.
+func (b *OriginRulesetBuilder) Route(expression string, route RulesetRouteParameters, opts ...RulesetRuleOption) *OriginRulesetBuilder {
+	b.add(RulesetRuleActionRoute, expression, &RulesetRuleActionParameters{
+		HostHeader: route.HostHeader,
+		Origin:     route.Origin,
+		SNI:        route.SNI,
+	}, nil, opts)
+	return b
+}
+
+// RulesetCacheSettingsParameters are the settings of a cache rule.
+type RulesetCacheSettingsParameters struct {
+	Cache                   *bool
+	EdgeTTL                 *RulesetRuleActionParametersEdgeTTL
+	BrowserTTL              *RulesetRuleActionParametersBrowserTTL
+	ServeStale              *RulesetRuleActionParametersServeStale
+	RespectStrongETags      *bool
+	CacheKey                *RulesetRuleActionParametersCacheKey
+	OriginErrorPagePassthru *bool
+}
+
+// CacheSettingsRulesetBuilder builds rulesets of the
+// http_request_cache_settings phase.
+type CacheSettingsRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewCacheSettingsRulesetBuilder returns a builder for cache rules.
+func NewCacheSettingsRulesetBuilder() *CacheSettingsRulesetBuilder {
+	return &CacheSettingsRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPRequestCacheSettings}}
+}
+
+// SetCacheSettings adds a rule changing how matching requests are cached.
+func (b *CacheSettingsRulesetBuilder) SetCacheSettings(expression string, settings RulesetCacheSettingsParameters, opts ...RulesetRuleOption) *CacheSettingsRulesetBuilder {
+	b.add(RulesetRuleActionSetCacheSettings, expression, &RulesetRuleActionParameters{
+		Cache:                   settings.Cache,
+		EdgeTTL:                 settings.EdgeTTL,
+		BrowserTTL:              settings.BrowserTTL,
+		ServeStale:              settings.ServeStale,
+		RespectStrongETags:      settings.RespectStrongETags,
+		CacheKey:                settings.CacheKey,
+		OriginErrorPagePassthru: settings.OriginErrorPagePassthru,
+	}, nil, opts)
+	return b
+}
+
+// RulesetConfigSettingsParameters are the zone settings a configuration rule
+// overrides.
+type RulesetConfigSettingsParameters struct {
+	AutomaticHTTPSRewrites  *bool
+	AutoMinify              *RulesetRuleActionParametersAutoMinify
+	BrowserIntegrityCheck   *bool
+	DisableApps             *bool
+	DisableZaraz            *bool
+	DisableRailgun          *bool
+	EmailObfuscation        *bool
+	Mirage                  *bool
+	OpportunisticEncryption *bool
+	Polish                  *Polish
+	RocketLoader            *bool
+	SecurityLevel           *SecurityLevel
+	ServerSideExcludes      *bool
+	SSL                     *SSL
+	SXG                     *bool
+	HotLinkProtection       *bool
+}
+
+// ConfigSettingsRulesetBuilder builds rulesets of the http_config_settings
+// phase.
+type ConfigSettingsRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewConfigSettingsRulesetBuilder returns a builder for configuration rules.
+func NewConfigSettingsRulesetBuilder() *ConfigSettingsRulesetBuilder {
+	return &ConfigSettingsRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPConfigSettings}}
+}
+
+// SetConfig adds a rule overriding zone settings for matching requests.
+func (b *ConfigSettingsRulesetBuilder) SetConfig(expression string, settings RulesetConfigSettingsParameters, opts ...RulesetRuleOption) *ConfigSettingsRulesetBuilder {
+	b.add(RulesetRuleActionSetConfig, expression, &RulesetRuleActionParameters{
+		AutomaticHTTPSRewrites:  settings.AutomaticHTTPSRewrites,
+		AutoMinify:              settings.AutoMinify,
+		BrowserIntegrityCheck:   settings.BrowserIntegrityCheck,
+		DisableApps:             settings.DisableApps,
+		DisableZaraz:            settings.DisableZaraz,
+		DisableRailgun:          settings.DisableRailgun,
+		EmailObfuscation:        settings.EmailObfuscation,
+		Mirage:                  settings.Mirage,
+		OpportunisticEncryption: settings.OpportunisticEncryption,
+		Polish:                  settings.Polish,
+		RocketLoader:            settings.RocketLoader,
+		SecurityLevel:           settings.SecurityLevel,
+		ServerSideExcludes:      settings.ServerSideExcludes,
+		SSL:                     settings.SSL,
+		SXG:                     settings.SXG,
+		HotLinkProtection:       settings.HotLinkProtection,
+	}, nil, opts)
+	return b
+}
+
+// CustomErrorsRulesetBuilder builds rulesets of the http_custom_errors phase.
+type CustomErrorsRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewCustomErrorsRulesetBuilder returns a builder for custom error rules.
+func NewCustomErrorsRulesetBuilder() *CustomErrorsRulesetBuilder {
+	return &CustomErrorsRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPCustomErrors}}
+}
+
+// ServeError adds a rule serving content in place of error responses.
+func (b *CustomErrorsRulesetBuilder) ServeError(expression, content, contentType string, statusCode uint16, opts ...RulesetRuleOption) *CustomErrorsRulesetBuilder {
+	b.add(RulesetRuleActionServeError, expression, &RulesetRuleActionParameters{
+		Content:     content,
+		ContentType: contentType,
+		StatusCode:  statusCode,
+	}, nil, opts)
+	return b
+}
+
+// LogCustomFieldsRulesetBuilder builds rulesets of the
+// http_log_custom_fields phase.
+type LogCustomFieldsRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewLogCustomFieldsRulesetBuilder returns a builder for custom log fields.
+func NewLogCustomFieldsRulesetBuilder() *LogCustomFieldsRulesetBuilder {
+	return &LogCustomFieldsRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPLogCustomFields}}
+}
+
+// LogCustomFields adds a rule logging the named request headers, response
+// headers and cookies of matching requests.
+func (b *LogCustomFieldsRulesetBuilder) LogCustomFields(expression string, requestFields, responseFields, cookieFields []string, opts ...RulesetRuleOption) *LogCustomFieldsRulesetBuilder {
+	b.add(RulesetRuleActionLogCustomField, expression, &RulesetRuleActionParameters{
+		RequestFields:  rulesetLogCustomFields(requestFields),
+		ResponseFields: rulesetLogCustomFields(responseFields),
+		CookieFields:   rulesetLogCustomFields(cookieFields),
+	}, nil, opts)
+	return b
+}
+
+func rulesetLogCustomFields(names []string) []RulesetActionParametersLogCustomField {
+	var fields []RulesetActionParametersLogCustomField
+	for _, name := range names {
+		fields = append(fields, RulesetActionParametersLogCustomField{Name: name})
+	}
+	return fields
+}
+
+// CompressionRulesetBuilder builds rulesets of the http_response_compression
+// phase.
+type CompressionRulesetBuilder struct {
+	rulesetBuilder
+}
+
+// NewCompressionRulesetBuilder returns a builder for compression rules.
+func NewCompressionRulesetBuilder() *CompressionRulesetBuilder {
+	return &CompressionRulesetBuilder{rulesetBuilder{phase: RulesetPhaseHTTPResponseCompression}}
+}
+
+// CompressResponse adds a rule compressing matching responses with the first
+// algorithm the client supports, such as "brotli", "gzip", "none" or "auto".
+func (b *CompressionRulesetBuilder) CompressResponse(expression string, algorithms []string, opts ...RulesetRuleOption) *CompressionRulesetBuilder {
+	params := &RulesetRuleActionParameters{}
+	for _, name := range algorithms {
+		params.Algorithms = append(params.Algorithms, RulesetRuleActionParametersCompressionAlgorithm{Name: name})
+	}
+	b.add(RulesetRuleActionCompressResponse, expression, params, nil, opts)
+	return b
+}
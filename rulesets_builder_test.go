@@ -0,0 +1,212 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFirewallRulesetBuilder(t *testing.T) {
+	entrypoint, err := NewCustomFirewallRulesetBuilder().
+		Block(`ip.src eq 192.0.2.1`, &RulesetRuleActionParametersBlockResponse{StatusCode: 403, ContentType: "text/plain", Content: "no"}, WithRulesetRuleRef("block-ip")).
+		ManagedChallenge(`cf.threat_score gt 10`, WithRulesetRuleDescription("challenge threats")).
+		Skip(`http.request.uri.path eq "/health"`, RulesetSkipParameters{Ruleset: "current"}, WithRulesetRuleDisabled()).
+		UpdateEntrypointRulesetParams("custom rules")
+	require.NoError(t, err)
+
+	want := UpdateEntrypointRulesetParams{
+		Phase:       string(RulesetPhaseHTTPRequestFirewallCustom),
+		Description: "custom rules",
+		Rules: []RulesetRule{
+			{
+				Action:     "block",
+				Expression: `ip.src eq 192.0.2.1`,
+				Ref:        "block-ip",
+				ActionParameters: &RulesetRuleActionParameters{
+					Response: &RulesetRuleActionParametersBlockResponse{StatusCode: 403, ContentType: "text/plain", Content: "no"},
+				},
+			},
+			{
+				Action:      "managed_challenge",
+				Expression:  `cf.threat_score gt 10`,
+				Description: "challenge threats",
+			},
+			{
+				Action:           "skip",
+				Expression:       `http.request.uri.path eq "/health"`,
+				ActionParameters: &RulesetRuleActionParameters{Ruleset: "current"},
+				Enabled:          BoolPtr(false),
+			},
+		},
+	}
+	assert.Equal(t, want, entrypoint)
+}
+
+func TestRulesetBuilder_CreateRulesetParams(t *testing.T) {
+	params, err := NewManagedFirewallRulesetBuilder().
+		Execute("true", "efb7b8c949ac4650a09736fc376e9aee", &RulesetRuleActionParametersOverrides{SensitivityLevel: "low"}).
+		CreateRulesetParams(RulesetKindZone, "default", "managed rules")
+	require.NoError(t, err)
+
+	assert.Equal(t, "zone", params.Kind)
+	assert.Equal(t, "http_request_firewall_managed", params.Phase)
+	assert.Equal(t, "default", params.Name)
+	require.Len(t, params.Rules, 1)
+	assert.Equal(t, "efb7b8c949ac4650a09736fc376e9aee", params.Rules[0].ActionParameters.ID)
+}
+
+func TestRateLimitRulesetBuilder(t *testing.T) {
+	rl := RulesetRuleRateLimit{
+		Characteristics:   []string{"cf.colo.id", "ip.src"},
+		RequestsPerPeriod: 100,
+		Period:            60,
+		MitigationTimeout: 600,
+	}
+
+	rules, err := NewRateLimitRulesetBuilder().Block(`http.request.uri.path eq "/login"`, rl, nil).Rules()
+	require.NoError(t, err)
+	assert.Equal(t, &rl, rules[0].RateLimit)
+	assert.Nil(t, rules[0].ActionParameters)
+
+	_, err = NewRateLimitRulesetBuilder().Log("true", RulesetRuleRateLimit{Period: 60}).Rules()
+	assert.ErrorIs(t, err, ErrMissingRulesetRuleRateLimitThreshold)
+}
+
+func TestRulesetBuilder_InvalidRules(t *testing.T) {
+	_, err := NewCustomFirewallRulesetBuilder().Block("", nil).Rules()
+	assert.ErrorIs(t, err, ErrMissingRulesetRuleExpression)
+
+	_, err = NewCustomFirewallRulesetBuilder().Execute("true", "").Rules()
+	assert.ErrorIs(t, err, ErrMissingRulesetRuleActionParameters)
+
+	_, err = NewCustomFirewallRulesetBuilder().
+		Log("true", WithRulesetRuleRef("a")).
+		Log("false", WithRulesetRuleRef("a")).
+		Rules()
+	assert.ErrorIs(t, err, ErrDuplicateRulesetRuleRef)
+	assert.Contains(t, err.Error(), "rule 1")
+
+	_, err = NewCustomFirewallRulesetBuilder().Skip("true", RulesetSkipParameters{Products: []RulesetActionParameterProduct{"nope"}}).Rules()
+	assert.ErrorIs(t, err, ErrInvalidRulesetRuleSkipTarget)
+
+	_, err = NewResponseHeaderRulesetBuilder().RewriteHeaders("true", map[string]RulesetRuleActionParametersHTTPHeader{
+		"X-Foo": {Operation: "set"},
+	}).Rules()
+	assert.ErrorIs(t, err, ErrInvalidRulesetRuleHeaderOperation)
+}
+
+func TestValidateRulesetRule(t *testing.T) {
+	testCases := map[string]struct {
+		phase RulesetPhase
+		rule  RulesetRule
+		err   error
+	}{
+		"valid rewrite": {
+			phase: RulesetPhaseHTTPRequestTransform,
+			rule: RulesetRule{
+				Action:     "rewrite",
+				Expression: "true",
+				ActionParameters: &RulesetRuleActionParameters{
+					URI: &RulesetRuleActionParametersURI{Path: &RulesetRuleActionParametersURIPath{Value: "/"}},
+				},
+			},
+		},
+		"managed list": {
+			phase: RulesetPhaseHTTPRequestFirewallCustom,
+			rule:  RulesetRule{Action: "block", Expression: "ip.src in $cf.open_proxies"},
+		},
+		"unparsed expression is left to the API": {
+			phase: RulesetPhaseHTTPRequestFirewallCustom,
+			rule:  RulesetRule{Action: "block", Expression: "http.host eq \"a\" foo"},
+		},
+		"unknown phase": {
+			phase: "http_request_nope",
+			rule:  RulesetRule{Action: "block", Expression: "true"},
+			err:   ErrUnknownRulesetPhase,
+		},
+		"unknown action": {
+			phase: RulesetPhaseHTTPRequestFirewallCustom,
+			rule:  RulesetRule{Action: "explode", Expression: "true"},
+			err:   ErrUnknownRulesetRuleAction,
+		},
+		"action not allowed in phase": {
+			phase: RulesetPhaseHTTPRequestOrigin,
+			rule:  RulesetRule{Action: "block", Expression: "true"},
+			err:   ErrRulesetRuleActionNotAllowedInPhase,
+		},
+		"parameter not allowed for action": {
+			phase: RulesetPhaseHTTPRequestFirewallCustom,
+			rule: RulesetRule{
+				Action:           "block",
+				Expression:       "true",
+				ActionParameters: &RulesetRuleActionParameters{HostHeader: "example.com"},
+			},
+			err: ErrInvalidRulesetRuleActionParameters,
+		},
+		"missing redirect target": {
+			phase: RulesetPhaseHTTPRequestDynamicRedirect,
+			rule:  RulesetRule{Action: "redirect", Expression: "true", ActionParameters: &RulesetRuleActionParameters{}},
+			err:   ErrMissingRulesetRuleActionParameters,
+		},
+		"rate limit outside ratelimit phase": {
+			phase: RulesetPhaseHTTPRequestFirewallCustom,
+			rule:  RulesetRule{Action: "block", Expression: "true", RateLimit: &RulesetRuleRateLimit{Period: 60}},
+			err:   ErrInvalidRulesetRuleRateLimit,
+		},
+		"unlisted phase accepts known actions": {
+			phase: RulesetPhaseDDoSL7,
+			rule:  RulesetRule{Action: "ddos_dynamic", Expression: "true"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRulesetRule(tc.phase, tc.rule)
+			if tc.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestPhaseRulesetBuilders(t *testing.T) {
+	on := BoolPtr(true)
+
+	builders := map[RulesetPhase]interface {
+		Rules() ([]RulesetRule, error)
+		Phase() RulesetPhase
+	}{
+		RulesetPhaseHTTPRequestTransform: NewURLRewriteRulesetBuilder().
+			RewriteURI("true", RulesetRuleActionParametersURI{Path: &RulesetRuleActionParametersURIPath{Value: "/new"}}),
+		RulesetPhaseHTTPRequestLateTransform: NewRequestHeaderRulesetBuilder().
+			RewriteHeaders("true", map[string]RulesetRuleActionParametersHTTPHeader{"X-Foo": {Operation: "remove"}}),
+		RulesetPhaseHTTPRequestDynamicRedirect: NewDynamicRedirectRulesetBuilder().
+			Redirect("true", RulesetRuleActionParametersFromValue{StatusCode: 301, TargetURL: RulesetRuleActionParametersTargetURL{Value: "https://example.com"}}),
+		RulesetPhaseHTTPRequestRedirect: NewBulkRedirectRulesetBuilder().
+			RedirectFromList("true", RulesetRuleActionParametersFromList{Name: "redirects", Key: "http.request.full_uri"}),
+		RulesetPhaseHTTPRequestOrigin: NewOriginRulesetBuilder().
+			Route("true", RulesetRouteParameters{HostHeader: "origin.example.com"}),
+		RulesetPhaseHTTPRequestCacheSettings: NewCacheSettingsRulesetBuilder().
+			SetCacheSettings("true", RulesetCacheSettingsParameters{Cache: on}),
+		RulesetPhaseHTTPConfigSettings: NewConfigSettingsRulesetBuilder().
+			SetConfig("true", RulesetConfigSettingsParameters{RocketLoader: on, SSL: SSLStrict.IntoRef()}),
+		RulesetPhaseHTTPCustomErrors: NewCustomErrorsRulesetBuilder().
+			ServeError("http.response.code eq 500", "oops", "text/plain", 500),
+		RulesetPhaseHTTPLogCustomFields: NewLogCustomFieldsRulesetBuilder().
+			LogCustomFields("true", []string{"cf-ray"}, nil, []string{"session"}),
+		RulesetPhaseHTTPResponseCompression: NewCompressionRulesetBuilder().
+			CompressResponse("true", []string{"brotli", "gzip"}),
+	}
+
+	for phase, b := range builders {
+		t.Run(string(phase), func(t *testing.T) {
+			assert.Equal(t, phase, b.Phase())
+			rules, err := b.Rules()
+			require.NoError(t, err)
+			assert.Len(t, rules, 1)
+		})
+	}
+}
@@ -0,0 +1,440 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// ErrRulesetConflict is wrapped by RulesetConflictError.
+var ErrRulesetConflict = errors.New("ruleset was changed concurrently")
+
+// RulesetFieldChange is a change of a single field. Path is the JSON path of
+// the field within the rule, such as "action_parameters.uri.path.value".
+// Old or New is nil when the field was added or removed.
+type RulesetFieldChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// RulesetRuleChange is a rule present in both rulesets with different fields.
+type RulesetRuleChange struct {
+	// Key is the ref of the rule, or its ID when it has no ref.
+	Key    string
+	Old    RulesetRule
+	New    RulesetRule
+	Fields []RulesetFieldChange
+}
+
+// RulesetDiff holds the differences between the rules of two rulesets.
+// Rules are matched by Ref, or by ID when they have no ref.
+type RulesetDiff struct {
+	Added   []RulesetRule
+	Removed []RulesetRule
+	Changed []RulesetRuleChange
+	// Reordered is set when rules present in both rulesets are in a
+	// different order.
+	Reordered bool
+}
+
+// IsEmpty reports whether the rulesets have the same rules.
+func (d RulesetDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && !d.Reordered
+}
+
+// keys returns the keys of every added, removed and changed rule.
+func (d RulesetDiff) keys() map[string]bool {
+	keys := map[string]bool{}
+	for _, r := range append(append([]RulesetRule{}, d.Added...), d.Removed...) {
+		if k := rulesetRuleKey(r); k != "" {
+			keys[k] = true
+		}
+	}
+	for _, c := range d.Changed {
+		keys[c.Key] = true
+	}
+	return keys
+}
+
+// String describes the differences, one rule per line with the changed
+// fields of each changed rule indented below it.
+func (d RulesetDiff) String() string {
+	var b strings.Builder
+	describe := func(r RulesetRule) string {
+		key := rulesetRuleKey(r)
+		if key == "" {
+			key = "(new rule)"
+		}
+		return fmt.Sprintf("%s: %s if %s", key, r.Action, r.Expression)
+	}
+
+	for _, r := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", describe(r))
+	}
+	for _, r := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", describe(r))
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n", c.Key)
+		for _, f := range c.Fields {
+			fmt.Fprintf(&b, "    %s: %s -> %s\n", f.Path, formatRulesetValue(f.Old), formatRulesetValue(f.New))
+		}
+	}
+	if d.Reordered {
+		b.WriteString("rules reordered\n")
+	}
+	return b.String()
+}
+
+func formatRulesetValue(v interface{}) string {
+	if v == nil {
+		return "(unset)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func rulesetRuleKey(r RulesetRule) string {
+	if r.Ref != "" {
+		return r.Ref
+	}
+	return r.ID
+}
+
+// DiffRulesets compares the rules of two rulesets. Fields managed by the
+// API, such as the rule version and last update time, are ignored.
+func DiffRulesets(old, new Ruleset) RulesetDiff {
+	var d RulesetDiff
+
+	oldByKey := map[string]RulesetRule{}
+	var oldOrder []string
+	for _, r := range old.Rules {
+		key := rulesetRuleKey(r)
+		if key == "" {
+			d.Removed = append(d.Removed, r)
+			continue
+		}
+		oldByKey[key] = r
+		oldOrder = append(oldOrder, key)
+	}
+
+	seen := map[string]bool{}
+	var newOrder []string
+	for _, r := range new.Rules {
+		key := rulesetRuleKey(r)
+		o, ok := oldByKey[key]
+		if key == "" || !ok {
+			d.Added = append(d.Added, r)
+			continue
+		}
+		seen[key] = true
+		newOrder = append(newOrder, key)
+
+		if fields := diffRulesetRules(o, r); len(fields) > 0 {
+			d.Changed = append(d.Changed, RulesetRuleChange{Key: key, Old: o, New: r, Fields: fields})
+		}
+	}
+
+	var kept []string
+	for _, key := range oldOrder {
+		if seen[key] {
+			kept = append(kept, key)
+		} else {
+			d.Removed = append(d.Removed, oldByKey[key])
+		}
+	}
+	d.Reordered = !reflect.DeepEqual(kept, newOrder)
+
+	return d
+}
+
+func diffRulesetRules(old, new RulesetRule) []RulesetFieldChange {
+	var changes []RulesetFieldChange
+	diffRulesetValues("", rulesetRuleFields(old), rulesetRuleFields(new), &changes)
+	return changes
+}
+
+// rulesetRuleFields returns the JSON representation of a rule without the
+// fields managed by the API.
+func rulesetRuleFields(r RulesetRule) map[string]interface{} {
+	r.ID = ""
+	r.Version = nil
+	r.LastUpdated = nil
+
+	var fields map[string]interface{}
+	b, _ := json.Marshal(r)
+	_ = json.Unmarshal(b, &fields)
+	return fields
+}
+
+func diffRulesetValues(path string, old, new interface{}, changes *[]RulesetFieldChange) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch o := old.(type) {
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for k := range o {
+			keys[k] = true
+		}
+		for k := range n {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffRulesetValues(join(k), o[k], n[k], changes)
+		}
+		return
+
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok || len(n) != len(o) {
+			break
+		}
+		for i := range o {
+			diffRulesetValues(fmt.Sprintf("%s[%d]", path, i), o[i], n[i], changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, RulesetFieldChange{Path: path, Old: old, New: new})
+	}
+}
+
+// RulesetConflictError is returned by ApplyEntrypointRulesetChanges when
+// rules it would change were also changed since the base ruleset was
+// fetched.
+type RulesetConflictError struct {
+	Phase string
+	// Keys are the refs or IDs of the rules changed by both sides.
+	Keys           []string
+	BaseVersion    string
+	CurrentVersion string
+}
+
+func (e *RulesetConflictError) Error() string {
+	return fmt.Sprintf("%s: %s entrypoint changed from version %s to %s, conflicting rules: %s",
+		ErrRulesetConflict, e.Phase, e.BaseVersion, e.CurrentVersion, strings.Join(e.Keys, ", "))
+}
+
+func (e *RulesetConflictError) Unwrap() error {
+	return ErrRulesetConflict
+}
+
+type ApplyEntrypointRulesetChangesParams struct {
+	Phase string
+	// Base is the entrypoint ruleset the changes were made against, as
+	// returned by GetEntrypointRuleset. It is empty when the entrypoint
+	// didn't exist.
+	Base Ruleset
+	// Rules are the rules the entrypoint should have.
+	Rules []RulesetRule
+}
+
+// ApplyEntrypointRulesetChanges updates the entrypoint ruleset of a phase
+// from Base to Rules without clobbering concurrent edits. The entrypoint is
+// fetched again; when its version differs from Base, the changes made since
+// are compared with the intended ones and a *RulesetConflictError is
+// returned if both touch the same rules, or if rules kept from Base were
+// deleted. Otherwise only the intended changes are applied, one rule at a
+// time, on top of the current rules.
+// Rules are only moved when added; reordering existing rules and
+// description changes are not applied.
+func (api *API) ApplyEntrypointRulesetChanges(ctx context.Context, rc *ResourceContainer, params ApplyEntrypointRulesetChangesParams) (Ruleset, error) {
+	if params.Phase == "" {
+		return Ruleset{}, ErrMissingRulesetPhase
+	}
+
+	current, err := api.GetEntrypointRuleset(ctx, rc, params.Phase)
+	if err != nil {
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) || params.Base.ID != "" {
+			return Ruleset{}, err
+		}
+		return api.UpdateEntrypointRuleset(ctx, rc, UpdateEntrypointRulesetParams{
+			Phase:       params.Phase,
+			Description: params.Base.Description,
+			Rules:       params.Rules,
+		})
+	}
+
+	intended := DiffRulesets(params.Base, Ruleset{Rules: params.Rules})
+
+	baseVersion, currentVersion := rulesetVersion(params.Base), rulesetVersion(current)
+	if baseVersion != currentVersion {
+		concurrent := DiffRulesets(params.Base, current).keys()
+		conflicting := map[string]bool{}
+		for key := range intended.keys() {
+			if concurrent[key] {
+				conflicting[key] = true
+			}
+		}
+
+		// Rules kept from Base but deleted since would otherwise be
+		// created again.
+		baseKeys, currentKeys := rulesetRuleKeys(params.Base.Rules), rulesetRuleKeys(current.Rules)
+		for _, r := range params.Rules {
+			if key := rulesetRuleKey(r); baseKeys[key] && !currentKeys[key] {
+				conflicting[key] = true
+			}
+		}
+
+		var conflicts []string
+		for key := range conflicting {
+			conflicts = append(conflicts, key)
+		}
+		if len(conflicts) > 0 {
+			sort.Strings(conflicts)
+			return Ruleset{}, &RulesetConflictError{
+				Phase:          params.Phase,
+				Keys:           conflicts,
+				BaseVersion:    baseVersion,
+				CurrentVersion: currentVersion,
+			}
+		}
+	}
+
+	return api.applyRulesetDiff(ctx, rc, current, intended, params.Rules)
+}
+
+func rulesetRuleKeys(rules []RulesetRule) map[string]bool {
+	keys := map[string]bool{}
+	for _, r := range rules {
+		if key := rulesetRuleKey(r); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+func rulesetVersion(r Ruleset) string {
+	if r.Version == nil {
+		return ""
+	}
+	return *r.Version
+}
+
+// applyRulesetDiff applies d to current using the rule endpoints. rules is
+// the intended list of rules, used to position added rules.
+func (api *API) applyRulesetDiff(ctx context.Context, rc *ResourceContainer, current Ruleset, d RulesetDiff, rules []RulesetRule) (Ruleset, error) {
+	currentByKey := map[string]RulesetRule{}
+	for _, r := range current.Rules {
+		if key := rulesetRuleKey(r); key != "" {
+			currentByKey[key] = r
+		}
+	}
+
+	result := current
+	var err error
+
+	for _, r := range d.Removed {
+		cur, ok := currentByKey[rulesetRuleKey(r)]
+		if !ok {
+			continue
+		}
+		result, err = api.DeleteRulesetRule(ctx, rc, DeleteRulesetRuleParams{RulesetID: current.ID, RuleID: cur.ID})
+		if err != nil {
+			return Ruleset{}, err
+		}
+	}
+
+	for _, c := range d.Changed {
+		cur, ok := currentByKey[c.Key]
+		if !ok {
+			continue
+		}
+		rule := c.New
+		rule.ID = cur.ID
+		result, err = api.UpdateRulesetRule(ctx, rc, UpdateRulesetRuleParams{RulesetID: current.ID, Rule: rule})
+		if err != nil {
+			return Ruleset{}, err
+		}
+	}
+
+	// Only rules added by d are created: a rule missing from current which
+	// d doesn't add was deleted concurrently.
+	addedKeys := rulesetRuleKeys(d.Added)
+	added := map[int]bool{}
+	for i, r := range rules {
+		if key := rulesetRuleKey(r); key == "" || addedKeys[key] {
+			added[i] = true
+		}
+	}
+
+	// ids holds the IDs of the intended rules which exist so far.
+	ids := make([]string, len(rules))
+	for i, r := range rules {
+		if !added[i] {
+			ids[i] = currentByKey[rulesetRuleKey(r)].ID
+		}
+	}
+
+	for i, r := range rules {
+		if !added[i] {
+			continue
+		}
+
+		rule := r
+		rule.ID = ""
+		before := map[string]bool{}
+		for _, existing := range result.Rules {
+			before[existing.ID] = true
+		}
+
+		result, err = api.CreateRulesetRule(ctx, rc, CreateRulesetRuleParams{
+			RulesetID: current.ID,
+			Position:  rulesetRulePositionAt(ids, i),
+			Rule:      rule,
+		})
+		if err != nil {
+			return Ruleset{}, err
+		}
+
+		for _, created := range result.Rules {
+			if !before[created.ID] {
+				ids[i] = created.ID
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// rulesetRulePositionAt places the rule at index i after the closest
+// existing rule before it, or else before the closest existing rule after it.
+func rulesetRulePositionAt(ids []string, i int) *RulesetRulePosition {
+	for j := i - 1; j >= 0; j-- {
+		if ids[j] != "" {
+			return &RulesetRulePosition{After: ids[j]}
+		}
+	}
+	for j := i + 1; j < len(ids); j++ {
+		if ids[j] != "" {
+			return &RulesetRulePosition{Before: ids[j]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRulesets(t *testing.T) {
+	old := Ruleset{Rules: []RulesetRule{
+		{ID: "1", Ref: "a", Action: "block", Expression: "ip.src eq 192.0.2.1", Version: StringPtr("1")},
+		{ID: "2", Ref: "b", Action: "rewrite", Expression: "true", ActionParameters: &RulesetRuleActionParameters{
+			URI: &RulesetRuleActionParametersURI{Path: &RulesetRuleActionParametersURIPath{Value: "/old"}},
+		}},
+		{ID: "3", Action: "log", Expression: "true"},
+	}}
+	new := Ruleset{Rules: []RulesetRule{
+		{ID: "2", Ref: "b", Action: "rewrite", Expression: "true", ActionParameters: &RulesetRuleActionParameters{
+			URI: &RulesetRuleActionParametersURI{Path: &RulesetRuleActionParametersURIPath{Value: "/new"}},
+		}},
+		{ID: "1", Ref: "a", Action: "block", Expression: "ip.src eq 192.0.2.1", Version: StringPtr("2")},
+		{Ref: "c", Action: "log", Expression: "ssl"},
+	}}
+
+	d := DiffRulesets(old, new)
+
+	require.Len(t, d.Added, 1)
+	assert.Equal(t, "c", d.Added[0].Ref)
+	require.Len(t, d.Removed, 1)
+	assert.Equal(t, "3", d.Removed[0].ID)
+	require.Len(t, d.Changed, 1)
+	assert.Equal(t, "b", d.Changed[0].Key)
+	assert.Equal(t, []RulesetFieldChange{{Path: "action_parameters.uri.path.value", Old: "/old", New: "/new"}}, d.Changed[0].Fields)
+	assert.True(t, d.Reordered)
+	assert.False(t, d.IsEmpty())
+
+	assert.Equal(t, `- 3: log if true
++ c: log if ssl
+~ b
+    action_parameters.uri.path.value: "/old" -> "/new"
+rules reordered
+`, d.String())
+
+	assert.True(t, DiffRulesets(old, old).IsEmpty())
+}
+
+// testEntrypointRuleset serves an entrypoint ruleset and the rule endpoints,
+// recording the calls made.
+func testEntrypointRuleset(t *testing.T, current Ruleset) *[]string {
+	var calls []string
+
+	respond := func(w http.ResponseWriter) {
+		w.Header().Set("content-type", "application/json")
+		b, _ := json.Marshal(current)
+		fmt.Fprintf(w, `{"result": %s, "success": true, "errors": [], "messages": []}`, b)
+	}
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/phases/http_request_firewall_custom/entrypoint", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		respond(w)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/"+testRulesetID+"/rules", func(w http.ResponseWriter, r *http.Request) {
+		var req rulesetRuleRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls = append(calls, fmt.Sprintf("create %s after %s", req.Ref, req.Position.After))
+		req.RulesetRule.ID = "new-" + req.Ref
+		current.Rules = append(current.Rules, req.RulesetRule)
+		respond(w)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/"+testRulesetID+"/rules/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path[len("/zones/"+testZoneID+"/rulesets/"+testRulesetID+"/rules/"):])
+		respond(w)
+	})
+
+	return &calls
+}
+
+func TestApplyEntrypointRulesetChanges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	base := Ruleset{ID: testRulesetID, Version: StringPtr("1"), Rules: []RulesetRule{
+		{ID: "1", Ref: "a", Action: "block", Expression: "ip.src eq 192.0.2.1"},
+		{ID: "2", Ref: "b", Action: "log", Expression: "true"},
+		{ID: "3", Ref: "c", Action: "log", Expression: "ssl"},
+	}}
+
+	// Someone else changed rule c since base was fetched.
+	current := base
+	current.Version = StringPtr("2")
+	current.Rules = []RulesetRule{base.Rules[0], base.Rules[1], {ID: "3", Ref: "c", Action: "block", Expression: "ssl"}}
+	calls := testEntrypointRuleset(t, current)
+
+	_, err := client.ApplyEntrypointRulesetChanges(context.Background(), ZoneIdentifier(testZoneID), ApplyEntrypointRulesetChangesParams{
+		Phase: string(RulesetPhaseHTTPRequestFirewallCustom),
+		Base:  base,
+		Rules: []RulesetRule{
+			{ID: "1", Ref: "a", Action: "managed_challenge", Expression: "ip.src eq 192.0.2.1"},
+			{Ref: "d", Action: "log", Expression: "http.host eq \"a\""},
+			base.Rules[2],
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DELETE 2", "PATCH 1", "create d after 1"}, *calls)
+}
+
+func TestApplyEntrypointRulesetChanges_Conflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	base := Ruleset{ID: testRulesetID, Version: StringPtr("1"), Rules: []RulesetRule{
+		{ID: "1", Ref: "a", Action: "block", Expression: "ip.src eq 192.0.2.1"},
+	}}
+	current := base
+	current.Version = StringPtr("2")
+	current.Rules = []RulesetRule{{ID: "1", Ref: "a", Action: "log", Expression: "ip.src eq 192.0.2.1"}}
+	calls := testEntrypointRuleset(t, current)
+
+	_, err := client.ApplyEntrypointRulesetChanges(context.Background(), ZoneIdentifier(testZoneID), ApplyEntrypointRulesetChangesParams{
+		Phase: string(RulesetPhaseHTTPRequestFirewallCustom),
+		Base:  base,
+		Rules: []RulesetRule{{ID: "1", Ref: "a", Action: "managed_challenge", Expression: "ip.src eq 192.0.2.1"}},
+	})
+
+	var conflict *RulesetConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.ErrorIs(t, err, ErrRulesetConflict)
+	assert.Equal(t, []string{"a"}, conflict.Keys)
+	assert.Equal(t, "2", conflict.CurrentVersion)
+	assert.Empty(t, *calls)
+}
+
+func TestApplyEntrypointRulesetChanges_ConcurrentDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	base := Ruleset{ID: testRulesetID, Version: StringPtr("1"), Rules: []RulesetRule{
+		{ID: "1", Ref: "a", Action: "block", Expression: "ip.src eq 192.0.2.1"},
+		{ID: "2", Ref: "b", Action: "log", Expression: "true"},
+	}}
+
+	// Someone else deleted rule b, which the changes leave unchanged.
+	current := base
+	current.Version = StringPtr("2")
+	current.Rules = base.Rules[:1]
+	calls := testEntrypointRuleset(t, current)
+
+	_, err := client.ApplyEntrypointRulesetChanges(context.Background(), ZoneIdentifier(testZoneID), ApplyEntrypointRulesetChangesParams{
+		Phase: string(RulesetPhaseHTTPRequestFirewallCustom),
+		Base:  base,
+		Rules: []RulesetRule{
+			{ID: "1", Ref: "a", Action: "managed_challenge", Expression: "ip.src eq 192.0.2.1"},
+			base.Rules[1],
+		},
+	})
+
+	var conflict *RulesetConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"b"}, conflict.Keys)
+	assert.Empty(t, *calls)
+}
@@ -0,0 +1,303 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownManagedRule             = errors.New("rule not found in managed ruleset")
+	ErrUnknownManagedRuleCategory     = errors.New("category not found in managed ruleset")
+	ErrEmptyManagedRuleSelection      = errors.New("selection matches no managed rule")
+	ErrInvalidManagedRulesetOverrides = errors.New("invalid managed ruleset override")
+)
+
+// managedRuleOverrideActions are the actions managed rules can be
+// overridden with.
+var managedRuleOverrideActions = []RulesetRuleAction{
+	RulesetRuleActionBlock, RulesetRuleActionChallenge, RulesetRuleActionJSChallenge,
+	RulesetRuleActionManagedChallenge, RulesetRuleActionLog, RulesetRuleActionScore,
+	RulesetRuleActionDDoSDynamic, RulesetRuleActionForceConnectionClose,
+}
+
+// managedRuleSensitivityLevels are the sensitivity levels of managed
+// rules, from the most to the least sensitive.
+var managedRuleSensitivityLevels = []string{"default", "medium", "low", "eoff"}
+
+// ManagedRuleSelector selects rules of a managed ruleset. Rules must match
+// every criteria set.
+type ManagedRuleSelector struct {
+	// IDs selects the rules with any of the IDs.
+	IDs []string
+	// Categories selects the rules tagged with any of the categories.
+	Categories []string
+	// Description selects the rules whose description contains it,
+	// ignoring case.
+	Description string
+}
+
+// ManagedRuleOverride is an override of a managed ruleset, of some of its
+// categories or of some of its rules.
+type ManagedRuleOverride struct {
+	Action  RulesetRuleAction
+	Enabled *bool
+	// SensitivityLevel is one of default, medium, low or eoff. It can't
+	// be set on categories.
+	SensitivityLevel string
+	// ScoreThreshold can only be set on rules having one.
+	ScoreThreshold int
+}
+
+// ManagedRulesetOverridesBuilder builds the overrides of a managed ruleset,
+// checking them against the rules and categories of the ruleset.
+//
+// The first invalid override is reported by Overrides and ExecuteRule;
+// later calls are ignored.
+type ManagedRulesetOverridesBuilder struct {
+	ruleset    Ruleset
+	categories map[string]bool
+	overrides  RulesetRuleActionParametersOverrides
+	err        error
+}
+
+// NewManagedRulesetOverridesBuilder returns a builder of overrides of the
+// managed ruleset, which must hold its rules.
+func NewManagedRulesetOverridesBuilder(ruleset Ruleset) *ManagedRulesetOverridesBuilder {
+	b := &ManagedRulesetOverridesBuilder{ruleset: ruleset, categories: map[string]bool{}}
+	for _, r := range ruleset.Rules {
+		for _, c := range r.Categories {
+			b.categories[c] = true
+		}
+	}
+	return b
+}
+
+// GetManagedRulesetOverridesBuilder fetches a managed ruleset with GetRuleset
+// and returns a builder of its overrides.
+func (api *API) GetManagedRulesetOverridesBuilder(ctx context.Context, rc *ResourceContainer, rulesetID string) (*ManagedRulesetOverridesBuilder, error) {
+	ruleset, err := api.GetRuleset(ctx, rc, rulesetID)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagedRulesetOverridesBuilder(ruleset), nil
+}
+
+// Categories returns the categories of the rules of the managed ruleset,
+// sorted.
+func (b *ManagedRulesetOverridesBuilder) Categories() []string {
+	categories := make([]string, 0, len(b.categories))
+	for c := range b.categories {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Select returns the rules of the managed ruleset matching the selector.
+func (b *ManagedRulesetOverridesBuilder) Select(selector ManagedRuleSelector) ([]RulesetRule, error) {
+	if len(selector.IDs) == 0 && len(selector.Categories) == 0 && selector.Description == "" {
+		return nil, fmt.Errorf("%w: empty selector", ErrInvalidManagedRulesetOverrides)
+	}
+
+	ids := map[string]bool{}
+	for _, id := range selector.IDs {
+		ids[id] = true
+	}
+	seen := map[string]bool{}
+	for _, c := range selector.Categories {
+		if !b.categories[c] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownManagedRuleCategory, c)
+		}
+	}
+	description := strings.ToLower(selector.Description)
+
+	var selected []RulesetRule
+	for _, r := range b.ruleset.Rules {
+		if len(ids) > 0 && !ids[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		if len(selector.Categories) > 0 && !containsAny(r.Categories, selector.Categories) {
+			continue
+		}
+		if description != "" && !strings.Contains(strings.ToLower(r.Description), description) {
+			continue
+		}
+		selected = append(selected, r)
+	}
+
+	for _, id := range selector.IDs {
+		if !seen[id] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownManagedRule, id)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, ErrEmptyManagedRuleSelection
+	}
+	return selected, nil
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		if contains(values, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateManagedRuleOverride(o ManagedRuleOverride) error {
+	if o.Action == "" && o.Enabled == nil && o.SensitivityLevel == "" && o.ScoreThreshold == 0 {
+		return fmt.Errorf("%w: nothing overridden", ErrInvalidManagedRulesetOverrides)
+	}
+	if o.Action != "" && !containsRulesetRuleAction(managedRuleOverrideActions, o.Action) {
+		return fmt.Errorf("%w: action %q", ErrInvalidManagedRulesetOverrides, o.Action)
+	}
+	if o.SensitivityLevel != "" && !contains(managedRuleSensitivityLevels, o.SensitivityLevel) {
+		return fmt.Errorf("%w: sensitivity level %q", ErrInvalidManagedRulesetOverrides, o.SensitivityLevel)
+	}
+	if o.ScoreThreshold < 0 {
+		return fmt.Errorf("%w: score threshold %d", ErrInvalidManagedRulesetOverrides, o.ScoreThreshold)
+	}
+	return nil
+}
+
+// Ruleset overrides every rule of the managed ruleset.
+func (b *ManagedRulesetOverridesBuilder) Ruleset(o ManagedRuleOverride) *ManagedRulesetOverridesBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := validateManagedRuleOverride(o); err != nil {
+		b.err = err
+		return b
+	}
+	if o.ScoreThreshold != 0 {
+		b.err = fmt.Errorf("%w: score threshold can only be set on rules", ErrInvalidManagedRulesetOverrides)
+		return b
+	}
+
+	b.overrides.Action = string(o.Action)
+	b.overrides.Enabled = o.Enabled
+	b.overrides.SensitivityLevel = o.SensitivityLevel
+	return b
+}
+
+// Category overrides the rules of the managed ruleset tagged with the
+// category, replacing an earlier override of the category.
+func (b *ManagedRulesetOverridesBuilder) Category(category string, o ManagedRuleOverride) *ManagedRulesetOverridesBuilder {
+	if b.err != nil {
+		return b
+	}
+	if !b.categories[category] {
+		b.err = fmt.Errorf("%w: %q", ErrUnknownManagedRuleCategory, category)
+		return b
+	}
+	if err := validateManagedRuleOverride(o); err != nil {
+		b.err = err
+		return b
+	}
+	if o.SensitivityLevel != "" || o.ScoreThreshold != 0 {
+		b.err = fmt.Errorf("%w: sensitivity level and score threshold can't be set on categories", ErrInvalidManagedRulesetOverrides)
+		return b
+	}
+
+	override := RulesetRuleActionParametersCategories{Category: category, Action: string(o.Action), Enabled: o.Enabled}
+	for i, c := range b.overrides.Categories {
+		if c.Category == category {
+			b.overrides.Categories[i] = override
+			return b
+		}
+	}
+	b.overrides.Categories = append(b.overrides.Categories, override)
+	return b
+}
+
+// Rules overrides the rules of the managed ruleset matching the selector,
+// replacing earlier overrides of the same rules. Rule overrides take
+// precedence over category and ruleset overrides.
+func (b *ManagedRulesetOverridesBuilder) Rules(selector ManagedRuleSelector, o ManagedRuleOverride) *ManagedRulesetOverridesBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := validateManagedRuleOverride(o); err != nil {
+		b.err = err
+		return b
+	}
+	rules, err := b.Select(selector)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	for _, r := range rules {
+		if o.ScoreThreshold != 0 && r.ScoreThreshold == 0 {
+			b.err = fmt.Errorf("%w: rule %q has no score threshold", ErrInvalidManagedRulesetOverrides, r.ID)
+			return b
+		}
+
+		override := RulesetRuleActionParametersRules{
+			ID:               r.ID,
+			Action:           string(o.Action),
+			Enabled:          o.Enabled,
+			ScoreThreshold:   o.ScoreThreshold,
+			SensitivityLevel: o.SensitivityLevel,
+		}
+		replaced := false
+		for i, existing := range b.overrides.Rules {
+			if existing.ID == r.ID {
+				b.overrides.Rules[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			b.overrides.Rules = append(b.overrides.Rules, override)
+		}
+	}
+	return b
+}
+
+// Overrides returns the overrides built, or nil when nothing is overridden.
+func (b *ManagedRulesetOverridesBuilder) Overrides() (*RulesetRuleActionParametersOverrides, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	o := b.overrides
+	if o.Action == "" && o.Enabled == nil && o.SensitivityLevel == "" && len(o.Categories) == 0 && len(o.Rules) == 0 {
+		return nil, nil
+	}
+	o.Categories = append([]RulesetRuleActionParametersCategories(nil), o.Categories...)
+	o.Rules = append([]RulesetRuleActionParametersRules(nil), o.Rules...)
+	return &o, nil
+}
+
+// ExecuteRule returns a rule deploying the managed ruleset with the
+// overrides for the requests matching expression, to add to the entrypoint
+// ruleset of the phase of the managed ruleset.
+func (b *ManagedRulesetOverridesBuilder) ExecuteRule(expression string, opts ...RulesetRuleOption) (RulesetRule, error) {
+	overrides, err := b.Overrides()
+	if err != nil {
+		return RulesetRule{}, err
+	}
+
+	rule := RulesetRule{
+		Action:           string(RulesetRuleActionExecute),
+		Expression:       expression,
+		ActionParameters: &RulesetRuleActionParameters{ID: b.ruleset.ID, Overrides: overrides},
+	}
+	for _, opt := range opts {
+		opt(&rule)
+	}
+
+	phase := RulesetPhase(b.ruleset.Phase)
+	if phase == "" {
+		phase = RulesetPhaseHTTPRequestFirewallManaged
+	}
+	if err := ValidateRulesetRule(phase, rule); err != nil {
+		return RulesetRule{}, err
+	}
+	return rule, nil
+}
@@ -0,0 +1,161 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedRulesetOverridesBuilder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/efb7b8c949ac4650a09736fc376e9aee", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "efb7b8c949ac4650a09736fc376e9aee",
+				"name": "Cloudflare Managed Ruleset",
+				"kind": "managed",
+				"phase": "http_request_firewall_managed",
+				"rules": [
+					{"id": "r1", "action": "block", "expression": "true", "description": "Apache Struts - Code Injection", "categories": ["apache-struts", "cve-2017-5638"]},
+					{"id": "r2", "action": "log", "expression": "true", "description": "WordPress - SQLi", "categories": ["wordpress", "sqli"]},
+					{"id": "r3", "action": "block", "expression": "true", "description": "WordPress - XSS", "categories": ["wordpress", "xss"]},
+					{"id": "r4", "action": "block", "expression": "true", "description": "Anomaly Score", "score_threshold": 40}
+				]
+			}
+		}`)
+	})
+
+	b, err := client.GetManagedRulesetOverridesBuilder(context.Background(), ZoneIdentifier(testZoneID), "efb7b8c949ac4650a09736fc376e9aee")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apache-struts", "cve-2017-5638", "sqli", "wordpress", "xss"}, b.Categories())
+
+	selected, err := b.Select(ManagedRuleSelector{Categories: []string{"wordpress"}, Description: "sqli"})
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "r2", selected[0].ID)
+
+	selected, err = b.Select(ManagedRuleSelector{IDs: []string{"r1"}})
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "r1", selected[0].ID)
+
+	selected, err = b.Select(ManagedRuleSelector{IDs: []string{"r3", "r2"}, Categories: []string{"xss"}})
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "r3", selected[0].ID)
+
+	rule, err := b.
+		Ruleset(ManagedRuleOverride{Action: RulesetRuleActionLog}).
+		Category("wordpress", ManagedRuleOverride{Enabled: BoolPtr(false)}).
+		Rules(ManagedRuleSelector{Description: "wordpress"}, ManagedRuleOverride{Action: RulesetRuleActionManagedChallenge}).
+		Rules(ManagedRuleSelector{IDs: []string{"r3"}}, ManagedRuleOverride{Action: RulesetRuleActionBlock}).
+		Rules(ManagedRuleSelector{IDs: []string{"r4"}}, ManagedRuleOverride{ScoreThreshold: 25}).
+		ExecuteRule("true", WithRulesetRuleRef("managed"))
+	require.NoError(t, err)
+
+	assert.Equal(t, RulesetRule{
+		Action:     "execute",
+		Expression: "true",
+		Ref:        "managed",
+		ActionParameters: &RulesetRuleActionParameters{
+			ID: "efb7b8c949ac4650a09736fc376e9aee",
+			Overrides: &RulesetRuleActionParametersOverrides{
+				Action:     "log",
+				Categories: []RulesetRuleActionParametersCategories{{Category: "wordpress", Enabled: BoolPtr(false)}},
+				Rules: []RulesetRuleActionParametersRules{
+					{ID: "r2", Action: "managed_challenge"},
+					{ID: "r3", Action: "block"},
+					{ID: "r4", ScoreThreshold: 25},
+				},
+			},
+		},
+	}, rule)
+}
+
+func TestManagedRulesetOverridesBuilder_Invalid(t *testing.T) {
+	ruleset := Ruleset{
+		ID:    "efb7b8c949ac4650a09736fc376e9aee",
+		Phase: string(RulesetPhaseHTTPRequestFirewallManaged),
+		Rules: []RulesetRule{
+			{ID: "r1", Action: "block", Expression: "true", Description: "WordPress - SQLi", Categories: []string{"wordpress"}},
+		},
+	}
+
+	testCases := map[string]struct {
+		build func(*ManagedRulesetOverridesBuilder)
+		err   error
+	}{
+		"unknown rule": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Rules(ManagedRuleSelector{IDs: []string{"nope"}}, ManagedRuleOverride{Action: RulesetRuleActionLog})
+			},
+			err: ErrUnknownManagedRule,
+		},
+		"unknown category": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Category("drupal", ManagedRuleOverride{Action: RulesetRuleActionLog})
+			},
+			err: ErrUnknownManagedRuleCategory,
+		},
+		"empty selection": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Rules(ManagedRuleSelector{Description: "struts"}, ManagedRuleOverride{Action: RulesetRuleActionLog})
+			},
+			err: ErrEmptyManagedRuleSelection,
+		},
+		"unknown action": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Ruleset(ManagedRuleOverride{Action: RulesetRuleActionRewrite})
+			},
+			err: ErrInvalidManagedRulesetOverrides,
+		},
+		"unknown sensitivity": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Ruleset(ManagedRuleOverride{SensitivityLevel: "paranoid"})
+			},
+			err: ErrInvalidManagedRulesetOverrides,
+		},
+		"score threshold without one": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Rules(ManagedRuleSelector{IDs: []string{"r1"}}, ManagedRuleOverride{ScoreThreshold: 10})
+			},
+			err: ErrInvalidManagedRulesetOverrides,
+		},
+		"sensitivity on category": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Category("wordpress", ManagedRuleOverride{SensitivityLevel: "low"})
+			},
+			err: ErrInvalidManagedRulesetOverrides,
+		},
+		"first error kept": {
+			build: func(b *ManagedRulesetOverridesBuilder) {
+				b.Category("drupal", ManagedRuleOverride{Action: RulesetRuleActionLog}).Ruleset(ManagedRuleOverride{})
+			},
+			err: ErrUnknownManagedRuleCategory,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			b := NewManagedRulesetOverridesBuilder(ruleset)
+			tc.build(b)
+			_, err := b.ExecuteRule("true")
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	overrides, err := NewManagedRulesetOverridesBuilder(ruleset).Overrides()
+	require.NoError(t, err)
+	assert.Nil(t, overrides)
+}
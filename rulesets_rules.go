@@ -0,0 +1,125 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	ErrMissingRulesetID     = errors.New("required ruleset ID missing")
+	ErrMissingRulesetRuleID = errors.New("required ruleset rule ID missing")
+)
+
+// RulesetRulePosition places a rule in a ruleset. Only one of Before, After
+// and Index should be set; a rule without a position is added last.
+type RulesetRulePosition struct {
+	// Before is the ID of the rule to place the rule before.
+	Before string `json:"before,omitempty"`
+	// After is the ID of the rule to place the rule after.
+	After string `json:"after,omitempty"`
+	// Index is the 1-based position of the rule.
+	Index int `json:"index,omitempty"`
+}
+
+type CreateRulesetRuleParams struct {
+	RulesetID string
+	Position  *RulesetRulePosition
+	Rule      RulesetRule
+}
+
+type UpdateRulesetRuleParams struct {
+	RulesetID string
+	Position  *RulesetRulePosition
+	// Rule holds the new rule. Rule.ID identifies the rule to update.
+	Rule RulesetRule
+}
+
+type DeleteRulesetRuleParams struct {
+	RulesetID string
+	RuleID    string
+}
+
+// rulesetRuleRequest is the body of the rule endpoints, which take the rule
+// fields and its position at the same level.
+type rulesetRuleRequest struct {
+	RulesetRule
+	Position *RulesetRulePosition `json:"position,omitempty"`
+}
+
+// newRulesetRuleRequest drops the fields of rule which are managed by the API.
+func newRulesetRuleRequest(rule RulesetRule, position *RulesetRulePosition) rulesetRuleRequest {
+	rule.Version = nil
+	rule.LastUpdated = nil
+	return rulesetRuleRequest{RulesetRule: rule, Position: position}
+}
+
+// CreateRulesetRule adds a rule to a ruleset without replacing its other
+// rules and returns the updated ruleset.
+//
+// API reference: https://developers.cloudflare.com/api/operations/createAccountRulesetRule
+// API reference: https://developers.cloudflare.com/api/operations/createZoneRulesetRule
+func (api *API) CreateRulesetRule(ctx context.Context, rc *ResourceContainer, params CreateRulesetRuleParams) (Ruleset, error) {
+	if params.RulesetID == "" {
+		return Ruleset{}, ErrMissingRulesetID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/rulesets/%s/rules", rc.Level, rc.Identifier, params.RulesetID)
+
+	return api.rulesetRuleRequest(ctx, http.MethodPost, uri, newRulesetRuleRequest(params.Rule, params.Position))
+}
+
+// UpdateRulesetRule updates a single rule of a ruleset, optionally moving
+// it, and returns the updated ruleset.
+//
+// API reference: https://developers.cloudflare.com/api/operations/updateAccountRulesetRule
+// API reference: https://developers.cloudflare.com/api/operations/updateZoneRulesetRule
+func (api *API) UpdateRulesetRule(ctx context.Context, rc *ResourceContainer, params UpdateRulesetRuleParams) (Ruleset, error) {
+	if params.RulesetID == "" {
+		return Ruleset{}, ErrMissingRulesetID
+	}
+
+	if params.Rule.ID == "" {
+		return Ruleset{}, ErrMissingRulesetRuleID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/rulesets/%s/rules/%s", rc.Level, rc.Identifier, params.RulesetID, params.Rule.ID)
+
+	return api.rulesetRuleRequest(ctx, http.MethodPatch, uri, newRulesetRuleRequest(params.Rule, params.Position))
+}
+
+// DeleteRulesetRule removes a single rule from a ruleset and returns the
+// updated ruleset.
+//
+// API reference: https://developers.cloudflare.com/api/operations/deleteAccountRulesetRule
+// API reference: https://developers.cloudflare.com/api/operations/deleteZoneRulesetRule
+func (api *API) DeleteRulesetRule(ctx context.Context, rc *ResourceContainer, params DeleteRulesetRuleParams) (Ruleset, error) {
+	if params.RulesetID == "" {
+		return Ruleset{}, ErrMissingRulesetID
+	}
+
+	if params.RuleID == "" {
+		return Ruleset{}, ErrMissingRulesetRuleID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/rulesets/%s/rules/%s", rc.Level, rc.Identifier, params.RulesetID, params.RuleID)
+
+	return api.rulesetRuleRequest(ctx, http.MethodDelete, uri, nil)
+}
+
+func (api *API) rulesetRuleRequest(ctx context.Context, method, uri string, params interface{}) (Ruleset, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return Ruleset{}, err
+	}
+
+	result := UpdateRulesetResponse{}
+	if err := json.Unmarshal(res, &result); err != nil {
+		return Ruleset{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return result.Result, nil
+}
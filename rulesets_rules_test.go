@@ -0,0 +1,109 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRulesetID = "2c0fc9fa937b11eaa1b71c4d701ab86e"
+
+func TestCreateRulesetRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/"+testRulesetID+"/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"action": "block",
+			"expression": "ip.src eq 192.0.2.1",
+			"ref": "block-ip",
+			"position": {"after": "62449e2e0de149619edb35e59c10d801"}
+		}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"result": {
+				"id": "%s",
+				"version": "3",
+				"phase": "http_request_firewall_custom",
+				"rules": [
+					{"id": "62449e2e0de149619edb35e59c10d801", "action": "log", "expression": "true"},
+					{"id": "3a03d665bac047339bb530ecb439a90d", "action": "block", "expression": "ip.src eq 192.0.2.1", "ref": "block-ip"}
+				]
+			},
+			"success": true,
+			"errors": [],
+			"messages": []
+		}`, testRulesetID)
+	})
+
+	ruleset, err := client.CreateRulesetRule(context.Background(), ZoneIdentifier(testZoneID), CreateRulesetRuleParams{
+		RulesetID: testRulesetID,
+		Position:  &RulesetRulePosition{After: "62449e2e0de149619edb35e59c10d801"},
+		Rule: RulesetRule{
+			Action:     "block",
+			Expression: "ip.src eq 192.0.2.1",
+			Ref:        "block-ip",
+			Version:    StringPtr("1"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "3", *ruleset.Version)
+	assert.Len(t, ruleset.Rules, 2)
+}
+
+func TestUpdateRulesetRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/rulesets/"+testRulesetID+"/rules/3a03d665bac047339bb530ecb439a90d", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"id": "3a03d665bac047339bb530ecb439a90d",
+			"action": "managed_challenge",
+			"expression": "ip.src eq 192.0.2.1"
+		}`, string(body))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"result": {"id": "%s", "rules": [{"id": "3a03d665bac047339bb530ecb439a90d", "action": "managed_challenge", "expression": "ip.src eq 192.0.2.1"}]}, "success": true, "errors": [], "messages": []}`, testRulesetID)
+	})
+
+	ruleset, err := client.UpdateRulesetRule(context.Background(), AccountIdentifier(testAccountID), UpdateRulesetRuleParams{
+		RulesetID: testRulesetID,
+		Rule:      RulesetRule{ID: "3a03d665bac047339bb530ecb439a90d", Action: "managed_challenge", Expression: "ip.src eq 192.0.2.1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "managed_challenge", ruleset.Rules[0].Action)
+
+	_, err = client.UpdateRulesetRule(context.Background(), AccountIdentifier(testAccountID), UpdateRulesetRuleParams{RulesetID: testRulesetID})
+	assert.ErrorIs(t, err, ErrMissingRulesetRuleID)
+}
+
+func TestDeleteRulesetRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/"+testRulesetID+"/rules/3a03d665bac047339bb530ecb439a90d", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"result": {"id": "%s", "rules": []}, "success": true, "errors": [], "messages": []}`, testRulesetID)
+	})
+
+	ruleset, err := client.DeleteRulesetRule(context.Background(), ZoneIdentifier(testZoneID), DeleteRulesetRuleParams{
+		RulesetID: testRulesetID,
+		RuleID:    "3a03d665bac047339bb530ecb439a90d",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, ruleset.Rules)
+
+	_, err = client.DeleteRulesetRule(context.Background(), ZoneIdentifier(testZoneID), DeleteRulesetRuleParams{RuleID: "x"})
+	assert.ErrorIs(t, err, ErrMissingRulesetID)
+}
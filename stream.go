@@ -328,8 +328,6 @@ func (api *API) StreamListVideos(ctx context.Context, params StreamListParameter
 	return streamListResponse.Result, nil
 }
 
-// Skipped: https://api.cloudflare.com/#stream-videos-initiate-a-video-upload-using-tus
-
 // StreamGetVideo gets the details for a specific video.
 //
 // API Reference: https://api.cloudflare.com/#stream-videos-video-details
@@ -0,0 +1,208 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	// ErrMissingCaptionLanguage is for when the language tag of a caption is missing.
+	ErrMissingCaptionLanguage = errors.New("required caption language missing")
+	// ErrMissingCaptionFile is for when the WebVTT file of a caption is missing.
+	ErrMissingCaptionFile = errors.New("required caption file missing")
+)
+
+// StreamCaption represents a caption or subtitle track of a video.
+type StreamCaption struct {
+	Language  string `json:"language"`
+	Label     string `json:"label"`
+	Generated bool   `json:"generated,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// StreamCaptionParameters identify a caption track by its BCP 47 language tag.
+type StreamCaptionParameters struct {
+	AccountID string
+	VideoID   string
+	Language  string
+}
+
+// StreamUploadCaptionParameters are the parameters used when uploading a
+// WebVTT caption file.
+type StreamUploadCaptionParameters struct {
+	AccountID string
+	VideoID   string
+	Language  string
+	File      io.Reader
+}
+
+// StreamCaptionResponse represents an API response of a caption.
+type StreamCaptionResponse struct {
+	Response
+	Result StreamCaption `json:"result"`
+}
+
+// StreamCaptionListResponse represents an API response of the captions of a video.
+type StreamCaptionListResponse struct {
+	Response
+	Result []StreamCaption `json:"result"`
+}
+
+// StreamListCaptions lists the captions and subtitles of a video.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-subtitles/captions-list-captions-or-subtitles
+func (api *API) StreamListCaptions(ctx context.Context, options StreamParameters) ([]StreamCaption, error) {
+	if options.AccountID == "" {
+		return []StreamCaption{}, ErrMissingAccountID
+	}
+
+	if options.VideoID == "" {
+		return []StreamCaption{}, ErrMissingVideoID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/captions", options.AccountID, options.VideoID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []StreamCaption{}, err
+	}
+
+	var r StreamCaptionListResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return []StreamCaption{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamUploadCaption uploads a WebVTT caption file for a language,
+// replacing any existing caption of that language.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-subtitles/captions-upload-captions-or-subtitles
+func (api *API) StreamUploadCaption(ctx context.Context, params StreamUploadCaptionParameters) (StreamCaption, error) {
+	if params.AccountID == "" {
+		return StreamCaption{}, ErrMissingAccountID
+	}
+
+	if params.VideoID == "" {
+		return StreamCaption{}, ErrMissingVideoID
+	}
+
+	if params.Language == "" {
+		return StreamCaption{}, ErrMissingCaptionLanguage
+	}
+
+	if params.File == nil {
+		return StreamCaption{}, ErrMissingCaptionFile
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	formFile, err := writer.CreateFormFile("file", params.Language+".vtt")
+	if err != nil {
+		return StreamCaption{}, err
+	}
+	if _, err := io.Copy(formFile, params.File); err != nil {
+		return StreamCaption{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return StreamCaption{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/captions/%s", params.AccountID, params.VideoID, params.Language)
+
+	res, err := api.makeRequestContextWithHeaders(ctx, http.MethodPut, uri, body, http.Header{
+		"Accept":       []string{"application/json"},
+		"Content-Type": []string{writer.FormDataContentType()},
+	})
+	if err != nil {
+		return StreamCaption{}, err
+	}
+
+	var r StreamCaptionResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamCaption{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamGenerateCaption starts generating a caption for a language from the
+// audio of a video. The caption is ready once its status is "ready".
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-subtitles/captions-generate-caption-or-subtitle-for-provided-language-via-ai
+func (api *API) StreamGenerateCaption(ctx context.Context, params StreamCaptionParameters) (StreamCaption, error) {
+	if params.AccountID == "" {
+		return StreamCaption{}, ErrMissingAccountID
+	}
+
+	if params.VideoID == "" {
+		return StreamCaption{}, ErrMissingVideoID
+	}
+
+	if params.Language == "" {
+		return StreamCaption{}, ErrMissingCaptionLanguage
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/captions/%s/generate", params.AccountID, params.VideoID, params.Language)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, nil)
+	if err != nil {
+		return StreamCaption{}, err
+	}
+
+	var r StreamCaptionResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamCaption{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamGetCaptionVTT returns the WebVTT file of a caption.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-subtitles/captions-get-vtt-caption-or-subtitle
+func (api *API) StreamGetCaptionVTT(ctx context.Context, params StreamCaptionParameters) ([]byte, error) {
+	if params.AccountID == "" {
+		return nil, ErrMissingAccountID
+	}
+
+	if params.VideoID == "" {
+		return nil, ErrMissingVideoID
+	}
+
+	if params.Language == "" {
+		return nil, ErrMissingCaptionLanguage
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/captions/%s/vtt", params.AccountID, params.VideoID, params.Language)
+
+	return api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+}
+
+// StreamDeleteCaption removes the caption of a language from a video.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-subtitles/captions-delete-captions-or-subtitles
+func (api *API) StreamDeleteCaption(ctx context.Context, params StreamCaptionParameters) error {
+	if params.AccountID == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.VideoID == "" {
+		return ErrMissingVideoID
+	}
+
+	if params.Language == "" {
+		return ErrMissingCaptionLanguage
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/captions/%s", params.AccountID, params.VideoID, params.Language)
+	if _, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil); err != nil {
+		return err
+	}
+	return nil
+}
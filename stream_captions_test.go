@@ -0,0 +1,103 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_ListCaptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/"+testVideoID+"/captions", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"language": "en", "label": "English", "generated": true, "status": "ready"},
+				{"language": "de", "label": "Deutsch"}
+			]
+		}`)
+	})
+
+	want := []StreamCaption{
+		{Language: "en", Label: "English", Generated: true, Status: "ready"},
+		{Language: "de", Label: "Deutsch"},
+	}
+
+	got, err := client.StreamListCaptions(context.Background(), StreamParameters{AccountID: testAccountID, VideoID: testVideoID})
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestStream_UploadCaption(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/"+testVideoID+"/captions/de", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		f, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, _ := io.ReadAll(f)
+		assert.Equal(t, "WEBVTT\n", string(content))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"language": "de", "label": "Deutsch"}}`)
+	})
+
+	got, err := client.StreamUploadCaption(context.Background(), StreamUploadCaptionParameters{
+		AccountID: testAccountID,
+		VideoID:   testVideoID,
+		Language:  "de",
+		File:      strings.NewReader("WEBVTT\n"),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, StreamCaption{Language: "de", Label: "Deutsch"}, got)
+	}
+}
+
+func TestStream_GenerateCaption(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/"+testVideoID+"/captions/en/generate", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"language": "en", "label": "English (auto-generated)", "generated": true, "status": "inprogress"}}`)
+	})
+
+	got, err := client.StreamGenerateCaption(context.Background(), StreamCaptionParameters{AccountID: testAccountID, VideoID: testVideoID, Language: "en"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "inprogress", got.Status)
+		assert.True(t, got.Generated)
+	}
+}
+
+func TestStream_DeleteCaption(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/"+testVideoID+"/captions/en", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": ""}`)
+	})
+
+	err := client.StreamDeleteCaption(context.Background(), StreamCaptionParameters{AccountID: testAccountID, VideoID: testVideoID, Language: "en"})
+	assert.NoError(t, err)
+
+	err = client.StreamDeleteCaption(context.Background(), StreamCaptionParameters{AccountID: testAccountID, VideoID: testVideoID})
+	assert.ErrorIs(t, err, ErrMissingCaptionLanguage)
+}
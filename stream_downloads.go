@@ -0,0 +1,78 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+// StreamDownload represents an MP4 download of a video.
+type StreamDownload struct {
+	// Status is one of "inprogress", "ready" or "error".
+	Status          string  `json:"status"`
+	URL             string  `json:"url,omitempty"`
+	PercentComplete float64 `json:"percentComplete"`
+}
+
+// StreamDownloads are the downloads available for a video.
+type StreamDownloads struct {
+	Default *StreamDownload `json:"default,omitempty"`
+}
+
+// StreamDownloadsResponse represents an API response of the downloads of a video.
+type StreamDownloadsResponse struct {
+	Response
+	Result StreamDownloads `json:"result"`
+}
+
+// StreamCreateDownloads starts generating an MP4 download of a video.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-m-p-4-downloads-create-downloads
+func (api *API) StreamCreateDownloads(ctx context.Context, options StreamParameters) (StreamDownloads, error) {
+	return api.streamDownloadsRequest(ctx, http.MethodPost, options)
+}
+
+// StreamGetDownloads gets the MP4 downloads of a video and how far along
+// generating them is.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-m-p-4-downloads-list-downloads
+func (api *API) StreamGetDownloads(ctx context.Context, options StreamParameters) (StreamDownloads, error) {
+	return api.streamDownloadsRequest(ctx, http.MethodGet, options)
+}
+
+// StreamDeleteDownloads deletes the MP4 downloads of a video.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-m-p-4-downloads-delete-downloads
+func (api *API) StreamDeleteDownloads(ctx context.Context, options StreamParameters) error {
+	_, err := api.streamDownloadsRequest(ctx, http.MethodDelete, options)
+	return err
+}
+
+func (api *API) streamDownloadsRequest(ctx context.Context, method string, options StreamParameters) (StreamDownloads, error) {
+	if options.AccountID == "" {
+		return StreamDownloads{}, ErrMissingAccountID
+	}
+
+	if options.VideoID == "" {
+		return StreamDownloads{}, ErrMissingVideoID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/%s/downloads", options.AccountID, options.VideoID)
+
+	res, err := api.makeRequestContext(ctx, method, uri, nil)
+	if err != nil {
+		return StreamDownloads{}, err
+	}
+
+	if method == http.MethodDelete {
+		return StreamDownloads{}, nil
+	}
+
+	var r StreamDownloadsResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamDownloads{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,50 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream_Downloads(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/"+testVideoID+"/downloads", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodPost:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"default": {"status": "inprogress", "url": "https://customer-f33zs165nr7gyfy4.cloudflarestream.com/ea95132c15732412d22c1476fa83f27a/downloads/default.mp4", "percentComplete": 75.0}}}`)
+		case http.MethodGet:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"default": {"status": "ready", "url": "https://customer-f33zs165nr7gyfy4.cloudflarestream.com/ea95132c15732412d22c1476fa83f27a/downloads/default.mp4", "percentComplete": 100.0}}}`)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": "ok"}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	params := StreamParameters{AccountID: testAccountID, VideoID: testVideoID}
+
+	created, err := client.StreamCreateDownloads(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &StreamDownload{
+			Status:          "inprogress",
+			URL:             "https://customer-f33zs165nr7gyfy4.cloudflarestream.com/ea95132c15732412d22c1476fa83f27a/downloads/default.mp4",
+			PercentComplete: 75,
+		}, created.Default)
+	}
+
+	got, err := client.StreamGetDownloads(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ready", got.Default.Status)
+	}
+
+	assert.NoError(t, client.StreamDeleteDownloads(context.Background(), params))
+
+	_, err = client.StreamGetDownloads(context.Background(), StreamParameters{AccountID: testAccountID})
+	assert.ErrorIs(t, err, ErrMissingVideoID)
+}
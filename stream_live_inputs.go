@@ -0,0 +1,361 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	// ErrMissingLiveInputID is for when the ID of a live input is missing.
+	ErrMissingLiveInputID = errors.New("required live input id missing")
+	// ErrMissingLiveInputOutputID is for when the ID of a live input output is missing.
+	ErrMissingLiveInputOutputID = errors.New("required live input output id missing")
+	// ErrMissingLiveInputOutputURL is for when the URL of a live input output is missing.
+	ErrMissingLiveInputOutputURL = errors.New("required live input output url missing")
+)
+
+// StreamLiveInput represents a live input which receives a live stream and
+// optionally records it as videos.
+type StreamLiveInput struct {
+	UID                      string                   `json:"uid"`
+	Created                  *time.Time               `json:"created,omitempty"`
+	Modified                 *time.Time               `json:"modified,omitempty"`
+	Meta                     map[string]interface{}   `json:"meta,omitempty"`
+	DefaultCreator           string                   `json:"defaultCreator,omitempty"`
+	DeleteRecordingAfterDays int                      `json:"deleteRecordingAfterDays,omitempty"`
+	Recording                StreamLiveInputRecording `json:"recording"`
+	RTMPS                    StreamLiveInputEndpoint  `json:"rtmps"`
+	RTMPSPlayback            StreamLiveInputEndpoint  `json:"rtmpsPlayback"`
+	SRT                      StreamLiveInputSRT       `json:"srt"`
+	SRTPlayback              StreamLiveInputSRT       `json:"srtPlayback"`
+	WebRTC                   StreamLiveInputEndpoint  `json:"webRTC"`
+	WebRTCPlayback           StreamLiveInputEndpoint  `json:"webRTCPlayback"`
+	Status                   *StreamLiveInputStatus   `json:"status,omitempty"`
+}
+
+// StreamLiveInputRecording configures whether and how a live input is recorded.
+type StreamLiveInputRecording struct {
+	// Mode is either "off" or "automatic".
+	Mode                string   `json:"mode,omitempty"`
+	RequireSignedURLs   bool     `json:"requireSignedURLs"`
+	AllowedOrigins      []string `json:"allowedOrigins,omitempty"`
+	TimeoutSeconds      int      `json:"timeoutSeconds,omitempty"`
+	HideLiveViewerCount bool     `json:"hideLiveViewerCount"`
+}
+
+// StreamLiveInputEndpoint is an RTMPS or WebRTC URL of a live input.
+type StreamLiveInputEndpoint struct {
+	URL       string `json:"url"`
+	StreamKey string `json:"streamKey,omitempty"`
+}
+
+// StreamLiveInputSRT is an SRT URL of a live input.
+type StreamLiveInputSRT struct {
+	URL        string `json:"url"`
+	StreamID   string `json:"streamId,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// StreamLiveInputStatus describes whether a live input is receiving a stream.
+type StreamLiveInputStatus struct {
+	Current struct {
+		State string `json:"state"`
+	} `json:"current"`
+}
+
+// StreamLiveInputOutput restreams a live input to another RTMP(S) or SRT
+// destination.
+type StreamLiveInputOutput struct {
+	UID       string `json:"uid"`
+	URL       string `json:"url"`
+	StreamKey string `json:"streamKey,omitempty"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// StreamLiveInputParameters identify a live input.
+type StreamLiveInputParameters struct {
+	AccountID   string
+	LiveInputID string
+}
+
+// StreamCreateLiveInputParameters are the parameters used when creating or
+// updating a live input.
+type StreamCreateLiveInputParameters struct {
+	AccountID                string                    `json:"-"`
+	LiveInputID              string                    `json:"-"`
+	DefaultCreator           string                    `json:"defaultCreator,omitempty"`
+	DeleteRecordingAfterDays int                       `json:"deleteRecordingAfterDays,omitempty"`
+	Meta                     map[string]interface{}    `json:"meta,omitempty"`
+	Recording                *StreamLiveInputRecording `json:"recording,omitempty"`
+}
+
+// StreamLiveInputOutputParameters are the parameters used when managing the
+// outputs of a live input.
+type StreamLiveInputOutputParameters struct {
+	AccountID   string `json:"-"`
+	LiveInputID string `json:"-"`
+	OutputID    string `json:"-"`
+	URL         string `json:"url,omitempty"`
+	StreamKey   string `json:"streamKey,omitempty"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// StreamLiveInputResponse represents an API response of a live input.
+type StreamLiveInputResponse struct {
+	Response
+	Result StreamLiveInput `json:"result"`
+}
+
+// StreamLiveInputListResponse represents an API response of live inputs.
+type StreamLiveInputListResponse struct {
+	Response
+	Result struct {
+		LiveInputs []StreamLiveInput `json:"liveInputs"`
+		Range      int               `json:"range"`
+		Total      int               `json:"total"`
+	} `json:"result"`
+}
+
+// StreamLiveInputOutputResponse represents an API response of a live input output.
+type StreamLiveInputOutputResponse struct {
+	Response
+	Result StreamLiveInputOutput `json:"result"`
+}
+
+// StreamLiveInputOutputListResponse represents an API response of live input outputs.
+type StreamLiveInputOutputListResponse struct {
+	Response
+	Result []StreamLiveInputOutput `json:"result"`
+}
+
+// StreamCreateLiveInput creates a live input.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-create-a-live-input
+func (api *API) StreamCreateLiveInput(ctx context.Context, params StreamCreateLiveInputParameters) (StreamLiveInput, error) {
+	if params.AccountID == "" {
+		return StreamLiveInput{}, ErrMissingAccountID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs", params.AccountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return StreamLiveInput{}, err
+	}
+
+	var r StreamLiveInputResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamLiveInput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamListLiveInputs lists the live inputs of an account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-list-live-inputs
+func (api *API) StreamListLiveInputs(ctx context.Context, params StreamLiveInputParameters) ([]StreamLiveInput, error) {
+	if params.AccountID == "" {
+		return []StreamLiveInput{}, ErrMissingAccountID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs", params.AccountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []StreamLiveInput{}, err
+	}
+
+	var r StreamLiveInputListResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return []StreamLiveInput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result.LiveInputs, nil
+}
+
+// StreamGetLiveInput gets the details of a live input, including the URLs
+// and keys to stream to it.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-retrieve-a-live-input
+func (api *API) StreamGetLiveInput(ctx context.Context, params StreamLiveInputParameters) (StreamLiveInput, error) {
+	if params.AccountID == "" {
+		return StreamLiveInput{}, ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return StreamLiveInput{}, ErrMissingLiveInputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s", params.AccountID, params.LiveInputID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return StreamLiveInput{}, err
+	}
+
+	var r StreamLiveInputResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamLiveInput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamUpdateLiveInput updates the settings of a live input.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-update-a-live-input
+func (api *API) StreamUpdateLiveInput(ctx context.Context, params StreamCreateLiveInputParameters) (StreamLiveInput, error) {
+	if params.AccountID == "" {
+		return StreamLiveInput{}, ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return StreamLiveInput{}, ErrMissingLiveInputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s", params.AccountID, params.LiveInputID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+	if err != nil {
+		return StreamLiveInput{}, err
+	}
+
+	var r StreamLiveInputResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamLiveInput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamDeleteLiveInput deletes a live input. Streams being received are
+// disconnected.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-delete-a-live-input
+func (api *API) StreamDeleteLiveInput(ctx context.Context, params StreamLiveInputParameters) error {
+	if params.AccountID == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return ErrMissingLiveInputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s", params.AccountID, params.LiveInputID)
+	if _, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// StreamListLiveInputOutputs lists the outputs of a live input.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-list-all-outputs-associated-with-a-specified-live-input
+func (api *API) StreamListLiveInputOutputs(ctx context.Context, params StreamLiveInputParameters) ([]StreamLiveInputOutput, error) {
+	if params.AccountID == "" {
+		return []StreamLiveInputOutput{}, ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return []StreamLiveInputOutput{}, ErrMissingLiveInputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s/outputs", params.AccountID, params.LiveInputID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []StreamLiveInputOutput{}, err
+	}
+
+	var r StreamLiveInputOutputListResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return []StreamLiveInputOutput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamCreateLiveInputOutput adds an output restreaming a live input to
+// params.URL.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-create-a-new-output,-connected-to-a-live-input
+func (api *API) StreamCreateLiveInputOutput(ctx context.Context, params StreamLiveInputOutputParameters) (StreamLiveInputOutput, error) {
+	if params.AccountID == "" {
+		return StreamLiveInputOutput{}, ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return StreamLiveInputOutput{}, ErrMissingLiveInputID
+	}
+
+	if params.URL == "" {
+		return StreamLiveInputOutput{}, ErrMissingLiveInputOutputURL
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s/outputs", params.AccountID, params.LiveInputID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	if err != nil {
+		return StreamLiveInputOutput{}, err
+	}
+
+	var r StreamLiveInputOutputResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamLiveInputOutput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamUpdateLiveInputOutput enables or disables an output of a live input.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-update-an-output
+func (api *API) StreamUpdateLiveInputOutput(ctx context.Context, params StreamLiveInputOutputParameters) (StreamLiveInputOutput, error) {
+	if params.AccountID == "" {
+		return StreamLiveInputOutput{}, ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return StreamLiveInputOutput{}, ErrMissingLiveInputID
+	}
+
+	if params.OutputID == "" {
+		return StreamLiveInputOutput{}, ErrMissingLiveInputOutputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s/outputs/%s", params.AccountID, params.LiveInputID, params.OutputID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+	if err != nil {
+		return StreamLiveInputOutput{}, err
+	}
+
+	var r StreamLiveInputOutputResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamLiveInputOutput{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamDeleteLiveInputOutput removes an output from a live input.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-live-inputs-delete-an-output
+func (api *API) StreamDeleteLiveInputOutput(ctx context.Context, params StreamLiveInputOutputParameters) error {
+	if params.AccountID == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.LiveInputID == "" {
+		return ErrMissingLiveInputID
+	}
+
+	if params.OutputID == "" {
+		return ErrMissingLiveInputOutputID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/live_inputs/%s/outputs/%s", params.AccountID, params.LiveInputID, params.OutputID)
+	if _, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil); err != nil {
+		return err
+	}
+	return nil
+}
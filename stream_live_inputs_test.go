@@ -0,0 +1,185 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLiveInputID = "66be4bf738797e01e1fca35a7bdecdcd"
+
+	testLiveInputResponse = `{
+		"success": true,
+		"errors": [],
+		"messages": [],
+		"result": {
+			"uid": "66be4bf738797e01e1fca35a7bdecdcd",
+			"created": "2014-01-02T02:20:00Z",
+			"modified": "2014-01-02T02:20:00Z",
+			"meta": {"name": "test stream 1"},
+			"deleteRecordingAfterDays": 45,
+			"recording": {"mode": "automatic", "requireSignedURLs": false, "timeoutSeconds": 0, "hideLiveViewerCount": false},
+			"rtmps": {"url": "rtmps://live.cloudflare.com:443/live/", "streamKey": "2fb3cb9f17e68a2568d6ebed8d5505eak3ceaf8c9b1f395e1b76b79332497cada"},
+			"rtmpsPlayback": {"url": "rtmps://live.cloudflare.com:443/live/", "streamKey": "2fb3cb9f17e68a2568d6ebed8d5505eak3ceaf8c9b1f395e1b76b79332497cada"},
+			"srt": {"url": "srt://live.cloudflare.com:778", "streamId": "f256e6ea9341d51eea64c9454659e576", "passphrase": "2fb3cb9f17e68a2568d6ebed8d5505eak3ceaf8c9b1f395e1b76b79332497cada"},
+			"webRTC": {"url": "https://customer-m033z5x00ks6nunl.cloudflarestream.com/b236bde30eb07b9d01318940e5fc3edake34a3efb3896e18f2dc277ce6cc993ad/webRTC/publish"},
+			"status": null
+		}
+	}`
+)
+
+func TestStream_CreateLiveInput(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/live_inputs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, float64(45), body["deleteRecordingAfterDays"])
+		assert.Equal(t, "automatic", body["recording"].(map[string]interface{})["mode"])
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testLiveInputResponse)
+	})
+
+	got, err := client.StreamCreateLiveInput(context.Background(), StreamCreateLiveInputParameters{
+		AccountID:                testAccountID,
+		DeleteRecordingAfterDays: 45,
+		Meta:                     map[string]interface{}{"name": "test stream 1"},
+		Recording:                &StreamLiveInputRecording{Mode: "automatic"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, testLiveInputID, got.UID)
+		assert.Equal(t, "rtmps://live.cloudflare.com:443/live/", got.RTMPS.URL)
+		assert.Equal(t, "f256e6ea9341d51eea64c9454659e576", got.SRT.StreamID)
+		assert.Nil(t, got.Status)
+	}
+}
+
+func TestStream_ListLiveInputs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/live_inputs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"liveInputs": [{"uid": "66be4bf738797e01e1fca35a7bdecdcd", "meta": {"name": "test stream 1"}, "deleteRecordingAfterDays": 45}],
+				"range": 1000,
+				"total": 1
+			}
+		}`)
+	})
+
+	got, err := client.StreamListLiveInputs(context.Background(), StreamLiveInputParameters{AccountID: testAccountID})
+	if assert.NoError(t, err) {
+		require.Len(t, got, 1)
+		assert.Equal(t, testLiveInputID, got[0].UID)
+	}
+}
+
+func TestStream_GetUpdateDeleteLiveInput(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/live_inputs/"+testLiveInputID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet, http.MethodPut:
+			fmt.Fprint(w, testLiveInputResponse)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": ""}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	_, err := client.StreamGetLiveInput(context.Background(), StreamLiveInputParameters{AccountID: testAccountID, LiveInputID: testLiveInputID})
+	assert.NoError(t, err)
+
+	_, err = client.StreamUpdateLiveInput(context.Background(), StreamCreateLiveInputParameters{AccountID: testAccountID, LiveInputID: testLiveInputID, DefaultCreator: "creator-id"})
+	assert.NoError(t, err)
+
+	_, err = client.StreamUpdateLiveInput(context.Background(), StreamCreateLiveInputParameters{AccountID: testAccountID})
+	assert.ErrorIs(t, err, ErrMissingLiveInputID)
+
+	assert.NoError(t, client.StreamDeleteLiveInput(context.Background(), StreamLiveInputParameters{AccountID: testAccountID, LiveInputID: testLiveInputID}))
+}
+
+func TestStream_LiveInputOutputs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	output := `{"uid": "baea4d9c515887b80289d5c33cf01145", "url": "rtmp://a.rtmp.youtube.com/live2", "streamKey": "uzya-f19y-g2g9-a2ee-51j2", "enabled": true}`
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/live_inputs/"+testLiveInputID+"/outputs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [%s]}`, output)
+		case http.MethodPost:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "rtmp://a.rtmp.youtube.com/live2", body["url"])
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, output)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/live_inputs/"+testLiveInputID+"/outputs/baea4d9c515887b80289d5c33cf01145", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodPut:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"enabled": false}, body)
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"uid": "baea4d9c515887b80289d5c33cf01145", "url": "rtmp://a.rtmp.youtube.com/live2", "enabled": false}}`)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": ""}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	want := StreamLiveInputOutput{UID: "baea4d9c515887b80289d5c33cf01145", URL: "rtmp://a.rtmp.youtube.com/live2", StreamKey: "uzya-f19y-g2g9-a2ee-51j2", Enabled: true}
+
+	outputs, err := client.StreamListLiveInputOutputs(context.Background(), StreamLiveInputParameters{AccountID: testAccountID, LiveInputID: testLiveInputID})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []StreamLiveInputOutput{want}, outputs)
+	}
+
+	created, err := client.StreamCreateLiveInputOutput(context.Background(), StreamLiveInputOutputParameters{
+		AccountID:   testAccountID,
+		LiveInputID: testLiveInputID,
+		URL:         "rtmp://a.rtmp.youtube.com/live2",
+		StreamKey:   "uzya-f19y-g2g9-a2ee-51j2",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, created)
+	}
+
+	updated, err := client.StreamUpdateLiveInputOutput(context.Background(), StreamLiveInputOutputParameters{
+		AccountID:   testAccountID,
+		LiveInputID: testLiveInputID,
+		OutputID:    "baea4d9c515887b80289d5c33cf01145",
+		Enabled:     BoolPtr(false),
+	})
+	if assert.NoError(t, err) {
+		assert.False(t, updated.Enabled)
+	}
+
+	err = client.StreamDeleteLiveInputOutput(context.Background(), StreamLiveInputOutputParameters{AccountID: testAccountID, LiveInputID: testLiveInputID, OutputID: "baea4d9c515887b80289d5c33cf01145"})
+	assert.NoError(t, err)
+}
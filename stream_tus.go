@@ -0,0 +1,331 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tusVersion = "1.0.0"
+
+	// Stream requires every chunk but the last to be a multiple of 256 KiB
+	// and at least 5 MiB.
+	streamTusChunkAlignment    = 256 * 1024
+	streamTusMinChunkSize      = 5 * 1024 * 1024
+	streamTusDefaultChunkSize  = 50 * 1024 * 1024
+	streamTusDefaultMaxRetries = 3
+)
+
+var (
+	// ErrMissingTusReader is for when the video reader of a tus upload is missing.
+	ErrMissingTusReader = errors.New("required video reader missing")
+	// ErrMissingTusUploadSize is for when the size of a tus upload is missing.
+	ErrMissingTusUploadSize = errors.New("required upload size missing")
+	// ErrInvalidTusChunkSize is for when the chunk size is not accepted by Stream.
+	ErrInvalidTusChunkSize = errors.New("chunk size must be a multiple of 256 KiB and at least 5 MiB")
+	// ErrTusOffsetMismatch is for when the server reports an offset the
+	// upload can't continue from.
+	ErrTusOffsetMismatch = errors.New("tus upload offset mismatch")
+)
+
+// StreamTusUploadParameters are the parameters of a resumable upload using
+// the tus protocol.
+type StreamTusUploadParameters struct {
+	AccountID string
+
+	// Reader is the video to upload and Size its length in bytes.
+	Reader io.ReaderAt
+	Size   int64
+
+	Name                  string
+	Creator               string
+	RequireSignedURLs     bool
+	AllowedOrigins        []string
+	ThumbnailTimestampPct float64
+	MaxDurationSeconds    int
+	Expiry                *time.Time
+	Watermark             UploadVideoURLWatermark
+
+	// UploadURL and VideoID resume an upload created earlier instead of
+	// creating a new one.
+	UploadURL string
+	VideoID   string
+
+	// ChunkSize is the number of bytes sent per request. Defaults to 50 MiB.
+	ChunkSize int64
+
+	// MaxRetries is the number of times a failed chunk is retried after
+	// asking the server where the upload stands. Defaults to 3.
+	MaxRetries int
+
+	// OnProgress is called after every chunk with the number of bytes
+	// stored by the server.
+	OnProgress func(uploaded, total int64)
+}
+
+// StreamTusUpload describes a tus upload. When an upload fails, UploadURL
+// and VideoID can be used to resume it.
+type StreamTusUpload struct {
+	UploadURL string
+	VideoID   string
+	Offset    int64
+	Size      int64
+}
+
+// StreamCreateTusUpload creates a tus upload of params.Size bytes and
+// returns where to send the video.
+//
+// API Reference: https://developers.cloudflare.com/stream/uploading-videos/resumable-uploads/
+func (api *API) StreamCreateTusUpload(ctx context.Context, params StreamTusUploadParameters) (StreamTusUpload, error) {
+	if params.AccountID == "" {
+		return StreamTusUpload{}, ErrMissingAccountID
+	}
+
+	if params.Size <= 0 {
+		return StreamTusUpload{}, ErrMissingTusUploadSize
+	}
+
+	headers := http.Header{
+		"Tus-Resumable": {tusVersion},
+		"Upload-Length": {strconv.FormatInt(params.Size, 10)},
+	}
+	if metadata := streamTusMetadata(params); metadata != "" {
+		headers.Set("Upload-Metadata", metadata)
+	}
+	if params.Creator != "" {
+		headers.Set("Upload-Creator", params.Creator)
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream", params.AccountID)
+	res, err := api.makeRequestContextWithHeadersComplete(ctx, http.MethodPost, uri, nil, headers)
+	if err != nil {
+		return StreamTusUpload{}, err
+	}
+
+	location := res.Headers.Get("Location")
+	if location == "" {
+		return StreamTusUpload{}, ErrMissingUploadURL
+	}
+
+	return StreamTusUpload{
+		UploadURL: location,
+		VideoID:   res.Headers.Get("stream-media-id"),
+		Size:      params.Size,
+	}, nil
+}
+
+// StreamGetTusUploadOffset returns the number of bytes of a tus upload
+// stored by the server.
+func (api *API) StreamGetTusUploadOffset(ctx context.Context, uploadURL string) (int64, error) {
+	if uploadURL == "" {
+		return 0, ErrMissingUploadURL
+	}
+
+	resp, err := api.tusRequest(ctx, http.MethodHead, uploadURL, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+}
+
+// StreamUploadVideoTus uploads a video in chunks using the tus protocol.
+// Failed chunks are retried from the offset reported by the server and an
+// interrupted upload can be resumed by passing the UploadURL and VideoID
+// of the returned StreamTusUpload.
+//
+// API Reference: https://developers.cloudflare.com/stream/uploading-videos/resumable-uploads/
+func (api *API) StreamUploadVideoTus(ctx context.Context, params StreamTusUploadParameters) (StreamTusUpload, error) {
+	if params.AccountID == "" {
+		return StreamTusUpload{}, ErrMissingAccountID
+	}
+
+	if params.Reader == nil {
+		return StreamTusUpload{}, ErrMissingTusReader
+	}
+
+	if params.Size <= 0 {
+		return StreamTusUpload{}, ErrMissingTusUploadSize
+	}
+
+	chunkSize := params.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = streamTusDefaultChunkSize
+	}
+	if chunkSize < streamTusMinChunkSize || chunkSize%streamTusChunkAlignment != 0 {
+		return StreamTusUpload{}, ErrInvalidTusChunkSize
+	}
+
+	maxRetries := params.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = streamTusDefaultMaxRetries
+	}
+
+	upload := StreamTusUpload{UploadURL: params.UploadURL, VideoID: params.VideoID, Size: params.Size}
+	if upload.UploadURL == "" {
+		created, err := api.StreamCreateTusUpload(ctx, params)
+		if err != nil {
+			return StreamTusUpload{}, err
+		}
+		upload = created
+	} else {
+		offset, err := api.StreamGetTusUploadOffset(ctx, upload.UploadURL)
+		if err != nil {
+			return upload, err
+		}
+		upload.Offset = offset
+	}
+
+	failures := 0
+	for upload.Offset < upload.Size {
+		length := chunkSize
+		if remaining := upload.Size - upload.Offset; remaining < length {
+			length = remaining
+		}
+
+		offset, err := api.streamTusPatch(ctx, upload.UploadURL, params.Reader, upload.Offset, length)
+		if err == nil {
+			failures = 0
+			upload.Offset = offset
+			if params.OnProgress != nil {
+				params.OnProgress(upload.Offset, upload.Size)
+			}
+			continue
+		}
+
+		if ctx.Err() != nil || failures >= maxRetries {
+			return upload, err
+		}
+		failures++
+
+		sleepDuration := time.Duration(math.Pow(2, float64(failures-1)) * float64(api.retryPolicy.MinRetryDelay))
+		if sleepDuration > api.retryPolicy.MaxRetryDelay {
+			sleepDuration = api.retryPolicy.MaxRetryDelay
+		}
+		select {
+		case <-time.After(sleepDuration):
+		case <-ctx.Done():
+			return upload, fmt.Errorf("operation aborted during backoff: %w", ctx.Err())
+		}
+
+		serverOffset, headErr := api.StreamGetTusUploadOffset(ctx, upload.UploadURL)
+		if headErr != nil {
+			continue
+		}
+		upload.Offset = serverOffset
+	}
+
+	return upload, nil
+}
+
+// streamTusPatch sends length bytes from offset and returns the offset the
+// server reports afterwards.
+func (api *API) streamTusPatch(ctx context.Context, uploadURL string, r io.ReaderAt, offset, length int64) (int64, error) {
+	headers := http.Header{
+		"Upload-Offset": {strconv.FormatInt(offset, 10)},
+		"Content-Type":  {"application/offset+octet-stream"},
+	}
+	body := io.NewSectionReader(r, offset, length)
+
+	resp, err := api.tusRequest(ctx, http.MethodPatch, uploadURL, body, headers)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	newOffset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || newOffset <= offset {
+		return 0, fmt.Errorf("%w: sent %d bytes from %d, server reported %q", ErrTusOffsetMismatch, length, offset, resp.Header.Get("Upload-Offset"))
+	}
+	return newOffset, nil
+}
+
+// tusRequest sends an authenticated request to a tus upload URL, which may
+// live outside of the API.
+func (api *API) tusRequest(ctx context.Context, method, uploadURL string, body io.Reader, headers http.Header) (*http.Response, error) {
+	if err := api.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("error caused by request rate limiting: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, uploadURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request creation failed: %w", err)
+	}
+
+	copyHeader(req.Header, api.headers)
+	copyHeader(req.Header, headers)
+	req.Header.Set("Tus-Resumable", tusVersion)
+	// Upload URLs are signed. Credentials are only sent to the API, as a
+	// resumed upload URL can point anywhere.
+	if api.isAPIHost(req.URL) {
+		api.setAuthHeaders(req.Header, api.authType)
+	}
+	if api.UserAgent != "" {
+		req.Header.Set("User-Agent", api.UserAgent)
+	}
+
+	resp, err := api.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("tus %s request failed (HTTP %d): %s", strings.ToLower(method), resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return resp, nil
+}
+
+// isAPIHost reports whether u is on the host of the API base URL.
+func (api *API) isAPIHost(u *url.URL) bool {
+	base, err := url.Parse(api.BaseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+// streamTusMetadata encodes the video settings as an Upload-Metadata header,
+// a list of keys followed by their base64 encoded value.
+func streamTusMetadata(params StreamTusUploadParameters) string {
+	var pairs []string
+	add := func(key, value string) {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+
+	if params.Name != "" {
+		add("name", params.Name)
+	}
+	if params.RequireSignedURLs {
+		pairs = append(pairs, "requiresignedurls")
+	}
+	if len(params.AllowedOrigins) > 0 {
+		add("allowedorigins", strings.Join(params.AllowedOrigins, ","))
+	}
+	if params.ThumbnailTimestampPct > 0 {
+		add("thumbnailtimestamppct", strconv.FormatFloat(params.ThumbnailTimestampPct, 'f', -1, 64))
+	}
+	if params.MaxDurationSeconds > 0 {
+		add("maxDurationSeconds", strconv.Itoa(params.MaxDurationSeconds))
+	}
+	if params.Expiry != nil {
+		add("expiry", params.Expiry.UTC().Format(time.RFC3339))
+	}
+	if params.Watermark.UID != "" {
+		add("watermark", params.Watermark.UID)
+	}
+
+	return strings.Join(pairs, ",")
+}
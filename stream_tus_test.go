@@ -0,0 +1,164 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tusStandIn is a minimal tus server storing a single upload.
+type tusStandIn struct {
+	mu       sync.Mutex
+	length   int64
+	data     []byte
+	metadata string
+	patches  int
+	// failPatch makes the given PATCH store half of its body and fail.
+	failPatch int
+}
+
+func (s *tusStandIn) create(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.length, _ = strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	s.metadata = r.Header.Get("Upload-Metadata")
+
+	w.Header().Set("Location", server.URL+"/tus/ea95132c15732412d22c1476fa83f27a")
+	w.Header().Set("stream-media-id", "ea95132c15732412d22c1476fa83f27a")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *tusStandIn) upload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+		w.Header().Set("Upload-Length", strconv.FormatInt(s.length, 10))
+	case http.MethodPatch:
+		s.patches++
+		offset, _ := strconv.Atoi(r.Header.Get("Upload-Offset"))
+		if offset != len(s.data) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if s.patches == s.failPatch {
+			s.data = append(s.data, body[:len(body)/2]...)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		s.data = append(s.data, body...)
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStreamUploadVideoTus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	standIn := &tusStandIn{failPatch: 2}
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream", standIn.create)
+	mux.HandleFunc("/tus/ea95132c15732412d22c1476fa83f27a", standIn.upload)
+
+	video := bytes.Repeat([]byte("0123456789abcdef"), 12*1024*1024/16+100)
+	var progress []int64
+
+	upload, err := client.StreamUploadVideoTus(context.Background(), StreamTusUploadParameters{
+		AccountID:         testAccountID,
+		Reader:            bytes.NewReader(video),
+		Size:              int64(len(video)),
+		Name:              "video.mp4",
+		RequireSignedURLs: true,
+		Watermark:         UploadVideoURLWatermark{UID: "ea95132c15732412d22c1476fa83f27a"},
+		ChunkSize:         streamTusMinChunkSize,
+		OnProgress:        func(uploaded, total int64) { progress = append(progress, uploaded) },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "ea95132c15732412d22c1476fa83f27a", upload.VideoID)
+	assert.Equal(t, int64(len(video)), upload.Offset)
+	assert.Equal(t, video, standIn.data)
+	assert.Equal(t, int64(len(video)), progress[len(progress)-1])
+
+	metadata := strings.Split(standIn.metadata, ",")
+	assert.Contains(t, metadata, "name "+base64.StdEncoding.EncodeToString([]byte("video.mp4")))
+	assert.Contains(t, metadata, "requiresignedurls")
+	assert.Contains(t, metadata, "watermark "+base64.StdEncoding.EncodeToString([]byte("ea95132c15732412d22c1476fa83f27a")))
+}
+
+func TestStreamUploadVideoTus_Resume(t *testing.T) {
+	setup()
+	defer teardown()
+
+	video := bytes.Repeat([]byte("x"), 6*1024*1024)
+	standIn := &tusStandIn{length: int64(len(video)), data: append([]byte(nil), video[:1024]...)}
+	mux.HandleFunc("/tus/ea95132c15732412d22c1476fa83f27a", standIn.upload)
+
+	upload, err := client.StreamUploadVideoTus(context.Background(), StreamTusUploadParameters{
+		AccountID: testAccountID,
+		Reader:    bytes.NewReader(video),
+		Size:      int64(len(video)),
+		UploadURL: server.URL + "/tus/ea95132c15732412d22c1476fa83f27a",
+		VideoID:   "ea95132c15732412d22c1476fa83f27a",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(video)), upload.Offset)
+	assert.Equal(t, video, standIn.data)
+	assert.Equal(t, 1, standIn.patches)
+}
+
+func TestStreamUploadVideoTus_Credentials(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var authenticated []bool
+	checkAuth := func(w http.ResponseWriter, r *http.Request) {
+		authenticated = append(authenticated, r.Header.Get("X-Auth-Key") != "" || r.Header.Get("Authorization") != "")
+		w.Header().Set("Upload-Offset", "0")
+	}
+	mux.HandleFunc("/tus/ea95132c15732412d22c1476fa83f27a", checkAuth)
+	upload := httptest.NewServer(http.HandlerFunc(checkAuth))
+	defer upload.Close()
+
+	_, err := client.StreamGetTusUploadOffset(context.Background(), server.URL+"/tus/ea95132c15732412d22c1476fa83f27a")
+	require.NoError(t, err)
+	_, err = client.StreamGetTusUploadOffset(context.Background(), upload.URL+"/tus/ea95132c15732412d22c1476fa83f27a")
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{true, false}, authenticated)
+}
+
+func TestStreamUploadVideoTus_InvalidChunkSize(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.StreamUploadVideoTus(context.Background(), StreamTusUploadParameters{
+		AccountID: testAccountID,
+		Reader:    bytes.NewReader([]byte("video")),
+		Size:      5,
+		ChunkSize: 1024,
+	})
+	assert.ErrorIs(t, err, ErrInvalidTusChunkSize)
+}
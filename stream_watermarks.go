@@ -0,0 +1,200 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	// ErrMissingWatermarkID is for when the ID of a watermark profile is missing.
+	ErrMissingWatermarkID = errors.New("required watermark id missing")
+	// ErrMissingWatermarkImage is for when neither a file nor a URL is given
+	// for a watermark image.
+	ErrMissingWatermarkImage = errors.New("required watermark file or url missing")
+)
+
+// StreamWatermarkParameters identify a watermark profile.
+type StreamWatermarkParameters struct {
+	AccountID   string
+	WatermarkID string
+}
+
+// StreamCreateWatermarkParameters are the parameters used when creating a
+// watermark profile, either from an uploaded File or an image URL.
+type StreamCreateWatermarkParameters struct {
+	AccountID string    `json:"-"`
+	File      io.Reader `json:"-"`
+	FileName  string    `json:"-"`
+	URL       string    `json:"url,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Opacity   float64   `json:"opacity,omitempty"`
+	Padding   float64   `json:"padding,omitempty"`
+	Scale     float64   `json:"scale,omitempty"`
+	// Position is one of "upperRight", "upperLeft", "lowerLeft", "lowerRight"
+	// or "center".
+	Position string `json:"position,omitempty"`
+}
+
+// StreamWatermarkResponse represents an API response of a watermark profile.
+type StreamWatermarkResponse struct {
+	Response
+	Result StreamVideoWatermark `json:"result"`
+}
+
+// StreamWatermarkListResponse represents an API response of watermark profiles.
+type StreamWatermarkListResponse struct {
+	Response
+	Result []StreamVideoWatermark `json:"result"`
+}
+
+func (p StreamCreateWatermarkParameters) write(mpw *multipart.Writer) error {
+	name := p.FileName
+	if name == "" {
+		name = "watermark"
+	}
+	formFile, err := mpw.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(formFile, p.File); err != nil {
+		return err
+	}
+
+	fields := map[string]string{"name": p.Name, "position": p.Position}
+	for k, v := range map[string]float64{"opacity": p.Opacity, "padding": p.Padding, "scale": p.Scale} {
+		if v != 0 {
+			fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mpw.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StreamCreateWatermark creates a watermark profile which can be applied to
+// videos when they are uploaded.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-watermark-profile-create-watermark-profiles-via-basic-upload
+func (api *API) StreamCreateWatermark(ctx context.Context, params StreamCreateWatermarkParameters) (StreamVideoWatermark, error) {
+	if params.AccountID == "" {
+		return StreamVideoWatermark{}, ErrMissingAccountID
+	}
+
+	if params.File == nil && params.URL == "" {
+		return StreamVideoWatermark{}, ErrMissingWatermarkImage
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/watermarks", params.AccountID)
+
+	var res []byte
+	var err error
+	if params.File != nil {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		if err := params.write(writer); err != nil {
+			return StreamVideoWatermark{}, err
+		}
+		if err := writer.Close(); err != nil {
+			return StreamVideoWatermark{}, err
+		}
+
+		res, err = api.makeRequestContextWithHeaders(ctx, http.MethodPost, uri, body, http.Header{
+			"Accept":       []string{"application/json"},
+			"Content-Type": []string{writer.FormDataContentType()},
+		})
+	} else {
+		res, err = api.makeRequestContext(ctx, http.MethodPost, uri, params)
+	}
+	if err != nil {
+		return StreamVideoWatermark{}, err
+	}
+
+	var r StreamWatermarkResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamVideoWatermark{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamListWatermarks lists the watermark profiles of an account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-watermark-profile-list-watermark-profiles
+func (api *API) StreamListWatermarks(ctx context.Context, params StreamWatermarkParameters) ([]StreamVideoWatermark, error) {
+	if params.AccountID == "" {
+		return []StreamVideoWatermark{}, ErrMissingAccountID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/watermarks", params.AccountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []StreamVideoWatermark{}, err
+	}
+
+	var r StreamWatermarkListResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return []StreamVideoWatermark{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamGetWatermark gets the details of a watermark profile.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-watermark-profile-watermark-profile-details
+func (api *API) StreamGetWatermark(ctx context.Context, params StreamWatermarkParameters) (StreamVideoWatermark, error) {
+	if params.AccountID == "" {
+		return StreamVideoWatermark{}, ErrMissingAccountID
+	}
+
+	if params.WatermarkID == "" {
+		return StreamVideoWatermark{}, ErrMissingWatermarkID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/watermarks/%s", params.AccountID, params.WatermarkID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return StreamVideoWatermark{}, err
+	}
+
+	var r StreamWatermarkResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamVideoWatermark{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamDeleteWatermark deletes a watermark profile. Videos already
+// watermarked with it are not affected.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-watermark-profile-delete-watermark-profiles
+func (api *API) StreamDeleteWatermark(ctx context.Context, params StreamWatermarkParameters) error {
+	if params.AccountID == "" {
+		return ErrMissingAccountID
+	}
+
+	if params.WatermarkID == "" {
+		return ErrMissingWatermarkID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/watermarks/%s", params.AccountID, params.WatermarkID)
+	if _, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil); err != nil {
+		return err
+	}
+	return nil
+}
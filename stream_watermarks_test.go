@@ -0,0 +1,116 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWatermarkResponse = `{
+	"success": true,
+	"errors": [],
+	"messages": [],
+	"result": {
+		"uid": "ea95132c15732412d22c1476fa83f27a",
+		"size": 29472,
+		"height": 600,
+		"width": 400,
+		"name": "Marketing Videos",
+		"opacity": 0.75,
+		"padding": 0.1,
+		"scale": 0.1,
+		"position": "center"
+	}
+}`
+
+func TestStream_CreateWatermark(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/watermarks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "Marketing Videos", r.FormValue("name"))
+		assert.Equal(t, "0.75", r.FormValue("opacity"))
+		assert.Equal(t, "center", r.FormValue("position"))
+
+		f, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		content, _ := io.ReadAll(f)
+		assert.Equal(t, "png", string(content))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testWatermarkResponse)
+	})
+
+	got, err := client.StreamCreateWatermark(context.Background(), StreamCreateWatermarkParameters{
+		AccountID: testAccountID,
+		File:      strings.NewReader("png"),
+		FileName:  "logo.png",
+		Name:      "Marketing Videos",
+		Opacity:   0.75,
+		Position:  "center",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ea95132c15732412d22c1476fa83f27a", got.UID)
+		assert.Equal(t, 0.75, got.Opacity)
+	}
+}
+
+func TestStream_CreateWatermarkFromURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/watermarks", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"url": "https://example.com/logo.png", "name": "Marketing Videos"}, body)
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, testWatermarkResponse)
+	})
+
+	_, err := client.StreamCreateWatermark(context.Background(), StreamCreateWatermarkParameters{
+		AccountID: testAccountID,
+		URL:       "https://example.com/logo.png",
+		Name:      "Marketing Videos",
+	})
+	assert.NoError(t, err)
+
+	_, err = client.StreamCreateWatermark(context.Background(), StreamCreateWatermarkParameters{AccountID: testAccountID})
+	assert.ErrorIs(t, err, ErrMissingWatermarkImage)
+}
+
+func TestStream_GetAndDeleteWatermark(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/watermarks/ea95132c15732412d22c1476fa83f27a", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, testWatermarkResponse)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": ""}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	params := StreamWatermarkParameters{AccountID: testAccountID, WatermarkID: "ea95132c15732412d22c1476fa83f27a"}
+	got, err := client.StreamGetWatermark(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Marketing Videos", got.Name)
+	}
+
+	assert.NoError(t, client.StreamDeleteWatermark(context.Background(), params))
+	assert.ErrorIs(t, client.StreamDeleteWatermark(context.Background(), StreamWatermarkParameters{AccountID: testAccountID}), ErrMissingWatermarkID)
+}
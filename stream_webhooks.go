@@ -0,0 +1,150 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var (
+	// ErrMissingWebhookURL is for when the notification URL of a webhook is missing.
+	ErrMissingWebhookURL = errors.New("required webhook notification url missing")
+	// ErrInvalidStreamWebhookSignature is for when a webhook request wasn't
+	// signed with the webhook secret or is too old.
+	ErrInvalidStreamWebhookSignature = errors.New("invalid stream webhook signature")
+)
+
+// StreamWebhook represents the webhook notified when videos are ready or fail.
+type StreamWebhook struct {
+	NotificationURL string     `json:"notificationUrl"`
+	Modified        *time.Time `json:"modified,omitempty"`
+	// Secret signs the webhook requests, see VerifyStreamWebhookSignature.
+	Secret string `json:"secret,omitempty"`
+}
+
+// StreamWebhookParameters are the parameters used when managing the webhook
+// of an account.
+type StreamWebhookParameters struct {
+	AccountID       string `json:"-"`
+	NotificationURL string `json:"notificationUrl,omitempty"`
+}
+
+// StreamWebhookResponse represents an API response of a webhook.
+type StreamWebhookResponse struct {
+	Response
+	Result StreamWebhook `json:"result"`
+}
+
+// StreamSetWebhook sets the URL notified when a video finishes processing.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-webhook-create-webhooks
+func (api *API) StreamSetWebhook(ctx context.Context, params StreamWebhookParameters) (StreamWebhook, error) {
+	if params.AccountID == "" {
+		return StreamWebhook{}, ErrMissingAccountID
+	}
+
+	if params.NotificationURL == "" {
+		return StreamWebhook{}, ErrMissingWebhookURL
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/webhook", params.AccountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, params)
+	if err != nil {
+		return StreamWebhook{}, err
+	}
+
+	var r StreamWebhookResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamWebhook{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamGetWebhook gets the webhook of an account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-webhook-view-webhooks
+func (api *API) StreamGetWebhook(ctx context.Context, params StreamWebhookParameters) (StreamWebhook, error) {
+	if params.AccountID == "" {
+		return StreamWebhook{}, ErrMissingAccountID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/webhook", params.AccountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return StreamWebhook{}, err
+	}
+
+	var r StreamWebhookResponse
+	if err := json.Unmarshal(res, &r); err != nil {
+		return StreamWebhook{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return r.Result, nil
+}
+
+// StreamDeleteWebhook removes the webhook of an account.
+//
+// API Reference: https://developers.cloudflare.com/api/operations/stream-webhook-delete-webhooks
+func (api *API) StreamDeleteWebhook(ctx context.Context, params StreamWebhookParameters) error {
+	if params.AccountID == "" {
+		return ErrMissingAccountID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/stream/webhook", params.AccountID)
+	if _, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyStreamWebhookSignature checks the Webhook-Signature header of a
+// webhook request against its body. Requests signed more than maxAge ago
+// are rejected; a zero maxAge skips that check.
+//
+// API reference: https://developers.cloudflare.com/stream/manage-video-library/using-webhooks/#verify-webhook-authenticity
+func VerifyStreamWebhookSignature(secret, signatureHeader string, body []byte, maxAge time.Duration) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "time":
+			timestamp = v
+		case "sig1":
+			signature = v
+		}
+	}
+
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidStreamWebhookSignature)
+	}
+
+	if maxAge > 0 {
+		sent, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: malformed time", ErrInvalidStreamWebhookSignature)
+		}
+		if time.Since(time.Unix(sent, 0)) > maxAge {
+			return fmt.Errorf("%w: signed more than %s ago", ErrInvalidStreamWebhookSignature, maxAge)
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidStreamWebhookSignature
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Webhook(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/stream/webhook", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodPut:
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"notificationUrl": "https://example.com/webhook"}, body)
+			fallthrough
+		case http.MethodGet:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"notificationUrl": "https://example.com/webhook", "modified": "2014-01-02T02:20:00Z", "secret": "85011ed3a913c6ad5f9cf6c5573cc0a7"}}`)
+		case http.MethodDelete:
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": ""}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	modified, _ := time.Parse(time.RFC3339, "2014-01-02T02:20:00Z")
+	want := StreamWebhook{NotificationURL: "https://example.com/webhook", Modified: &modified, Secret: "85011ed3a913c6ad5f9cf6c5573cc0a7"}
+
+	got, err := client.StreamSetWebhook(context.Background(), StreamWebhookParameters{AccountID: testAccountID, NotificationURL: "https://example.com/webhook"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+
+	got, err = client.StreamGetWebhook(context.Background(), StreamWebhookParameters{AccountID: testAccountID})
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, got)
+	}
+
+	assert.NoError(t, client.StreamDeleteWebhook(context.Background(), StreamWebhookParameters{AccountID: testAccountID}))
+
+	_, err = client.StreamSetWebhook(context.Background(), StreamWebhookParameters{AccountID: testAccountID})
+	assert.ErrorIs(t, err, ErrMissingWebhookURL)
+}
+
+func TestVerifyStreamWebhookSignature(t *testing.T) {
+	secret := "85011ed3a913c6ad5f9cf6c5573cc0a7"
+	body := []byte(`{"uid":"ea95132c15732412d22c1476fa83f27a","readyToStream":true}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(now + "." + string(body)))
+	header := "time=" + now + ",sig1=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.NoError(t, VerifyStreamWebhookSignature(secret, header, body, time.Minute))
+	assert.ErrorIs(t, VerifyStreamWebhookSignature("other", header, body, time.Minute), ErrInvalidStreamWebhookSignature)
+	assert.ErrorIs(t, VerifyStreamWebhookSignature(secret, header, []byte("{}"), time.Minute), ErrInvalidStreamWebhookSignature)
+	assert.ErrorIs(t, VerifyStreamWebhookSignature(secret, "sig1=abc", body, 0), ErrInvalidStreamWebhookSignature)
+
+	old := "time=1230811200,sig1=" + header[len("time="+now+",sig1="):]
+	assert.ErrorIs(t, VerifyStreamWebhookSignature(secret, old, body, time.Minute), ErrInvalidStreamWebhookSignature)
+}
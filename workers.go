@@ -3,6 +3,7 @@ package cloudflare
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -137,6 +138,58 @@ type WorkerScriptResponse struct {
 
 type ListWorkersParams struct{}
 
+type DownloadWorkerParams struct {
+	ScriptName string
+}
+
+// WorkerModule is a single module of a deployed Worker script.
+type WorkerModule struct {
+	// Name is the module name, as referenced by imports in other modules.
+	Name string
+	// ContentType is the MIME type the module was uploaded with, for example
+	// "application/javascript+module" or "application/wasm".
+	ContentType string
+	Content     []byte
+}
+
+// WorkerScriptContent is the full content of a deployed Worker along with
+// the settings required to redeploy it.
+type WorkerScriptContent struct {
+	// Module is true for ES Module syntax Workers.
+	Module bool
+
+	// MainModule is the name of the entrypoint module. The API always
+	// returns the entrypoint as the first part of the response.
+	MainModule string
+
+	// Modules contains every module of the Worker in the order returned by
+	// the API. Service Worker syntax scripts have a single module.
+	Modules []WorkerModule
+
+	// Bindings is keyed by the binding name, matching
+	// CreateWorkerParams.Bindings.
+	Bindings map[string]WorkerBinding
+
+	CompatibilityDate  string
+	CompatibilityFlags []string
+	Logpush            *bool
+	Placement          *Placement
+	TailConsumers      *[]WorkersTailConsumer
+	UsageModel         string
+}
+
+// workerScriptSettings is the configuration of a deployed Worker as returned
+// by the script settings endpoint.
+type workerScriptSettings struct {
+	Bindings           []workerBindingMeta    `json:"bindings"`
+	CompatibilityDate  string                 `json:"compatibility_date"`
+	CompatibilityFlags []string               `json:"compatibility_flags"`
+	Logpush            *bool                  `json:"logpush,omitempty"`
+	Placement          *Placement             `json:"placement,omitempty"`
+	TailConsumers      *[]WorkersTailConsumer `json:"tail_consumers,omitempty"`
+	UsageModel         string                 `json:"usage_model,omitempty"`
+}
+
 type DeleteWorkerParams struct {
 	ScriptName string
 }
@@ -224,6 +277,117 @@ func (api *API) GetWorker(ctx context.Context, rc *ResourceContainer, scriptName
 	return r, nil
 }
 
+// DownloadWorker fetches the content of every module of a deployed Worker
+// along with its bindings, compatibility settings, placement and tail
+// consumers.
+//
+// API reference: https://developers.cloudflare.com/api/operations/worker-script-download-worker
+func (api *API) DownloadWorker(ctx context.Context, rc *ResourceContainer, params DownloadWorkerParams) (WorkerScriptContent, error) {
+	if rc.Level != AccountRouteLevel {
+		return WorkerScriptContent{}, ErrRequiredAccountLevelResourceContainer
+	}
+
+	if rc.Identifier == "" {
+		return WorkerScriptContent{}, ErrMissingAccountID
+	}
+
+	if params.ScriptName == "" {
+		return WorkerScriptContent{}, errors.New("ScriptName is required")
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/workers/scripts/%s", rc.Identifier, params.ScriptName)
+	res, err := api.makeRequestContextWithHeadersComplete(ctx, http.MethodGet, uri, nil, nil)
+	if err != nil {
+		return WorkerScriptContent{}, err
+	}
+
+	var r WorkerScriptContent
+	r.Modules, r.Module, err = parseWorkerModules(res.Headers.Get("content-type"), res.Body)
+	if err != nil {
+		return WorkerScriptContent{}, err
+	}
+	if len(r.Modules) > 0 {
+		r.MainModule = r.Modules[0].Name
+	}
+
+	uri = fmt.Sprintf("/accounts/%s/workers/scripts/%s/settings", rc.Identifier, params.ScriptName)
+	settingsRes, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return WorkerScriptContent{}, err
+	}
+
+	var settings struct {
+		Response
+		Result workerScriptSettings `json:"result"`
+	}
+	err = json.Unmarshal(settingsRes, &settings)
+	if err != nil {
+		return WorkerScriptContent{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	bindings, err := api.decodeWorkerBindings(ctx, rc.Identifier, params.ScriptName, settings.Result.Bindings)
+	if err != nil {
+		return WorkerScriptContent{}, err
+	}
+	r.Bindings = make(map[string]WorkerBinding, len(bindings))
+	for _, b := range bindings {
+		r.Bindings[b.Name] = b.Binding
+	}
+
+	r.CompatibilityDate = settings.Result.CompatibilityDate
+	r.CompatibilityFlags = settings.Result.CompatibilityFlags
+	r.Logpush = settings.Result.Logpush
+	r.Placement = settings.Result.Placement
+	r.TailConsumers = settings.Result.TailConsumers
+	r.UsageModel = settings.Result.UsageModel
+
+	return r, nil
+}
+
+// parseWorkerModules splits a script download response into its modules.
+// Module Workers are returned as a multipart body with one part per module,
+// Service Worker scripts as a single JavaScript body.
+func parseWorkerModules(contentType string, body []byte) ([]WorkerModule, bool, error) {
+	mediaType, mediaParams, _ := mime.ParseMediaType(contentType)
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return []WorkerModule{{
+			Name:        "script",
+			ContentType: "application/javascript",
+			Content:     body,
+		}}, false, nil
+	}
+
+	var modules []WorkerModule
+	mimeReader := multipart.NewReader(bytes.NewReader(body), mediaParams["boundary"])
+	for {
+		part, err := mimeReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("could not get multipart response body: %w", err)
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, true, fmt.Errorf("could not read multipart response body: %w", err)
+		}
+
+		name := part.FormName()
+		if name == "" {
+			name = part.FileName()
+		}
+
+		modules = append(modules, WorkerModule{
+			Name:        name,
+			ContentType: part.Header.Get("content-type"),
+			Content:     content,
+		})
+	}
+
+	return modules, true, nil
+}
+
 // ListWorkers returns list of Workers for given account.
 //
 // API reference: https://developers.cloudflare.com/workers/tooling/api/scripts/
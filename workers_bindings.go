@@ -44,6 +44,18 @@ const (
 	WorkerQueueBindingType WorkerBindingType = "queue"
 	// DispatchNamespaceBindingType is the type for WFP namespace bindings.
 	DispatchNamespaceBindingType WorkerBindingType = "dispatch_namespace"
+	// WorkerD1DatabaseBindingType is the type for D1 database bindings.
+	WorkerD1DatabaseBindingType WorkerBindingType = "d1"
+	// WorkerMTLSCertificateBindingType is the type for mTLS certificate bindings.
+	WorkerMTLSCertificateBindingType WorkerBindingType = "mtls_certificate"
+	// WorkerHyperdriveBindingType is the type for Hyperdrive config bindings.
+	WorkerHyperdriveBindingType WorkerBindingType = "hyperdrive"
+	// WorkerBrowserBindingType is the type for Browser Rendering bindings.
+	WorkerBrowserBindingType WorkerBindingType = "browser"
+	// WorkerAIBindingType is the type for Workers AI bindings.
+	WorkerAIBindingType WorkerBindingType = "ai"
+	// WorkerVersionMetadataBindingType is the type for version metadata bindings.
+	WorkerVersionMetadataBindingType WorkerBindingType = "version_metadata"
 )
 
 type ListWorkerBindingsParams struct {
@@ -408,6 +420,130 @@ func (b DispatchNamespaceBinding) serialize(bindingName string) (workerBindingMe
 	return meta, nil, nil
 }
 
+// WorkerD1DatabaseBinding is a binding to a D1 database.
+//
+// https://developers.cloudflare.com/workers/configuration/bindings/#d1-database-bindings
+type WorkerD1DatabaseBinding struct {
+	DatabaseID string
+}
+
+// Type returns the type of the binding.
+func (b WorkerD1DatabaseBinding) Type() WorkerBindingType {
+	return WorkerD1DatabaseBindingType
+}
+
+func (b WorkerD1DatabaseBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	if b.DatabaseID == "" {
+		return nil, nil, fmt.Errorf(`DatabaseID for binding "%s" cannot be empty`, bindingName)
+	}
+
+	return workerBindingMeta{
+		"name": bindingName,
+		"type": b.Type(),
+		"id":   b.DatabaseID,
+	}, nil, nil
+}
+
+// WorkerMTLSCertificateBinding is a binding to an uploaded mTLS client
+// certificate, used when making subrequests that require client
+// authentication.
+//
+// https://developers.cloudflare.com/workers/runtime-apis/mtls/
+type WorkerMTLSCertificateBinding struct {
+	CertificateID string
+}
+
+// Type returns the type of the binding.
+func (b WorkerMTLSCertificateBinding) Type() WorkerBindingType {
+	return WorkerMTLSCertificateBindingType
+}
+
+func (b WorkerMTLSCertificateBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	if b.CertificateID == "" {
+		return nil, nil, fmt.Errorf(`CertificateID for binding "%s" cannot be empty`, bindingName)
+	}
+
+	return workerBindingMeta{
+		"name":           bindingName,
+		"type":           b.Type(),
+		"certificate_id": b.CertificateID,
+	}, nil, nil
+}
+
+// WorkerHyperdriveBinding is a binding to a Hyperdrive configuration.
+//
+// https://developers.cloudflare.com/hyperdrive/
+type WorkerHyperdriveBinding struct {
+	ConfigID string
+}
+
+// Type returns the type of the binding.
+func (b WorkerHyperdriveBinding) Type() WorkerBindingType {
+	return WorkerHyperdriveBindingType
+}
+
+func (b WorkerHyperdriveBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	if b.ConfigID == "" {
+		return nil, nil, fmt.Errorf(`ConfigID for binding "%s" cannot be empty`, bindingName)
+	}
+
+	return workerBindingMeta{
+		"name": bindingName,
+		"type": b.Type(),
+		"id":   b.ConfigID,
+	}, nil, nil
+}
+
+// WorkerBrowserBinding is a binding to the Browser Rendering API.
+//
+// https://developers.cloudflare.com/browser-rendering/
+type WorkerBrowserBinding struct{}
+
+// Type returns the type of the binding.
+func (b WorkerBrowserBinding) Type() WorkerBindingType {
+	return WorkerBrowserBindingType
+}
+
+func (b WorkerBrowserBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	return workerBindingMeta{
+		"name": bindingName,
+		"type": b.Type(),
+	}, nil, nil
+}
+
+// WorkerAIBinding is a binding to Workers AI.
+//
+// https://developers.cloudflare.com/workers-ai/
+type WorkerAIBinding struct{}
+
+// Type returns the type of the binding.
+func (b WorkerAIBinding) Type() WorkerBindingType {
+	return WorkerAIBindingType
+}
+
+func (b WorkerAIBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	return workerBindingMeta{
+		"name": bindingName,
+		"type": b.Type(),
+	}, nil, nil
+}
+
+// WorkerVersionMetadataBinding exposes the ID and tag of the running
+// Worker version to the script.
+type WorkerVersionMetadataBinding struct{}
+
+// Type returns the type of the binding.
+func (b WorkerVersionMetadataBinding) Type() WorkerBindingType {
+	return WorkerVersionMetadataBindingType
+}
+
+func (b WorkerVersionMetadataBinding) serialize(bindingName string) (workerBindingMeta, workerBindingBodyWriter, error) {
+	return workerBindingMeta{
+		"name": bindingName,
+		"type": b.Type(),
+	}, nil, nil
+}
+
 // UnsafeBinding is for experimental or deprecated bindings, and allows specifying any binding type or property.
 type UnsafeBinding map[string]interface{}
 
@@ -459,18 +595,32 @@ func (api *API) ListWorkerBindings(ctx context.Context, rc *ResourceContainer, p
 		return r, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
+	bindingList, err := api.decodeWorkerBindings(ctx, rc.Identifier, params.ScriptName, jsonRes.Bindings)
+	if err != nil {
+		return r, err
+	}
+
 	r = WorkerBindingListResponse{
 		Response:    jsonRes.Response,
-		BindingList: make([]WorkerBindingListItem, 0, len(jsonRes.Bindings)),
+		BindingList: bindingList,
 	}
-	for _, jsonBinding := range jsonRes.Bindings {
+
+	return r, nil
+}
+
+// decodeWorkerBindings converts the binding metadata returned by the API into
+// the matching WorkerBinding implementations. Unknown binding types are
+// represented as a WorkerInheritBinding.
+func (api *API) decodeWorkerBindings(ctx context.Context, accountID, scriptName string, bindings []workerBindingMeta) ([]WorkerBindingListItem, error) {
+	bindingList := make([]WorkerBindingListItem, 0, len(bindings))
+	for _, jsonBinding := range bindings {
 		name, ok := jsonBinding["name"].(string)
 		if !ok {
-			return r, fmt.Errorf("Binding missing name %v", jsonBinding)
+			return nil, fmt.Errorf("Binding missing name %v", jsonBinding)
 		}
 		bType, ok := jsonBinding["type"].(string)
 		if !ok {
-			return r, fmt.Errorf("Binding missing type %v", jsonBinding)
+			return nil, fmt.Errorf("Binding missing type %v", jsonBinding)
 		}
 		bindingListItem := WorkerBindingListItem{
 			Name: name,
@@ -500,8 +650,8 @@ func (api *API) ListWorkerBindings(ctx context.Context, rc *ResourceContainer, p
 				Module: &bindingContentReader{
 					api:         api,
 					ctx:         ctx,
-					accountID:   rc.Identifier,
-					params:      &params,
+					accountID:   accountID,
+					params:      &ListWorkerBindingsParams{ScriptName: scriptName},
 					bindingName: name,
 				},
 			}
@@ -529,13 +679,34 @@ func (api *API) ListWorkerBindings(ctx context.Context, rc *ResourceContainer, p
 			bindingListItem.Binding = WorkerAnalyticsEngineBinding{
 				Dataset: dataset,
 			}
+		case WorkerD1DatabaseBindingType:
+			databaseID := jsonBinding["id"].(string)
+			bindingListItem.Binding = WorkerD1DatabaseBinding{
+				DatabaseID: databaseID,
+			}
+		case WorkerMTLSCertificateBindingType:
+			certificateID := jsonBinding["certificate_id"].(string)
+			bindingListItem.Binding = WorkerMTLSCertificateBinding{
+				CertificateID: certificateID,
+			}
+		case WorkerHyperdriveBindingType:
+			configID := jsonBinding["id"].(string)
+			bindingListItem.Binding = WorkerHyperdriveBinding{
+				ConfigID: configID,
+			}
+		case WorkerBrowserBindingType:
+			bindingListItem.Binding = WorkerBrowserBinding{}
+		case WorkerAIBindingType:
+			bindingListItem.Binding = WorkerAIBinding{}
+		case WorkerVersionMetadataBindingType:
+			bindingListItem.Binding = WorkerVersionMetadataBinding{}
 		default:
 			bindingListItem.Binding = WorkerInheritBinding{}
 		}
-		r.BindingList = append(r.BindingList, bindingListItem)
+		bindingList = append(bindingList, bindingListItem)
 	}
 
-	return r, nil
+	return bindingList, nil
 }
 
 // bindingContentReader is an io.Reader that will lazily load the
@@ -35,7 +35,7 @@ func TestListWorkerBindings(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, successResponse, res.Response)
-	assert.Equal(t, 8, len(res.BindingList))
+	assert.Equal(t, 14, len(res.BindingList))
 
 	assert.Equal(t, res.BindingList[0], WorkerBindingListItem{
 		Name: "MY_KV",
@@ -97,6 +97,48 @@ func TestListWorkerBindings(t *testing.T) {
 		},
 	})
 	assert.Equal(t, WorkerAnalyticsEngineBindingType, res.BindingList[7].Binding.Type())
+
+	assert.Equal(t, res.BindingList[8], WorkerBindingListItem{
+		Name: "MY_DATABASE",
+		Binding: WorkerD1DatabaseBinding{
+			DatabaseID: "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e",
+		},
+	})
+	assert.Equal(t, WorkerD1DatabaseBindingType, res.BindingList[8].Binding.Type())
+
+	assert.Equal(t, res.BindingList[9], WorkerBindingListItem{
+		Name: "MY_CERT",
+		Binding: WorkerMTLSCertificateBinding{
+			CertificateID: "efwu2n6s-q69d-2kr9-184j-4913e8h391k6",
+		},
+	})
+	assert.Equal(t, WorkerMTLSCertificateBindingType, res.BindingList[9].Binding.Type())
+
+	assert.Equal(t, res.BindingList[10], WorkerBindingListItem{
+		Name: "MY_HYPERDRIVE",
+		Binding: WorkerHyperdriveBinding{
+			ConfigID: "a76a99bc342644deb02c38d66082262a",
+		},
+	})
+	assert.Equal(t, WorkerHyperdriveBindingType, res.BindingList[10].Binding.Type())
+
+	assert.Equal(t, res.BindingList[11], WorkerBindingListItem{
+		Name:    "MY_BROWSER",
+		Binding: WorkerBrowserBinding{},
+	})
+	assert.Equal(t, WorkerBrowserBindingType, res.BindingList[11].Binding.Type())
+
+	assert.Equal(t, res.BindingList[12], WorkerBindingListItem{
+		Name:    "MY_AI",
+		Binding: WorkerAIBinding{},
+	})
+	assert.Equal(t, WorkerAIBindingType, res.BindingList[12].Binding.Type())
+
+	assert.Equal(t, res.BindingList[13], WorkerBindingListItem{
+		Name:    "MY_VERSION",
+		Binding: WorkerVersionMetadataBinding{},
+	})
+	assert.Equal(t, WorkerVersionMetadataBindingType, res.BindingList[13].Binding.Type())
 }
 
 func ExampleUnsafeBinding() {
@@ -140,6 +140,33 @@ const (
 				"name": "MY_DATASET",
 				"type": "analytics_engine",
 				"dataset": "my_dataset"
+			},
+			{
+				"name": "MY_DATABASE",
+				"type": "d1",
+				"id": "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e"
+			},
+			{
+				"name": "MY_CERT",
+				"type": "mtls_certificate",
+				"certificate_id": "efwu2n6s-q69d-2kr9-184j-4913e8h391k6"
+			},
+			{
+				"name": "MY_HYPERDRIVE",
+				"type": "hyperdrive",
+				"id": "a76a99bc342644deb02c38d66082262a"
+			},
+			{
+				"name": "MY_BROWSER",
+				"type": "browser"
+			},
+			{
+				"name": "MY_AI",
+				"type": "ai"
+			},
+			{
+				"name": "MY_VERSION",
+				"type": "version_metadata"
 			}
 		],
 		"success": true,
@@ -491,6 +518,108 @@ func TestGetWorker_Module(t *testing.T) {
 	}
 }
 
+func TestDownloadWorker(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/workers/scripts/foo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "multipart/form-data; boundary=workermodulescriptdownload")
+		fmt.Fprint(w, `
+--workermodulescriptdownload
+Content-Disposition: form-data; name="worker.js"
+Content-Type: application/javascript+module
+
+import { greet } from "./greet.js";
+export default { fetch: () => new Response(greet()) };
+--workermodulescriptdownload
+Content-Disposition: form-data; name="greet.js"
+Content-Type: application/javascript+module
+
+export const greet = () => "hello";
+--workermodulescriptdownload--
+`)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/workers/scripts/foo/settings", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"result": {
+				"bindings": [
+					{"name": "MY_KV", "type": "kv_namespace", "namespace_id": "89f5f8fd93f94cb98473f6f421aa3b65"},
+					{"name": "DB", "type": "d1", "id": "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e"}
+				],
+				"compatibility_date": "2023-09-04",
+				"compatibility_flags": ["nodejs_compat"],
+				"logpush": true,
+				"placement": {"mode": "smart"},
+				"tail_consumers": [{"service": "my-tail"}],
+				"usage_model": "bundled"
+			},
+			"success": true,
+			"errors": [],
+			"messages": []
+		}`)
+	})
+
+	res, err := client.DownloadWorker(context.Background(), AccountIdentifier(testAccountID), DownloadWorkerParams{ScriptName: "foo"})
+	logpush := true
+	want := WorkerScriptContent{
+		Module:     true,
+		MainModule: "worker.js",
+		Modules: []WorkerModule{
+			{
+				Name:        "worker.js",
+				ContentType: "application/javascript+module",
+				Content:     []byte("import { greet } from \"./greet.js\";\nexport default { fetch: () => new Response(greet()) };"),
+			},
+			{
+				Name:        "greet.js",
+				ContentType: "application/javascript+module",
+				Content:     []byte("export const greet = () => \"hello\";"),
+			},
+		},
+		Bindings: map[string]WorkerBinding{
+			"MY_KV": WorkerKvNamespaceBinding{NamespaceID: "89f5f8fd93f94cb98473f6f421aa3b65"},
+			"DB":    WorkerD1DatabaseBinding{DatabaseID: "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e"},
+		},
+		CompatibilityDate:  "2023-09-04",
+		CompatibilityFlags: []string{"nodejs_compat"},
+		Logpush:            &logpush,
+		Placement:          &Placement{Mode: PlacementModeSmart},
+		TailConsumers:      &[]WorkersTailConsumer{{Service: "my-tail"}},
+		UsageModel:         "bundled",
+	}
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, res)
+	}
+}
+
+func TestDownloadWorker_ServiceWorker(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/workers/scripts/foo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/javascript")
+		fmt.Fprint(w, workerScript)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/workers/scripts/foo/settings", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"result": {"bindings": []}, "success": true, "errors": [], "messages": []}`)
+	})
+
+	res, err := client.DownloadWorker(context.Background(), AccountIdentifier(testAccountID), DownloadWorkerParams{ScriptName: "foo"})
+	if assert.NoError(t, err) {
+		assert.False(t, res.Module)
+		assert.Equal(t, "script", res.MainModule)
+		assert.Equal(t, []WorkerModule{{Name: "script", ContentType: "application/javascript", Content: []byte(workerScript)}}, res.Modules)
+		assert.Empty(t, res.Bindings)
+	}
+}
+
 func TestListWorkers(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1113,6 +1242,67 @@ func TestUploadWorker_WithDispatchNamespaceBinding(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUploadWorker_WithPlatformBindings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+
+		mpUpload, err := parseMultipartUpload(r)
+		assert.NoError(t, err)
+
+		expectedBindings := map[string]workerBindingMeta{
+			"DB": {
+				"name": "DB",
+				"type": "d1",
+				"id":   "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e",
+			},
+			"CERT": {
+				"name":           "CERT",
+				"type":           "mtls_certificate",
+				"certificate_id": "efwu2n6s-q69d-2kr9-184j-4913e8h391k6",
+			},
+			"HYPERDRIVE": {
+				"name": "HYPERDRIVE",
+				"type": "hyperdrive",
+				"id":   "a76a99bc342644deb02c38d66082262a",
+			},
+			"BROWSER": {
+				"name": "BROWSER",
+				"type": "browser",
+			},
+			"AI": {
+				"name": "AI",
+				"type": "ai",
+			},
+			"VERSION": {
+				"name": "VERSION",
+				"type": "version_metadata",
+			},
+		}
+		assert.Equal(t, workerScript, mpUpload.Script)
+		assert.Equal(t, expectedBindings, mpUpload.BindingMeta)
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, workersScriptResponse(t))
+	}
+	mux.HandleFunc("/accounts/"+testAccountID+"/workers/scripts/bar", handler)
+
+	_, err := client.UploadWorker(context.Background(), AccountIdentifier(testAccountID), CreateWorkerParams{
+		ScriptName: "bar",
+		Script:     workerScript,
+		Bindings: map[string]WorkerBinding{
+			"DB":         WorkerD1DatabaseBinding{DatabaseID: "4ae8e06c-4c8e-4ae1-b1e7-7b2a8a1c9b0e"},
+			"CERT":       WorkerMTLSCertificateBinding{CertificateID: "efwu2n6s-q69d-2kr9-184j-4913e8h391k6"},
+			"HYPERDRIVE": WorkerHyperdriveBinding{ConfigID: "a76a99bc342644deb02c38d66082262a"},
+			"BROWSER":    WorkerBrowserBinding{},
+			"AI":         WorkerAIBinding{},
+			"VERSION":    WorkerVersionMetadataBinding{},
+		}})
+	assert.NoError(t, err)
+}
+
 func TestUploadWorker_WithSmartPlacementEnabled(t *testing.T) {
 	setup()
 	defer teardown()
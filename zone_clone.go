@@ -0,0 +1,609 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// ZoneConfiguration is a snapshot of the configuration of a zone which can
+// be applied to another zone. Nil fields aren't managed: they are neither
+// compared nor changed.
+type ZoneConfiguration struct {
+	// Settings holds the editable settings of the zone.
+	Settings []ZoneSetting `json:"settings,omitempty"`
+	// Rulesets holds the entrypoint rulesets of the zone, one per phase.
+	Rulesets       []Ruleset                `json:"rulesets,omitempty"`
+	PageRules      []PageRule               `json:"page_rules,omitempty"`
+	ManagedHeaders *ManagedHeaders          `json:"managed_headers,omitempty"`
+	TieredCache    *TieredCacheType         `json:"tiered_cache,omitempty"`
+	CacheVariants  *ZoneCacheVariantsValues `json:"cache_variants,omitempty"`
+	CustomPages    []CustomPage             `json:"custom_pages,omitempty"`
+}
+
+// defaultZoneConfigurationPhases are the phases of the entrypoint rulesets
+// SnapshotZoneConfiguration fetches by default.
+var defaultZoneConfigurationPhases = []RulesetPhase{
+	RulesetPhaseHTTPRequestFirewallCustom,
+	RulesetPhaseHTTPRequestFirewallManaged,
+	RulesetPhaseRateLimit,
+	RulesetPhaseHTTPRequestTransform,
+	RulesetPhaseHTTPRequestLateTransform,
+	RulesetPhaseHTTPResponseHeadersTransform,
+	RulesetPhaseHTTPRequestDynamicRedirect,
+	RulesetPhaseHTTPRequestOrigin,
+	RulesetPhaseHTTPRequestCacheSettings,
+	RulesetPhaseHTTPConfigSettings,
+	RulesetPhaseHTTPCustomErrors,
+	RulesetPhaseHTTPResponseCompression,
+}
+
+type SnapshotZoneConfigurationParams struct {
+	// Phases are the phases of the entrypoint rulesets to fetch. Defaults
+	// to the phases of the rulesets configurable on zones.
+	Phases []RulesetPhase
+}
+
+// SnapshotZoneConfiguration fetches the configuration of a zone. Phases
+// without an entrypoint ruleset are skipped, and a zone without cache
+// variants has empty ones.
+func (api *API) SnapshotZoneConfiguration(ctx context.Context, rc *ResourceContainer, params SnapshotZoneConfigurationParams) (ZoneConfiguration, error) {
+	if rc.Identifier == "" {
+		return ZoneConfiguration{}, ErrMissingZoneID
+	}
+
+	var config ZoneConfiguration
+	var notFound *NotFoundError
+
+	settings, err := api.ZoneSettings(ctx, rc.Identifier)
+	if err != nil {
+		return ZoneConfiguration{}, err
+	}
+	for _, s := range settings.Result {
+		if s.Editable {
+			config.Settings = append(config.Settings, ZoneSetting{ID: s.ID, Editable: true, Value: s.Value})
+		}
+	}
+
+	phases := params.Phases
+	if len(phases) == 0 {
+		phases = defaultZoneConfigurationPhases
+	}
+	for _, phase := range phases {
+		ruleset, err := api.GetEntrypointRuleset(ctx, rc, string(phase))
+		if errors.As(err, &notFound) {
+			continue
+		}
+		if err != nil {
+			return ZoneConfiguration{}, err
+		}
+		ruleset.Phase = string(phase)
+		config.Rulesets = append(config.Rulesets, ruleset)
+	}
+
+	if config.PageRules, err = api.ListPageRules(ctx, rc.Identifier); err != nil {
+		return ZoneConfiguration{}, err
+	}
+
+	headers, err := api.ListZoneManagedHeaders(ctx, rc, ListManagedHeadersParams{})
+	if err != nil {
+		return ZoneConfiguration{}, err
+	}
+	config.ManagedHeaders = &headers
+
+	tieredCache, err := api.GetTieredCache(ctx, rc)
+	if err != nil {
+		return ZoneConfiguration{}, err
+	}
+	config.TieredCache = &tieredCache.Type
+
+	variants, err := api.ZoneCacheVariants(ctx, rc.Identifier)
+	if err != nil && !errors.As(err, &notFound) {
+		return ZoneConfiguration{}, err
+	}
+	config.CacheVariants = &variants.Value
+
+	if config.CustomPages, err = api.CustomPages(ctx, &CustomPageOptions{ZoneID: rc.Identifier}); err != nil {
+		return ZoneConfiguration{}, err
+	}
+
+	return config, nil
+}
+
+// RewriteHostnames returns a copy of the configuration with the hostname
+// from, and its subdomains, replaced by to in the rulesets, page rules and
+// custom pages. Hostnames are matched ignoring case, and only as a whole:
+// rewriting example.com leaves myexample.com and example.com.au unchanged.
+func (c ZoneConfiguration) RewriteHostnames(from, to string) (ZoneConfiguration, error) {
+	if from == "" || from == to {
+		return c, nil
+	}
+
+	rewritten := c
+	// Decode into new slices, leaving the ones of c unchanged.
+	rewritten.Rulesets, rewritten.PageRules, rewritten.CustomPages = nil, nil, nil
+	if err := rewriteHostnamesJSON(c.Rulesets, &rewritten.Rulesets, from, to); err != nil {
+		return ZoneConfiguration{}, err
+	}
+	if err := rewriteHostnamesJSON(c.PageRules, &rewritten.PageRules, from, to); err != nil {
+		return ZoneConfiguration{}, err
+	}
+	if err := rewriteHostnamesJSON(c.CustomPages, &rewritten.CustomPages, from, to); err != nil {
+		return ZoneConfiguration{}, err
+	}
+	return rewritten, nil
+}
+
+// rewriteHostnamesJSON rewrites the hostnames in the JSON encoding of in,
+// decoding the result into out.
+func rewriteHostnamesJSON(in, out interface{}, from, to string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Escaped characters would look like parts of hostnames.
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(in); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(rewriteHostname(buf.String(), from, to)), out); err != nil {
+		return fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return nil
+}
+
+// rewriteHostname replaces the hostname from by to in s wherever it isn't
+// part of a longer label or hostname. Subdomains of from are rewritten.
+func rewriteHostname(s, from, to string) string {
+	lower, from := asciiLower(s), asciiLower(from)
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(s); {
+		j := strings.Index(lower[i:], from)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(from)
+		i = start + 1
+
+		if start > 0 && isHostnameLabelChar(s[start-1]) {
+			continue
+		}
+		if end < len(s) && (isHostnameLabelChar(s[end]) || s[end] == '.' && end+1 < len(s) && isHostnameLabelChar(s[end+1])) {
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		b.WriteString(to)
+		last, i = end, end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isHostnameLabelChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// asciiLower lowercases the ASCII letters of s, keeping byte offsets.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// ZoneConfigurationResource is the kind of resource of a zone configuration
+// change.
+type ZoneConfigurationResource string
+
+const (
+	ZoneConfigurationSetting       ZoneConfigurationResource = "setting"
+	ZoneConfigurationRuleset       ZoneConfigurationResource = "ruleset"
+	ZoneConfigurationPageRule      ZoneConfigurationResource = "page_rule"
+	ZoneConfigurationManagedHeader ZoneConfigurationResource = "managed_header"
+	ZoneConfigurationTieredCache   ZoneConfigurationResource = "tiered_cache"
+	ZoneConfigurationCacheVariants ZoneConfigurationResource = "cache_variants"
+	ZoneConfigurationCustomPage    ZoneConfigurationResource = "custom_page"
+)
+
+// ZoneConfigurationAction is the change made to a resource.
+type ZoneConfigurationAction string
+
+const (
+	ZoneConfigurationCreate ZoneConfigurationAction = "create"
+	ZoneConfigurationUpdate ZoneConfigurationAction = "update"
+	ZoneConfigurationDelete ZoneConfigurationAction = "delete"
+)
+
+// ZoneConfigurationChange is a change made to a zone to apply a
+// configuration.
+type ZoneConfigurationChange struct {
+	Resource ZoneConfigurationResource
+	// ID identifies the resource: the ID of settings, managed headers and
+	// custom pages, the phase of rulesets and the URL pattern of page
+	// rules.
+	ID     string
+	Action ZoneConfigurationAction
+}
+
+// ZoneConfigurationPlan describes the changes made to a zone to apply a
+// configuration, in the order they are made.
+type ZoneConfigurationPlan struct {
+	Changes []ZoneConfigurationChange
+	// ReadOnlySettings holds the settings which differ but can't be edited
+	// on the zone, such as settings its plan doesn't include.
+	ReadOnlySettings []ZoneSettingChange
+}
+
+type ApplyZoneConfigurationParams struct {
+	Configuration ZoneConfiguration
+	// DeletePageRules deletes the page rules of the zone the configuration
+	// doesn't hold.
+	DeletePageRules bool
+	// DryRun only computes the plan.
+	DryRun bool
+}
+
+// ApplyZoneConfiguration makes the configuration of a zone match a
+// snapshot. Entrypoint rulesets are replaced as a whole, page rules are
+// matched by their targets, and managed headers and custom pages missing
+// from the snapshot are left unchanged.
+//
+// The plan is returned along with the error of the first change which
+// failed; the changes before it were made.
+func (api *API) ApplyZoneConfiguration(ctx context.Context, rc *ResourceContainer, params ApplyZoneConfigurationParams) (ZoneConfigurationPlan, error) {
+	if rc.Identifier == "" {
+		return ZoneConfigurationPlan{}, ErrMissingZoneID
+	}
+
+	var plan ZoneConfigurationPlan
+	var steps []func() error
+	change := func(resource ZoneConfigurationResource, id string, action ZoneConfigurationAction) {
+		plan.Changes = append(plan.Changes, ZoneConfigurationChange{Resource: resource, ID: id, Action: action})
+	}
+	config := params.Configuration
+
+	if config.Settings != nil {
+		current, err := api.ZoneSettings(ctx, rc.Identifier)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		values := make(map[string]interface{}, len(config.Settings))
+		for _, s := range config.Settings {
+			if values[s.ID], err = normalizeZoneSettingValue(s.Value); err != nil {
+				return ZoneConfigurationPlan{}, err
+			}
+		}
+		changes, err := diffZoneSettingValues(current.Result, values)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+
+		var items []ZoneSetting
+		for _, c := range changes {
+			if !c.Editable {
+				plan.ReadOnlySettings = append(plan.ReadOnlySettings, c)
+				continue
+			}
+			change(ZoneConfigurationSetting, c.ID, ZoneConfigurationUpdate)
+			items = append(items, ZoneSetting{ID: c.ID, Value: c.Desired})
+		}
+		if len(items) > 0 {
+			steps = append(steps, func() error {
+				_, err := api.UpdateZoneSettings(ctx, rc.Identifier, items)
+				return err
+			})
+		}
+	}
+
+	for _, ruleset := range config.Rulesets {
+		ruleset := ruleset
+		current, err := api.GetEntrypointRuleset(ctx, rc, ruleset.Phase)
+		var notFound *NotFoundError
+		exists := !errors.As(err, &notFound)
+		if err != nil && exists {
+			return ZoneConfigurationPlan{}, err
+		}
+
+		rules := cloneRulesetRules(ruleset.Rules)
+		if exists && DiffRulesets(current, Ruleset{Rules: rules}).IsEmpty() {
+			continue
+		}
+		if exists {
+			change(ZoneConfigurationRuleset, ruleset.Phase, ZoneConfigurationUpdate)
+		} else {
+			change(ZoneConfigurationRuleset, ruleset.Phase, ZoneConfigurationCreate)
+		}
+		steps = append(steps, func() error {
+			_, err := api.UpdateEntrypointRuleset(ctx, rc, UpdateEntrypointRulesetParams{
+				Phase:       ruleset.Phase,
+				Description: ruleset.Description,
+				Rules:       rules,
+			})
+			return err
+		})
+	}
+
+	if config.PageRules != nil || params.DeletePageRules {
+		current, err := api.ListPageRules(ctx, rc.Identifier)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		currentByTargets := make(map[string]PageRule, len(current))
+		for _, r := range current {
+			key, err := pageRuleTargetsKey(r)
+			if err != nil {
+				return ZoneConfigurationPlan{}, err
+			}
+			currentByTargets[key] = r
+		}
+
+		for _, r := range config.PageRules {
+			key, err := pageRuleTargetsKey(r)
+			if err != nil {
+				return ZoneConfigurationPlan{}, err
+			}
+			rule := PageRule{Targets: r.Targets, Actions: r.Actions, Priority: r.Priority, Status: r.Status}
+
+			existing, ok := currentByTargets[key]
+			delete(currentByTargets, key)
+			if !ok {
+				change(ZoneConfigurationPageRule, pageRuleURL(r), ZoneConfigurationCreate)
+				steps = append(steps, func() error {
+					_, err := api.CreatePageRule(ctx, rc.Identifier, rule)
+					return err
+				})
+				continue
+			}
+
+			same, err := pageRulesEqual(existing, rule)
+			if err != nil {
+				return ZoneConfigurationPlan{}, err
+			}
+			if same {
+				continue
+			}
+			change(ZoneConfigurationPageRule, pageRuleURL(r), ZoneConfigurationUpdate)
+			steps = append(steps, func() error {
+				return api.UpdatePageRule(ctx, rc.Identifier, existing.ID, rule)
+			})
+		}
+
+		if params.DeletePageRules {
+			// Delete in the order the zone lists them.
+			for _, r := range current {
+				r := r
+				key, _ := pageRuleTargetsKey(r)
+				if _, ok := currentByTargets[key]; !ok {
+					continue
+				}
+				change(ZoneConfigurationPageRule, pageRuleURL(r), ZoneConfigurationDelete)
+				steps = append(steps, func() error {
+					return api.DeletePageRule(ctx, rc.Identifier, r.ID)
+				})
+			}
+		}
+	}
+
+	if config.ManagedHeaders != nil {
+		current, err := api.ListZoneManagedHeaders(ctx, rc, ListManagedHeadersParams{})
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		request := diffManagedHeaders(current.ManagedRequestHeaders, config.ManagedHeaders.ManagedRequestHeaders)
+		response := diffManagedHeaders(current.ManagedResponseHeaders, config.ManagedHeaders.ManagedResponseHeaders)
+		for _, h := range request {
+			change(ZoneConfigurationManagedHeader, h.ID, ZoneConfigurationUpdate)
+		}
+		for _, h := range response {
+			change(ZoneConfigurationManagedHeader, h.ID, ZoneConfigurationUpdate)
+		}
+		if len(request) > 0 || len(response) > 0 {
+			steps = append(steps, func() error {
+				_, err := api.UpdateZoneManagedHeaders(ctx, rc, UpdateManagedHeadersParams{
+					ManagedHeaders: ManagedHeaders{ManagedRequestHeaders: request, ManagedResponseHeaders: response},
+				})
+				return err
+			})
+		}
+	}
+
+	if config.TieredCache != nil {
+		current, err := api.GetTieredCache(ctx, rc)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		if current.Type != *config.TieredCache {
+			value := *config.TieredCache
+			change(ZoneConfigurationTieredCache, value.String(), ZoneConfigurationUpdate)
+			steps = append(steps, func() error {
+				_, err := api.SetTieredCache(ctx, rc, value)
+				return err
+			})
+		}
+	}
+
+	if config.CacheVariants != nil {
+		current, err := api.ZoneCacheVariants(ctx, rc.Identifier)
+		var notFound *NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return ZoneConfigurationPlan{}, err
+		}
+		value := *config.CacheVariants
+		empty := reflect.DeepEqual(value, ZoneCacheVariantsValues{})
+		switch {
+		case reflect.DeepEqual(current.Value, value):
+		case empty:
+			change(ZoneConfigurationCacheVariants, "", ZoneConfigurationDelete)
+			steps = append(steps, func() error {
+				return api.DeleteZoneCacheVariants(ctx, rc.Identifier)
+			})
+		default:
+			change(ZoneConfigurationCacheVariants, "", ZoneConfigurationUpdate)
+			steps = append(steps, func() error {
+				_, err := api.UpdateZoneCacheVariants(ctx, rc.Identifier, value)
+				return err
+			})
+		}
+	}
+
+	if config.CustomPages != nil {
+		current, err := api.CustomPages(ctx, &CustomPageOptions{ZoneID: rc.Identifier})
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		currentByID := make(map[string]CustomPage, len(current))
+		for _, p := range current {
+			currentByID[p.ID] = p
+		}
+		for _, p := range config.CustomPages {
+			if existing, ok := currentByID[p.ID]; ok && existing.State == p.State && reflect.DeepEqual(existing.URL, p.URL) {
+				continue
+			}
+			id, page := p.ID, CustomPageParameters{URL: p.URL, State: p.State}
+			change(ZoneConfigurationCustomPage, id, ZoneConfigurationUpdate)
+			steps = append(steps, func() error {
+				_, err := api.UpdateCustomPage(ctx, &CustomPageOptions{ZoneID: rc.Identifier}, id, page)
+				return err
+			})
+		}
+	}
+
+	if params.DryRun {
+		return plan, nil
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// cloneRulesetRules returns copies of rules without the fields set by the
+// API. Rules without a ref are given their ID as ref, so that applying
+// them again matches the rules created.
+func cloneRulesetRules(rules []RulesetRule) []RulesetRule {
+	cloned := make([]RulesetRule, 0, len(rules))
+	for _, r := range rules {
+		r.Ref = rulesetRuleKey(r)
+		r.ID = ""
+		r.Version = nil
+		r.LastUpdated = nil
+		cloned = append(cloned, r)
+	}
+	return cloned
+}
+
+func pageRuleTargetsKey(r PageRule) (string, error) {
+	b, err := json.Marshal(r.Targets)
+	if err != nil {
+		return "", err
+	}
+	return asciiLower(string(b)), nil
+}
+
+// pageRuleURL returns the URL pattern of a page rule.
+func pageRuleURL(r PageRule) string {
+	for _, t := range r.Targets {
+		if t.Target == "url" {
+			return t.Constraint.Value
+		}
+	}
+	return ""
+}
+
+// pageRulesEqual compares the actions, priority and status of page rules.
+// Actions are compared regardless of their order.
+func pageRulesEqual(a, b PageRule) (bool, error) {
+	if a.Priority != b.Priority || a.Status != b.Status || len(a.Actions) != len(b.Actions) {
+		return false, nil
+	}
+	actions := func(r PageRule) (interface{}, error) {
+		sorted := append([]PageRuleAction(nil), r.Actions...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+		return normalizeZoneSettingValue(sorted)
+	}
+	aActions, err := actions(a)
+	if err != nil {
+		return false, err
+	}
+	bActions, err := actions(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aActions, bActions), nil
+}
+
+// diffManagedHeaders returns the desired headers whose enabled state
+// differs from the current one. Headers the zone doesn't have are skipped.
+func diffManagedHeaders(current, desired []ManagedHeader) []ManagedHeader {
+	enabled := make(map[string]bool, len(current))
+	for _, h := range current {
+		enabled[h.ID] = h.Enabled
+	}
+	var changed []ManagedHeader
+	for _, h := range desired {
+		if e, ok := enabled[h.ID]; ok && e != h.Enabled {
+			changed = append(changed, ManagedHeader{ID: h.ID, Enabled: h.Enabled})
+		}
+	}
+	return changed
+}
+
+type CloneZoneConfigurationParams struct {
+	// Phases are the phases of the entrypoint rulesets to clone. Defaults
+	// to the phases of the rulesets configurable on zones.
+	Phases []RulesetPhase
+	// RewriteHostnames replaces the name of the source zone by the name of
+	// the target zone in the rulesets, page rules and custom pages.
+	RewriteHostnames bool
+	// DeletePageRules deletes the page rules of the target zone the source
+	// zone doesn't have.
+	DeletePageRules bool
+	// DryRun only computes the plan.
+	DryRun bool
+}
+
+// CloneZoneConfiguration makes the configuration of the target zone match
+// the one of the source zone. See SnapshotZoneConfiguration and
+// ApplyZoneConfiguration.
+func (api *API) CloneZoneConfiguration(ctx context.Context, source, target *ResourceContainer, params CloneZoneConfigurationParams) (ZoneConfigurationPlan, error) {
+	if source.Identifier == "" || target.Identifier == "" {
+		return ZoneConfigurationPlan{}, ErrMissingZoneID
+	}
+
+	config, err := api.SnapshotZoneConfiguration(ctx, source, SnapshotZoneConfigurationParams{Phases: params.Phases})
+	if err != nil {
+		return ZoneConfigurationPlan{}, err
+	}
+
+	if params.RewriteHostnames {
+		sourceZone, err := api.ZoneDetails(ctx, source.Identifier)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		targetZone, err := api.ZoneDetails(ctx, target.Identifier)
+		if err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+		if config, err = config.RewriteHostnames(sourceZone.Name, targetZone.Name); err != nil {
+			return ZoneConfigurationPlan{}, err
+		}
+	}
+
+	return api.ApplyZoneConfiguration(ctx, target, ApplyZoneConfigurationParams{
+		Configuration:   config,
+		DeletePageRules: params.DeletePageRules,
+		DryRun:          params.DryRun,
+	})
+}
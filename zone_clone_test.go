@@ -0,0 +1,182 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteHostname(t *testing.T) {
+	testCases := map[string]string{
+		`http.host eq "example.com"`:         `http.host eq "example.net"`,
+		`http.host eq "WWW.Example.COM"`:     `http.host eq "WWW.example.net"`,
+		`https://example.com/path`:           `https://example.net/path`,
+		`example.com/*`:                      `example.net/*`,
+		`http.host eq "myexample.com"`:       `http.host eq "myexample.com"`,
+		`http.host eq "example.com.au"`:      `http.host eq "example.com.au"`,
+		`http.host eq "example.community"`:   `http.host eq "example.community"`,
+		`http.host in {"a.example.com" "b"}`: `http.host in {"a.example.net" "b"}`,
+		`example.com.`:                       `example.net.`,
+		`example.com,example.com`:            `example.net,example.net`,
+	}
+
+	for in, want := range testCases {
+		assert.Equal(t, want, rewriteHostname(in, "example.com", "example.net"), in)
+	}
+}
+
+func TestZoneConfigurationRewriteHostnames(t *testing.T) {
+	smart := TieredCacheSmart
+	config := ZoneConfiguration{
+		Rulesets: []Ruleset{{
+			Phase: string(RulesetPhaseHTTPRequestDynamicRedirect),
+			Rules: []RulesetRule{{
+				Action:     string(RulesetRuleActionRedirect),
+				Expression: `http.host eq "old.example.com" and http.request.uri.path contains "<&>"`,
+				ActionParameters: &RulesetRuleActionParameters{
+					FromValue: &RulesetRuleActionParametersFromValue{TargetURL: RulesetRuleActionParametersTargetURL{Value: "https://example.com/"}},
+				},
+			}},
+		}},
+		CustomPages: []CustomPage{{ID: "500_errors", URL: "https://errors.example.com/500.html", State: "customized"}},
+		TieredCache: &smart,
+	}
+
+	rewritten, err := config.RewriteHostnames("example.com", "example.org")
+	require.NoError(t, err)
+	assert.Equal(t, `http.host eq "old.example.org" and http.request.uri.path contains "<&>"`, rewritten.Rulesets[0].Rules[0].Expression)
+	assert.Equal(t, "https://example.org/", rewritten.Rulesets[0].Rules[0].ActionParameters.FromValue.TargetURL.Value)
+	assert.Equal(t, "https://errors.example.org/500.html", rewritten.CustomPages[0].URL)
+	assert.Equal(t, config.TieredCache, rewritten.TieredCache)
+	assert.Nil(t, rewritten.PageRules)
+
+	// The configuration rewritten is left unchanged.
+	assert.Equal(t, `http.host eq "old.example.com" and http.request.uri.path contains "<&>"`, config.Rulesets[0].Rules[0].Expression)
+}
+
+func TestCloneZoneConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const targetZoneID = "target"
+	var requests []string
+
+	handle := func(path string, get func(zoneID string) string) {
+		for _, zoneID := range []string{testZoneID, targetZoneID} {
+			zoneID := zoneID
+			mux.HandleFunc("/zones/"+zoneID+path, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("content-type", "application/json")
+				if r.Method != http.MethodGet {
+					assert.Equal(t, targetZoneID, zoneID, "source zone changed")
+					body, _ := io.ReadAll(r.Body)
+					requests = append(requests, strings.TrimSpace(fmt.Sprintf("%s %s %s", r.Method, path, body)))
+					fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+					return
+				}
+				result := get(zoneID)
+				if result == "" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"success": false, "errors": [{"code": 10000, "message": "not found"}], "messages": [], "result": null}`)
+					return
+				}
+				fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, result)
+			})
+		}
+	}
+	bySource := func(source, target string) func(string) string {
+		return func(zoneID string) string {
+			if zoneID == testZoneID {
+				return source
+			}
+			return target
+		}
+	}
+
+	handle("", bySource(
+		`{"id": "`+testZoneID+`", "name": "example.com"}`,
+		`{"id": "`+targetZoneID+`", "name": "example.net"}`,
+	))
+	handle("/settings", bySource(`[
+		{"id": "ssl", "value": "full", "editable": true},
+		{"id": "min_tls_version", "value": "1.2", "editable": true},
+		{"id": "polish", "value": "lossless", "editable": true},
+		{"id": "advanced_ddos", "value": "on", "editable": false}
+	]`, `[
+		{"id": "ssl", "value": "flexible", "editable": true},
+		{"id": "min_tls_version", "value": "1.2", "editable": true},
+		{"id": "polish", "value": "off", "editable": false}
+	]`))
+	handle("/rulesets/phases/http_request_transform/entrypoint", bySource(`{
+		"id": "rs1",
+		"phase": "http_request_transform",
+		"description": "rewrites",
+		"rules": [{"id": "r1", "version": "2", "action": "rewrite", "expression": "http.host eq \"www.example.com\"", "action_parameters": {"uri": {"path": {"value": "/new"}}}}]
+	}`, ``))
+	handle("/rulesets/phases/http_request_origin/entrypoint", bySource(``, ``))
+	handle("/pagerules", bySource(`[
+		{"id": "p1", "targets": [{"target": "url", "constraint": {"operator": "matches", "value": "example.com/old/*"}}], "actions": [{"id": "forwarding_url", "value": {"url": "https://example.com/new", "status_code": 301}}], "priority": 2, "status": "active"},
+		{"id": "p2", "targets": [{"target": "url", "constraint": {"operator": "matches", "value": "example.com/blog/*"}}], "actions": [{"id": "cache_level", "value": "bypass"}], "priority": 1, "status": "active"}
+	]`, `[
+		{"id": "t1", "targets": [{"target": "url", "constraint": {"operator": "matches", "value": "example.net/old/*"}}], "actions": [{"id": "forwarding_url", "value": {"status_code": 301, "url": "https://example.net/new"}}], "priority": 2, "status": "active"},
+		{"id": "t2", "targets": [{"target": "url", "constraint": {"operator": "matches", "value": "example.net/stale"}}], "actions": [{"id": "cache_level", "value": "bypass"}], "priority": 3, "status": "active"}
+	]`))
+	mux.HandleFunc("/zones/"+targetZoneID+"/pagerules/t2", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" /pagerules/t2")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "t2"}}`)
+	})
+	handle("/managed_headers", bySource(
+		`{"managed_request_headers": [{"id": "add_true_client_ip_headers", "enabled": true}], "managed_response_headers": [{"id": "remove_x-powered-by_header", "enabled": false}]}`,
+		`{"managed_request_headers": [{"id": "add_true_client_ip_headers", "enabled": false}], "managed_response_headers": [{"id": "remove_x-powered-by_header", "enabled": false}]}`,
+	))
+	handle("/argo/tiered_caching", bySource(`{"id": "tiered_caching", "value": "on"}`, `{"id": "tiered_caching", "value": "on"}`))
+	handle("/cache/tiered_cache_smart_topology_enable", bySource(`{"id": "tiered_cache_smart_topology_enable", "value": "off"}`, ``))
+	handle("/cache/variants", bySource(`{"id": "variants", "value": {"webp": ["image/webp"]}}`, ``))
+	handle("/custom_pages", bySource(
+		`[{"id": "500_errors", "url": "https://example.com/500.html", "state": "customized"}, {"id": "basic_challenge", "url": null, "state": "default"}]`,
+		`[{"id": "500_errors", "url": null, "state": "default"}, {"id": "basic_challenge", "url": null, "state": "default"}]`,
+	))
+	handle("/custom_pages/500_errors", bySource(``, ``))
+
+	params := CloneZoneConfigurationParams{
+		Phases:           []RulesetPhase{RulesetPhaseHTTPRequestTransform, RulesetPhaseHTTPRequestOrigin},
+		RewriteHostnames: true,
+		DeletePageRules:  true,
+		DryRun:           true,
+	}
+	plan, err := client.CloneZoneConfiguration(context.Background(), ZoneIdentifier(testZoneID), ZoneIdentifier(targetZoneID), params)
+	require.NoError(t, err)
+	assert.Equal(t, []ZoneConfigurationChange{
+		{Resource: ZoneConfigurationSetting, ID: "ssl", Action: ZoneConfigurationUpdate},
+		{Resource: ZoneConfigurationRuleset, ID: "http_request_transform", Action: ZoneConfigurationCreate},
+		{Resource: ZoneConfigurationPageRule, ID: "example.net/blog/*", Action: ZoneConfigurationCreate},
+		{Resource: ZoneConfigurationPageRule, ID: "example.net/stale", Action: ZoneConfigurationDelete},
+		{Resource: ZoneConfigurationManagedHeader, ID: "add_true_client_ip_headers", Action: ZoneConfigurationUpdate},
+		{Resource: ZoneConfigurationCacheVariants, Action: ZoneConfigurationUpdate},
+		{Resource: ZoneConfigurationCustomPage, ID: "500_errors", Action: ZoneConfigurationUpdate},
+	}, plan.Changes)
+	assert.Equal(t, []ZoneSettingChange{{ID: "polish", Current: "off", Desired: "lossless"}}, plan.ReadOnlySettings)
+	assert.Empty(t, requests)
+
+	params.DryRun = false
+	_, err = client.CloneZoneConfiguration(context.Background(), ZoneIdentifier(testZoneID), ZoneIdentifier(targetZoneID), params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`PATCH /settings {"items":[{"id":"ssl","editable":false,"value":"full","time_remaining":0}]}`,
+		`PUT /rulesets/phases/http_request_transform/entrypoint {"description":"rewrites","rules":[{"action":"rewrite","action_parameters":{"uri":{"path":{"value":"/new"}}},"expression":"http.host eq \"www.example.net\"","ref":"r1"}]}`,
+		`POST /pagerules {"targets":[{"target":"url","constraint":{"operator":"matches","value":"example.net/blog/*"}}],"actions":[{"id":"cache_level","value":"bypass"}],"priority":1,"status":"active","modified_on":"0001-01-01T00:00:00Z","created_on":"0001-01-01T00:00:00Z"}`,
+		`DELETE /pagerules/t2`,
+		`PATCH /managed_headers {"managed_request_headers":[{"id":"add_true_client_ip_headers","enabled":true}],"managed_response_headers":null}`,
+		`PATCH /cache/variants {"value":{"webp":["image/webp"]}}`,
+		`PUT /custom_pages/500_errors {"url":"https://example.net/500.html","state":"customized"}`,
+	}, requests)
+
+	_, err = client.CloneZoneConfiguration(context.Background(), ZoneIdentifier(testZoneID), ZoneIdentifier(""), params)
+	assert.ErrorIs(t, err, ErrMissingZoneID)
+}
@@ -0,0 +1,418 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ZoneSecuritySeverity is the severity of a zone security finding.
+type ZoneSecuritySeverity string
+
+const (
+	ZoneSecuritySeverityLow      ZoneSecuritySeverity = "low"
+	ZoneSecuritySeverityMedium   ZoneSecuritySeverity = "medium"
+	ZoneSecuritySeverityHigh     ZoneSecuritySeverity = "high"
+	ZoneSecuritySeverityCritical ZoneSecuritySeverity = "critical"
+)
+
+// zoneSecuritySeverityRanks orders severities, from the least severe.
+var zoneSecuritySeverityRanks = map[ZoneSecuritySeverity]int{
+	ZoneSecuritySeverityLow:      1,
+	ZoneSecuritySeverityMedium:   2,
+	ZoneSecuritySeverityHigh:     3,
+	ZoneSecuritySeverityCritical: 4,
+}
+
+// AtLeast reports whether the severity is at least min.
+func (s ZoneSecuritySeverity) AtLeast(min ZoneSecuritySeverity) bool {
+	return zoneSecuritySeverityRanks[s] >= zoneSecuritySeverityRanks[min]
+}
+
+// ZoneSecurityCheck identifies a check of a zone security policy.
+type ZoneSecurityCheck string
+
+const (
+	ZoneSecurityCheckMinTLSVersion            ZoneSecurityCheck = "min_tls_version"
+	ZoneSecurityCheckSSLMode                  ZoneSecurityCheck = "ssl_mode"
+	ZoneSecurityCheckAlwaysUseHTTPS           ZoneSecurityCheck = "always_use_https"
+	ZoneSecurityCheckHSTS                     ZoneSecurityCheck = "hsts"
+	ZoneSecurityCheckDNSSEC                   ZoneSecurityCheck = "dnssec"
+	ZoneSecurityCheckManagedWAF               ZoneSecurityCheck = "managed_waf"
+	ZoneSecurityCheckBotFightMode             ZoneSecurityCheck = "bot_fight_mode"
+	ZoneSecurityCheckTotalTLS                 ZoneSecurityCheck = "total_tls"
+	ZoneSecurityCheckAuthenticatedOriginPulls ZoneSecurityCheck = "authenticated_origin_pulls"
+	ZoneSecurityCheckManagedHeaders           ZoneSecurityCheck = "managed_headers"
+)
+
+// zoneSecurityCheckSeverities are the severities of the findings of each
+// check unless the policy sets otherwise.
+var zoneSecurityCheckSeverities = map[ZoneSecurityCheck]ZoneSecuritySeverity{
+	ZoneSecurityCheckMinTLSVersion:            ZoneSecuritySeverityHigh,
+	ZoneSecurityCheckSSLMode:                  ZoneSecuritySeverityHigh,
+	ZoneSecurityCheckAlwaysUseHTTPS:           ZoneSecuritySeverityMedium,
+	ZoneSecurityCheckHSTS:                     ZoneSecuritySeverityMedium,
+	ZoneSecurityCheckDNSSEC:                   ZoneSecuritySeverityMedium,
+	ZoneSecurityCheckManagedWAF:               ZoneSecuritySeverityHigh,
+	ZoneSecurityCheckBotFightMode:             ZoneSecuritySeverityLow,
+	ZoneSecurityCheckTotalTLS:                 ZoneSecuritySeverityLow,
+	ZoneSecurityCheckAuthenticatedOriginPulls: ZoneSecuritySeverityMedium,
+	ZoneSecurityCheckManagedHeaders:           ZoneSecuritySeverityLow,
+}
+
+// zoneSSLModes are the SSL modes of zones, from the least secure.
+var zoneSSLModes = []string{"off", "flexible", "full", "strict"}
+
+// ZoneSecurityPolicy is the security baseline zones are audited against.
+// Only the checks whose fields are set are run.
+type ZoneSecurityPolicy struct {
+	// MinTLSVersion is the lowest minimum TLS version allowed, such as
+	// "1.2".
+	MinTLSVersion string
+	// MinSSLMode is the least secure SSL mode allowed: off, flexible, full
+	// or strict.
+	MinSSLMode                      string
+	RequireAlwaysUseHTTPS           bool
+	RequireHSTS                     bool
+	RequireDNSSEC                   bool
+	RequireBotFightMode             bool
+	RequireTotalTLS                 bool
+	RequireAuthenticatedOriginPulls bool
+	// RequireManagedWAF requires the http_request_firewall_managed
+	// entrypoint ruleset to execute a managed ruleset.
+	RequireManagedWAF bool
+	// ManagedRulesets are the IDs of the managed rulesets which must be
+	// executed. Setting it implies RequireManagedWAF.
+	ManagedRulesets []string
+	// ManagedHeaders are the IDs of the managed transforms which must be
+	// enabled, such as add_security_headers.
+	ManagedHeaders []string
+	// Severities overrides the severity of the findings of checks.
+	Severities map[ZoneSecurityCheck]ZoneSecuritySeverity
+}
+
+// DefaultZoneSecurityPolicy returns a baseline requiring TLS 1.2, full SSL,
+// HTTPS, DNSSEC and the WAF managed rules.
+func DefaultZoneSecurityPolicy() ZoneSecurityPolicy {
+	return ZoneSecurityPolicy{
+		MinTLSVersion:         "1.2",
+		MinSSLMode:            "full",
+		RequireAlwaysUseHTTPS: true,
+		RequireDNSSEC:         true,
+		RequireManagedWAF:     true,
+	}
+}
+
+func (p ZoneSecurityPolicy) severity(check ZoneSecurityCheck) ZoneSecuritySeverity {
+	if s, ok := p.Severities[check]; ok {
+		return s
+	}
+	return zoneSecurityCheckSeverities[check]
+}
+
+// ZoneSecurityFinding is a way a zone doesn't follow a security policy.
+type ZoneSecurityFinding struct {
+	ZoneID   string
+	ZoneName string
+	Check    ZoneSecurityCheck
+	Severity ZoneSecuritySeverity
+	Message  string
+	Current  string
+	Expected string
+	// Err is set when the check couldn't be run because fetching the
+	// configuration it needs failed.
+	Err error
+}
+
+// ZoneSecuritySnapshot holds the configuration of a zone audited by
+// EvaluateZoneSecurity. Configuration which wasn't fetched is nil.
+type ZoneSecuritySnapshot struct {
+	Zone                     Zone
+	Settings                 []ZoneSetting
+	SSL                      *ZoneSSLSetting
+	BotManagement            *BotManagement
+	DNSSEC                   *ZoneDNSSEC
+	TotalTLS                 *TotalTLS
+	AuthenticatedOriginPulls *AuthenticatedOriginPulls
+	// ManagedFirewall is the http_request_firewall_managed entrypoint
+	// ruleset, with no rules when the zone has none.
+	ManagedFirewall *Ruleset
+	ManagedHeaders  *ManagedHeaders
+	// Errors holds why the configuration needed by checks couldn't be
+	// fetched.
+	Errors map[ZoneSecurityCheck]error
+}
+
+// ZoneSecurityReport holds the findings of a zone.
+type ZoneSecurityReport struct {
+	ZoneID   string
+	ZoneName string
+	Findings []ZoneSecurityFinding
+}
+
+// ZoneSecurityAudit is the outcome of AuditZoneSecurity.
+type ZoneSecurityAudit struct {
+	Reports []ZoneSecurityReport
+}
+
+// Findings returns the findings of every zone with at least the given
+// severity.
+func (a ZoneSecurityAudit) Findings(min ZoneSecuritySeverity) []ZoneSecurityFinding {
+	var findings []ZoneSecurityFinding
+	for _, r := range a.Reports {
+		for _, f := range r.Findings {
+			if f.Severity.AtLeast(min) {
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings
+}
+
+// AuditZoneSecurity audits a zone, or every zone of an account, against a
+// security policy. Zones are audited one at a time; failing to fetch the
+// configuration of a zone gives findings with Err set rather than an
+// error.
+func (api *API) AuditZoneSecurity(ctx context.Context, rc *ResourceContainer, policy ZoneSecurityPolicy) (ZoneSecurityAudit, error) {
+	var zones []Zone
+	switch rc.Level {
+	case ZoneRouteLevel:
+		if rc.Identifier == "" {
+			return ZoneSecurityAudit{}, ErrMissingZoneID
+		}
+		zone, err := api.ZoneDetails(ctx, rc.Identifier)
+		if err != nil {
+			return ZoneSecurityAudit{}, err
+		}
+		zones = []Zone{zone}
+	case AccountRouteLevel:
+		if rc.Identifier == "" {
+			return ZoneSecurityAudit{}, ErrMissingAccountID
+		}
+		res, err := api.ListZonesContext(ctx, WithZoneFilters("", rc.Identifier, ""))
+		if err != nil {
+			return ZoneSecurityAudit{}, err
+		}
+		zones = res.Result
+	default:
+		return ZoneSecurityAudit{}, fmt.Errorf(errInvalidResourceContainerAccess, rc.Level)
+	}
+
+	var audit ZoneSecurityAudit
+	for _, zone := range zones {
+		if err := ctx.Err(); err != nil {
+			return ZoneSecurityAudit{}, err
+		}
+		snapshot := api.zoneSecuritySnapshot(ctx, zone, policy)
+		audit.Reports = append(audit.Reports, ZoneSecurityReport{
+			ZoneID:   zone.ID,
+			ZoneName: zone.Name,
+			Findings: EvaluateZoneSecurity(snapshot, policy),
+		})
+	}
+
+	return audit, nil
+}
+
+// zoneSecuritySnapshot fetches the configuration of a zone needed by the
+// checks of the policy.
+func (api *API) zoneSecuritySnapshot(ctx context.Context, zone Zone, policy ZoneSecurityPolicy) ZoneSecuritySnapshot {
+	s := ZoneSecuritySnapshot{Zone: zone, Errors: map[ZoneSecurityCheck]error{}}
+	rc := ZoneIdentifier(zone.ID)
+	fail := func(err error, checks ...ZoneSecurityCheck) {
+		for _, c := range checks {
+			s.Errors[c] = err
+		}
+	}
+
+	if policy.MinTLSVersion != "" || policy.RequireAlwaysUseHTTPS || policy.RequireHSTS {
+		if res, err := api.ZoneSettings(ctx, zone.ID); err != nil {
+			fail(err, ZoneSecurityCheckMinTLSVersion, ZoneSecurityCheckAlwaysUseHTTPS, ZoneSecurityCheckHSTS)
+		} else {
+			s.Settings = res.Result
+		}
+	}
+	if policy.MinSSLMode != "" {
+		if ssl, err := api.ZoneSSLSettings(ctx, zone.ID); err != nil {
+			fail(err, ZoneSecurityCheckSSLMode)
+		} else {
+			s.SSL = &ssl
+		}
+	}
+	if policy.RequireBotFightMode {
+		if bm, err := api.GetBotManagement(ctx, rc); err != nil {
+			fail(err, ZoneSecurityCheckBotFightMode)
+		} else {
+			s.BotManagement = &bm
+		}
+	}
+	if policy.RequireDNSSEC {
+		if dnssec, err := api.ZoneDNSSECSetting(ctx, zone.ID); err != nil {
+			fail(err, ZoneSecurityCheckDNSSEC)
+		} else {
+			s.DNSSEC = &dnssec
+		}
+	}
+	if policy.RequireTotalTLS {
+		if tls, err := api.GetTotalTLS(ctx, rc); err != nil {
+			fail(err, ZoneSecurityCheckTotalTLS)
+		} else {
+			s.TotalTLS = &tls
+		}
+	}
+	if policy.RequireAuthenticatedOriginPulls {
+		if aop, err := api.GetAuthenticatedOriginPullsStatus(ctx, zone.ID); err != nil {
+			fail(err, ZoneSecurityCheckAuthenticatedOriginPulls)
+		} else {
+			s.AuthenticatedOriginPulls = &aop
+		}
+	}
+	if policy.RequireManagedWAF || len(policy.ManagedRulesets) > 0 {
+		ruleset, err := api.GetEntrypointRuleset(ctx, rc, string(RulesetPhaseHTTPRequestFirewallManaged))
+		var notFound *NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			fail(err, ZoneSecurityCheckManagedWAF)
+		} else {
+			s.ManagedFirewall = &ruleset
+		}
+	}
+	if len(policy.ManagedHeaders) > 0 {
+		if headers, err := api.ListZoneManagedHeaders(ctx, rc, ListManagedHeadersParams{}); err != nil {
+			fail(err, ZoneSecurityCheckManagedHeaders)
+		} else {
+			s.ManagedHeaders = &headers
+		}
+	}
+
+	return s
+}
+
+// EvaluateZoneSecurity checks the configuration of a zone against a
+// security policy. Checks whose configuration is missing from the snapshot
+// are skipped, unless fetching it failed.
+func EvaluateZoneSecurity(s ZoneSecuritySnapshot, policy ZoneSecurityPolicy) []ZoneSecurityFinding {
+	var findings []ZoneSecurityFinding
+	add := func(check ZoneSecurityCheck, current, expected, message string) {
+		findings = append(findings, ZoneSecurityFinding{
+			ZoneID:   s.Zone.ID,
+			ZoneName: s.Zone.Name,
+			Check:    check,
+			Severity: policy.severity(check),
+			Message:  message,
+			Current:  current,
+			Expected: expected,
+		})
+	}
+	// available reports whether a check can run, adding a finding when
+	// its configuration couldn't be fetched.
+	available := func(check ZoneSecurityCheck, fetched bool) bool {
+		if err, ok := s.Errors[check]; ok {
+			add(check, "", "", "could not be checked")
+			findings[len(findings)-1].Err = err
+			return false
+		}
+		return fetched
+	}
+
+	settings := map[string]interface{}{}
+	for _, setting := range s.Settings {
+		settings[setting.ID] = setting.Value
+	}
+
+	if policy.MinTLSVersion != "" && available(ZoneSecurityCheckMinTLSVersion, s.Settings != nil) {
+		current, _ := settings["min_tls_version"].(string)
+		if current < policy.MinTLSVersion {
+			add(ZoneSecurityCheckMinTLSVersion, current, policy.MinTLSVersion,
+				fmt.Sprintf("minimum TLS version is %s, lower than %s", current, policy.MinTLSVersion))
+		}
+	}
+
+	if policy.MinSSLMode != "" && available(ZoneSecurityCheckSSLMode, s.SSL != nil) {
+		if indexOf(zoneSSLModes, s.SSL.Value) < indexOf(zoneSSLModes, policy.MinSSLMode) {
+			add(ZoneSecurityCheckSSLMode, s.SSL.Value, policy.MinSSLMode,
+				fmt.Sprintf("SSL mode is %s, less secure than %s", s.SSL.Value, policy.MinSSLMode))
+		}
+	}
+
+	if policy.RequireAlwaysUseHTTPS && available(ZoneSecurityCheckAlwaysUseHTTPS, s.Settings != nil) {
+		if current, _ := settings["always_use_https"].(string); current != "on" {
+			add(ZoneSecurityCheckAlwaysUseHTTPS, current, "on", "Always Use HTTPS is off")
+		}
+	}
+
+	if policy.RequireHSTS && available(ZoneSecurityCheckHSTS, s.Settings != nil) {
+		header, _ := settings["security_header"].(map[string]interface{})
+		hsts, _ := header["strict_transport_security"].(map[string]interface{})
+		if enabled, _ := hsts["enabled"].(bool); !enabled {
+			add(ZoneSecurityCheckHSTS, "off", "on", "HTTP Strict Transport Security is off")
+		}
+	}
+
+	if policy.RequireDNSSEC && available(ZoneSecurityCheckDNSSEC, s.DNSSEC != nil) {
+		if s.DNSSEC.Status != "active" {
+			add(ZoneSecurityCheckDNSSEC, s.DNSSEC.Status, "active", fmt.Sprintf("DNSSEC is %s", s.DNSSEC.Status))
+		}
+	}
+
+	if (policy.RequireManagedWAF || len(policy.ManagedRulesets) > 0) && available(ZoneSecurityCheckManagedWAF, s.ManagedFirewall != nil) {
+		var executed []string
+		for _, r := range s.ManagedFirewall.Rules {
+			if r.Action == string(RulesetRuleActionExecute) && r.ActionParameters != nil && (r.Enabled == nil || *r.Enabled) {
+				executed = append(executed, r.ActionParameters.ID)
+			}
+		}
+		if len(executed) == 0 {
+			add(ZoneSecurityCheckManagedWAF, "", strings.Join(policy.ManagedRulesets, ", "), "no managed ruleset is deployed")
+		} else {
+			for _, id := range policy.ManagedRulesets {
+				if !contains(executed, id) {
+					add(ZoneSecurityCheckManagedWAF, strings.Join(executed, ", "), id, fmt.Sprintf("managed ruleset %s is not deployed", id))
+				}
+			}
+		}
+	}
+
+	if policy.RequireBotFightMode && available(ZoneSecurityCheckBotFightMode, s.BotManagement != nil) {
+		if s.BotManagement.FightMode == nil || !*s.BotManagement.FightMode {
+			add(ZoneSecurityCheckBotFightMode, "off", "on", "Bot Fight Mode is off")
+		}
+	}
+
+	if policy.RequireTotalTLS && available(ZoneSecurityCheckTotalTLS, s.TotalTLS != nil) {
+		if s.TotalTLS.Enabled == nil || !*s.TotalTLS.Enabled {
+			add(ZoneSecurityCheckTotalTLS, "off", "on", "Total TLS is off")
+		}
+	}
+
+	if policy.RequireAuthenticatedOriginPulls && available(ZoneSecurityCheckAuthenticatedOriginPulls, s.AuthenticatedOriginPulls != nil) {
+		if s.AuthenticatedOriginPulls.Value != "on" {
+			add(ZoneSecurityCheckAuthenticatedOriginPulls, s.AuthenticatedOriginPulls.Value, "on", "Authenticated Origin Pulls is off")
+		}
+	}
+
+	if len(policy.ManagedHeaders) > 0 && available(ZoneSecurityCheckManagedHeaders, s.ManagedHeaders != nil) {
+		enabled := map[string]bool{}
+		for _, headers := range [][]ManagedHeader{s.ManagedHeaders.ManagedRequestHeaders, s.ManagedHeaders.ManagedResponseHeaders} {
+			for _, h := range headers {
+				enabled[h.ID] = enabled[h.ID] || h.Enabled
+			}
+		}
+		for _, id := range policy.ManagedHeaders {
+			if !enabled[id] {
+				add(ZoneSecurityCheckManagedHeaders, "off", "on", fmt.Sprintf("managed transform %s is off", id))
+			}
+		}
+	}
+
+	return findings
+}
+
+func indexOf(values []string, v string) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,111 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateZoneSecurity(t *testing.T) {
+	snapshot := ZoneSecuritySnapshot{
+		Zone: Zone{ID: testZoneID, Name: "example.com"},
+		Settings: []ZoneSetting{
+			{ID: "min_tls_version", Value: "1.0"},
+			{ID: "always_use_https", Value: "on"},
+			{ID: "security_header", Value: map[string]interface{}{
+				"strict_transport_security": map[string]interface{}{"enabled": true, "max_age": float64(31536000)},
+			}},
+		},
+		SSL:           &ZoneSSLSetting{Value: "flexible"},
+		BotManagement: &BotManagement{FightMode: BoolPtr(true)},
+		TotalTLS:      &TotalTLS{Enabled: BoolPtr(false)},
+		ManagedFirewall: &Ruleset{Rules: []RulesetRule{
+			{Action: "execute", ActionParameters: &RulesetRuleActionParameters{ID: "efb7b8c949ac4650a09736fc376e9aee"}},
+			{Action: "execute", ActionParameters: &RulesetRuleActionParameters{ID: "4814384a9e5d4991b9815dcfc25d2f1f"}, Enabled: BoolPtr(false)},
+		}},
+		ManagedHeaders: &ManagedHeaders{ManagedResponseHeaders: []ManagedHeader{{ID: "add_security_headers", Enabled: true}}},
+		Errors:         map[ZoneSecurityCheck]error{ZoneSecurityCheckDNSSEC: errors.New("boom")},
+	}
+
+	policy := DefaultZoneSecurityPolicy()
+	policy.RequireHSTS = true
+	policy.RequireBotFightMode = true
+	policy.RequireTotalTLS = true
+	policy.ManagedRulesets = []string{"efb7b8c949ac4650a09736fc376e9aee", "4814384a9e5d4991b9815dcfc25d2f1f"}
+	policy.ManagedHeaders = []string{"add_security_headers", "remove_visitor_ip_headers"}
+	policy.Severities = map[ZoneSecurityCheck]ZoneSecuritySeverity{ZoneSecurityCheckMinTLSVersion: ZoneSecuritySeverityCritical}
+
+	findings := EvaluateZoneSecurity(snapshot, policy)
+
+	checks := make([]ZoneSecurityCheck, 0, len(findings))
+	for _, f := range findings {
+		assert.Equal(t, "example.com", f.ZoneName)
+		checks = append(checks, f.Check)
+	}
+	assert.Equal(t, []ZoneSecurityCheck{
+		ZoneSecurityCheckMinTLSVersion,
+		ZoneSecurityCheckSSLMode,
+		ZoneSecurityCheckDNSSEC,
+		ZoneSecurityCheckManagedWAF,
+		ZoneSecurityCheckTotalTLS,
+		ZoneSecurityCheckManagedHeaders,
+	}, checks)
+
+	assert.Equal(t, ZoneSecuritySeverityCritical, findings[0].Severity)
+	assert.Equal(t, "1.0", findings[0].Current)
+	assert.Equal(t, "1.2", findings[0].Expected)
+	assert.Equal(t, ZoneSecuritySeverityHigh, findings[1].Severity)
+	assert.EqualError(t, findings[2].Err, "boom")
+	assert.Equal(t, "managed ruleset 4814384a9e5d4991b9815dcfc25d2f1f is not deployed", findings[3].Message)
+	assert.Equal(t, "managed transform remove_visitor_ip_headers is off", findings[5].Message)
+}
+
+func TestAuditZoneSecurity(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/zones/"+testZoneID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "%s", "name": "example.com"}}`, testZoneID)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/settings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": [
+			{"id": "min_tls_version", "value": "1.2"},
+			{"id": "always_use_https", "value": "off"}
+		]}`)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/settings/ssl", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "ssl", "value": "strict"}}`)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/dnssec", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"status": "disabled"}}`)
+	})
+	mux.HandleFunc("/zones/"+testZoneID+"/rulesets/phases/http_request_firewall_managed/entrypoint", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 10003, "message": "could not find entrypoint ruleset"}], "messages": [], "result": null}`)
+	})
+
+	audit, err := client.AuditZoneSecurity(context.Background(), ZoneIdentifier(testZoneID), DefaultZoneSecurityPolicy())
+	require.NoError(t, err)
+	require.Len(t, audit.Reports, 1)
+	assert.Equal(t, "example.com", audit.Reports[0].ZoneName)
+
+	findings := audit.Findings(ZoneSecuritySeverityMedium)
+	require.Len(t, findings, 3)
+	assert.Equal(t, ZoneSecurityCheckAlwaysUseHTTPS, findings[0].Check)
+	assert.Equal(t, ZoneSecurityCheckDNSSEC, findings[1].Check)
+	assert.Equal(t, "disabled", findings[1].Current)
+	assert.Equal(t, ZoneSecurityCheckManagedWAF, findings[2].Check)
+	assert.NoError(t, findings[2].Err)
+
+	assert.Len(t, audit.Findings(ZoneSecuritySeverityHigh), 1)
+}
@@ -0,0 +1,238 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+// ZoneSettingsConfig holds zone settings as typed values. Settings left nil
+// aren't managed: they are neither compared nor changed.
+type ZoneSettingsConfig struct {
+	ZeroRTT                 *string                    `json:"0rtt,omitempty"`
+	AlwaysOnline            *string                    `json:"always_online,omitempty"`
+	AlwaysUseHTTPS          *string                    `json:"always_use_https,omitempty"`
+	AutomaticHTTPSRewrites  *string                    `json:"automatic_https_rewrites,omitempty"`
+	Brotli                  *string                    `json:"brotli,omitempty"`
+	BrowserCacheTTL         *int                       `json:"browser_cache_ttl,omitempty"`
+	BrowserCheck            *string                    `json:"browser_check,omitempty"`
+	CacheLevel              *string                    `json:"cache_level,omitempty"`
+	ChallengeTTL            *int                       `json:"challenge_ttl,omitempty"`
+	DevelopmentMode         *string                    `json:"development_mode,omitempty"`
+	EarlyHints              *string                    `json:"early_hints,omitempty"`
+	EmailObfuscation        *string                    `json:"email_obfuscation,omitempty"`
+	HotlinkProtection       *string                    `json:"hotlink_protection,omitempty"`
+	HTTP2                   *string                    `json:"http2,omitempty"`
+	HTTP3                   *string                    `json:"http3,omitempty"`
+	IPGeolocation           *string                    `json:"ip_geolocation,omitempty"`
+	IPv6                    *string                    `json:"ipv6,omitempty"`
+	MinTLSVersion           *string                    `json:"min_tls_version,omitempty"`
+	Minify                  *ZoneMinifySetting         `json:"minify,omitempty"`
+	OpportunisticEncryption *string                    `json:"opportunistic_encryption,omitempty"`
+	OpportunisticOnion      *string                    `json:"opportunistic_onion,omitempty"`
+	Polish                  *string                    `json:"polish,omitempty"`
+	RocketLoader            *string                    `json:"rocket_loader,omitempty"`
+	SecurityHeader          *ZoneSecurityHeaderSetting `json:"security_header,omitempty"`
+	SecurityLevel           *string                    `json:"security_level,omitempty"`
+	ServerSideExclude       *string                    `json:"server_side_exclude,omitempty"`
+	SSL                     *string                    `json:"ssl,omitempty"`
+	TLS13                   *string                    `json:"tls_1_3,omitempty"`
+	TLSClientAuth           *string                    `json:"tls_client_auth,omitempty"`
+	WAF                     *string                    `json:"waf,omitempty"`
+	WebP                    *string                    `json:"webp,omitempty"`
+	WebSockets              *string                    `json:"websockets,omitempty"`
+}
+
+// ZoneMinifySetting is the value of the minify zone setting. Each field is
+// "on" or "off".
+type ZoneMinifySetting struct {
+	CSS  string `json:"css,omitempty"`
+	HTML string `json:"html,omitempty"`
+	JS   string `json:"js,omitempty"`
+}
+
+// ZoneSecurityHeaderSetting is the value of the security_header zone
+// setting.
+type ZoneSecurityHeaderSetting struct {
+	StrictTransportSecurity ZoneStrictTransportSecurity `json:"strict_transport_security"`
+}
+
+// ZoneStrictTransportSecurity configures the Strict-Transport-Security
+// header sent by a zone.
+type ZoneStrictTransportSecurity struct {
+	Enabled           bool `json:"enabled"`
+	MaxAge            int  `json:"max_age"`
+	IncludeSubdomains bool `json:"include_subdomains"`
+	Preload           bool `json:"preload"`
+	Nosniff           bool `json:"nosniff"`
+}
+
+// DecodeZoneSettings decodes the settings returned by ZoneSettings.
+// Settings ZoneSettingsConfig doesn't hold are ignored.
+func DecodeZoneSettings(settings []ZoneSetting) (ZoneSettingsConfig, error) {
+	values := make(map[string]interface{}, len(settings))
+	for _, s := range settings {
+		values[s.ID] = s.Value
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return ZoneSettingsConfig{}, err
+	}
+	var config ZoneSettingsConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return ZoneSettingsConfig{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return config, nil
+}
+
+// zoneSettingsConfigValues returns the values of the settings set in a
+// config by setting ID, as the API encodes them.
+func zoneSettingsConfigValues(config ZoneSettingsConfig) (map[string]interface{}, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return values, nil
+}
+
+// ZoneSettingChange is a setting whose value differs from the desired one.
+type ZoneSettingChange struct {
+	ID       string
+	Current  interface{}
+	Desired  interface{}
+	Editable bool
+}
+
+// DiffZoneSettings returns the settings set in desired whose current value
+// differs, sorted by ID. Settings with object values only differ when a
+// field set in desired differs. Settings missing from current are
+// returned with a nil Current value and not editable.
+func DiffZoneSettings(current []ZoneSetting, desired ZoneSettingsConfig) ([]ZoneSettingChange, error) {
+	values, err := zoneSettingsConfigValues(desired)
+	if err != nil {
+		return nil, err
+	}
+	return diffZoneSettingValues(current, values)
+}
+
+// diffZoneSettingValues compares settings with the desired values by
+// setting ID, encoded as the API does.
+func diffZoneSettingValues(current []ZoneSetting, values map[string]interface{}) ([]ZoneSettingChange, error) {
+	currentByID := make(map[string]ZoneSetting, len(current))
+	for _, s := range current {
+		currentByID[s.ID] = s
+	}
+
+	var changes []ZoneSettingChange
+	for id, value := range values {
+		s, ok := currentByID[id]
+		if ok {
+			// Normalize the current value the way the desired one was, so
+			// that numbers compare equal.
+			normalized, err := normalizeZoneSettingValue(s.Value)
+			if err != nil {
+				return nil, err
+			}
+			if zoneSettingValueMatches(normalized, value) {
+				continue
+			}
+		}
+		changes = append(changes, ZoneSettingChange{ID: id, Current: s.Value, Desired: value, Editable: ok && s.Editable})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, nil
+}
+
+func normalizeZoneSettingValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+	return normalized, nil
+}
+
+// zoneSettingValueMatches reports whether current has the desired value,
+// only comparing the fields of objects set in desired.
+func zoneSettingValueMatches(current, desired interface{}) bool {
+	desiredObject, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(current, desired)
+	}
+	currentObject, ok := current.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for k, v := range desiredObject {
+		if !zoneSettingValueMatches(currentObject[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+type ApplyZoneSettingsParams struct {
+	Config ZoneSettingsConfig
+	// DryRun only computes the changes.
+	DryRun bool
+}
+
+// ZoneSettingsApplyResult describes the outcome of ApplyZoneSettings.
+type ZoneSettingsApplyResult struct {
+	// Changed holds the settings changed, or to change on dry runs.
+	Changed []ZoneSettingChange
+	// ReadOnly holds the settings which differ but can't be edited,
+	// including settings the zone doesn't have.
+	ReadOnly []ZoneSettingChange
+}
+
+// ApplyZoneSettings makes the settings of a zone match a config, patching
+// only the editable settings which differ in a single request.
+func (api *API) ApplyZoneSettings(ctx context.Context, rc *ResourceContainer, params ApplyZoneSettingsParams) (ZoneSettingsApplyResult, error) {
+	if rc.Identifier == "" {
+		return ZoneSettingsApplyResult{}, ErrMissingZoneID
+	}
+
+	current, err := api.ZoneSettings(ctx, rc.Identifier)
+	if err != nil {
+		return ZoneSettingsApplyResult{}, err
+	}
+
+	changes, err := DiffZoneSettings(current.Result, params.Config)
+	if err != nil {
+		return ZoneSettingsApplyResult{}, err
+	}
+
+	var result ZoneSettingsApplyResult
+	var items []ZoneSetting
+	for _, c := range changes {
+		if !c.Editable {
+			result.ReadOnly = append(result.ReadOnly, c)
+			continue
+		}
+		result.Changed = append(result.Changed, c)
+		items = append(items, ZoneSetting{ID: c.ID, Value: c.Desired})
+	}
+
+	if params.DryRun || len(items) == 0 {
+		return result, nil
+	}
+
+	if _, err := api.UpdateZoneSettings(ctx, rc.Identifier, items); err != nil {
+		return ZoneSettingsApplyResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const zoneSettingsConfigResponse = `{
+	"success": true,
+	"errors": [],
+	"messages": [],
+	"result": [
+		{"id": "min_tls_version", "value": "1.0", "editable": true},
+		{"id": "ssl", "value": "full", "editable": true},
+		{"id": "browser_cache_ttl", "value": 14400, "editable": true},
+		{"id": "minify", "value": {"css": "on", "html": "off", "js": "off"}, "editable": true},
+		{"id": "security_header", "value": {"strict_transport_security": {"enabled": false, "max_age": 0, "include_subdomains": false, "preload": false, "nosniff": false}}, "editable": true},
+		{"id": "waf", "value": "off", "editable": false},
+		{"id": "unknown_setting", "value": "on", "editable": true}
+	]
+}`
+
+func TestDecodeZoneSettings(t *testing.T) {
+	config, err := DecodeZoneSettings([]ZoneSetting{
+		{ID: "min_tls_version", Value: "1.2"},
+		{ID: "browser_cache_ttl", Value: float64(14400)},
+		{ID: "minify", Value: map[string]interface{}{"css": "on", "html": "off", "js": "on"}},
+		{ID: "security_header", Value: map[string]interface{}{
+			"strict_transport_security": map[string]interface{}{"enabled": true, "max_age": float64(31536000)},
+		}},
+		{ID: "unknown_setting", Value: "on"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ZoneSettingsConfig{
+		MinTLSVersion:   StringPtr("1.2"),
+		BrowserCacheTTL: IntPtr(14400),
+		Minify:          &ZoneMinifySetting{CSS: "on", HTML: "off", JS: "on"},
+		SecurityHeader: &ZoneSecurityHeaderSetting{
+			StrictTransportSecurity: ZoneStrictTransportSecurity{Enabled: true, MaxAge: 31536000},
+		},
+	}, config)
+}
+
+func TestApplyZoneSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var patched string
+	mux.HandleFunc("/zones/"+testZoneID+"/settings", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, zoneSettingsConfigResponse)
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			patched = string(body)
+			fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+
+	params := ApplyZoneSettingsParams{
+		Config: ZoneSettingsConfig{
+			MinTLSVersion:   StringPtr("1.2"),
+			SSL:             StringPtr("full"),
+			BrowserCacheTTL: IntPtr(14400),
+			Minify:          &ZoneMinifySetting{CSS: "on"},
+			SecurityHeader: &ZoneSecurityHeaderSetting{
+				StrictTransportSecurity: ZoneStrictTransportSecurity{Enabled: true, MaxAge: 31536000},
+			},
+			WAF:   StringPtr("on"),
+			HTTP3: StringPtr("on"),
+		},
+		DryRun: true,
+	}
+
+	result, err := client.ApplyZoneSettings(context.Background(), ZoneIdentifier(testZoneID), params)
+	require.NoError(t, err)
+
+	changed := make([]string, 0, len(result.Changed))
+	for _, c := range result.Changed {
+		changed = append(changed, c.ID)
+	}
+	assert.Equal(t, []string{"min_tls_version", "security_header"}, changed)
+	assert.Equal(t, "1.0", result.Changed[0].Current)
+	assert.Equal(t, "1.2", result.Changed[0].Desired)
+
+	readOnly := make([]string, 0, len(result.ReadOnly))
+	for _, c := range result.ReadOnly {
+		readOnly = append(readOnly, c.ID)
+	}
+	assert.Equal(t, []string{"http3", "waf"}, readOnly)
+	assert.Empty(t, patched)
+
+	params.DryRun = false
+	_, err = client.ApplyZoneSettings(context.Background(), ZoneIdentifier(testZoneID), params)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items": [
+		{"id": "min_tls_version", "editable": false, "value": "1.2", "time_remaining": 0},
+		{"id": "security_header", "editable": false, "value": {"strict_transport_security": {"enabled": true, "max_age": 31536000, "include_subdomains": false, "preload": false, "nosniff": false}}, "time_remaining": 0}
+	]}`, patched)
+}